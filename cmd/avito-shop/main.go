@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/grpc"
 	"github.com/azaliaz/avito-shop/internal/facade/rest"
 	"github.com/azaliaz/avito-shop/internal/storage"
 	"github.com/azaliaz/avito-shop/pkg/config"
@@ -16,6 +17,7 @@ type Config struct {
 	App     application.Config `envPrefix:"APP_" yaml:"app"`
 	Storage storage.Config     `envPrefix:"STORAGE_" yaml:"storage"`
 	Rest    rest.Config        `envPrefix:"REST_" yaml:"rest"`
+	Grpc    grpc.Config        `envPrefix:"GRPC_" yaml:"grpc"`
 }
 
 func main() {
@@ -40,9 +42,10 @@ func main() {
 	repo := storage.NewService(db, logger)
 	app := application.NewService(logger, &cfg.App, repo)
 	api := rest.NewAPI(logger, &cfg.Rest, app)
+	grpcAPI := grpc.NewAPI(logger, &cfg.Grpc, app)
 
 	mgr := service.NewManager(logger)
-	mgr.AddService(db, app, api)
+	mgr.AddService(db, app, api, grpcAPI)
 
 	ctx := context.Background()
 	if err := mgr.Run(ctx); err != nil {
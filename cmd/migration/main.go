@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"github.com/azaliaz/avito-shop/internal/storage"
 	"github.com/azaliaz/avito-shop/migrations"
 	"github.com/azaliaz/avito-shop/pkg/config"
@@ -12,6 +15,8 @@ import (
 type Config struct {
 	DBConfig storage.Config `envPrefix:"DB_" yaml:"db-config"`
 	Path     string         `env:"PATH" yaml:"path"`
+	// Admin is a registered username to grant the admin role after migrating.
+	Admin string `env:"ADMIN" yaml:"admin"`
 }
 
 func main() {
@@ -22,6 +27,7 @@ func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, opts))
 	/* Configuring flags */
 	configFile := flag.String("config-file", "none", "config file")
+	admin := flag.String("admin", "", "username to grant the admin role")
 	flag.Parse()
 
 	/* Parsing config */
@@ -31,10 +37,38 @@ func main() {
 		logger.Error("config parse error:", slog.String("err", err.Error()))
 		os.Exit(1)
 	}
+	if *admin != "" {
+		cfg.Admin = *admin
+	}
 
 	if err := migrations.PostgresMigrate(cfg.DBConfig.UrlPostgres()); err != nil {
 		logger.Error("migration error", slog.String("err", err.Error()))
 		os.Exit(1)
 	}
 	logger.Info("migration completed", slog.String("path", cfg.Path))
+
+	if cfg.Admin != "" {
+		if err := grantAdmin(&cfg.DBConfig, logger, cfg.Admin); err != nil {
+			logger.Error("grant admin error", slog.String("err", err.Error()))
+			os.Exit(1)
+		}
+		logger.Info("admin role granted", slog.String("username", cfg.Admin))
+	}
+}
+
+func grantAdmin(config *storage.Config, logger *slog.Logger, username string) error {
+	db := storage.NewDB(config, logger)
+	if err := db.Init(); err != nil {
+		return err
+	}
+	defer db.Stop()
+	repo := storage.NewService(db, logger)
+	_, err := repo.SetUserRole(context.Background(), &storage.SetUserRoleRequest{
+		UserName: username,
+		Role:     "admin",
+	})
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return fmt.Errorf("user %s is not registered", username)
+	}
+	return err
 }
@@ -0,0 +1,65 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"strings"
+	"unicode/utf8"
+)
+
+// AdjustBalance credits or debits any user's coins. The adjustment comes from
+// the system account and keeps its reason as the memo, so the user sees it in
+// their history.
+func (s *Service) AdjustBalance(ctx context.Context, request *AdjustBalanceRequest) (_ *AdjustBalanceResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditAdjustBalance, request.UserName, &err)
+
+	if err := authorize(request.Principal, permManageBalances); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(request.Reason)
+	var v validator
+	v.username("username", request.UserName)
+	v.check(request.Amount != 0, "amount", RuleRequired, "must not be zero")
+	v.check(request.Amount >= -maxAmount && request.Amount <= maxAmount, "amount", RuleMax,
+		fmt.Sprintf("must be at most %d", maxAmount))
+	v.check(!request.Force || request.Amount < 0, "force", RuleNotAllowed, "is only allowed for a debit")
+	switch {
+	case reason == "":
+		v.add(FieldError{Field: "reason", Rule: RuleRequired, Message: "is required", Err: ErrReasonRequired})
+	case utf8.RuneCountInString(reason) > maxMemoLength:
+		v.add(FieldError{Field: "reason", Rule: RuleMax, Err: ErrReasonTooLong,
+			Message: fmt.Sprintf("must be at most %d characters long", maxMemoLength)})
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if len(request.IdempotencyKey) > maxIdempotencyKeyLength {
+		return nil, ErrIdempotencyKeyTooLong
+	}
+	res, err := s.db.AdjustBalance(ctx, &storage.AdjustBalanceRequest{
+		AdminId:           request.Principal.UserId,
+		UserName:          request.UserName,
+		Amount:            request.Amount,
+		Reason:            reason,
+		Force:             request.Force,
+		IdempotencyKey:    request.IdempotencyKey,
+		IdempotencyKeyTTL: s.config.idempotencyKeyTTL(),
+	})
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		return nil, ErrUserNotFound
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		return nil, ErrInsufficientFunds
+	case errors.Is(err, storage.ErrIdempotencyKeyReused):
+		return nil, ErrIdempotencyKeyReused
+	case err != nil:
+		return nil, fmt.Errorf("error adjust balance in db: %w", err)
+	}
+	return &AdjustBalanceResponse{
+		Balance:  res.Balance,
+		Replayed: res.Replayed,
+	}, nil
+}
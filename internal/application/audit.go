@@ -0,0 +1,121 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"log/slog"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// WithClientIP attaches the address a request came from to ctx, the facades
+// call it once per request so that the address ends up in the audit log.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	actor := storage.ActorFromContext(ctx)
+	actor.IP = ip
+	return storage.WithActor(ctx, actor)
+}
+
+// withPrincipal makes the principal the actor of the storage calls made with
+// the returned context.
+func withPrincipal(ctx context.Context, principal *Principal) context.Context {
+	if principal == nil {
+		return ctx
+	}
+	actor := storage.ActorFromContext(ctx)
+	actor.UserId = principal.UserId
+	return storage.WithActor(ctx, actor)
+}
+
+// auditFailure records the operation as failed if *errp is set when the
+// operation returns. Successful operations are recorded by the storage in the
+// same transaction as their changes.
+func (s *Service) auditFailure(ctx context.Context, action, target string, errp *error) {
+	if *errp == nil {
+		return
+	}
+	err := s.db.RecordAuditEvent(ctx, &storage.AuditEvent{
+		Action:  action,
+		Target:  target,
+		Outcome: storage.AuditFailure,
+		Details: map[string]any{"error": (*errp).Error()},
+	})
+	if err != nil {
+		s.log.Error("record audit event", slog.String("action", action), slog.String("err", err.Error()))
+	}
+}
+
+func (s *Service) GetAuditEvents(ctx context.Context, request *GetAuditEventsRequest) (*GetAuditEventsResponse, error) {
+	if err := authorize(request.Principal, permReadAudit); err != nil {
+		return nil, err
+	}
+	var v validator
+	switch request.Outcome {
+	case "", storage.AuditSuccess, storage.AuditFailure:
+	default:
+		v.add(FieldError{Field: "outcome", Rule: RuleOneOf, Message: "must be success or failure"})
+	}
+	v.optionalUsername("actor", request.Actor)
+	v.limit("limit", request.Limit, maxAuditLimit)
+	v.timeRange(request.From, request.To)
+	after := v.cursor("cursor", request.Cursor)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultAuditLimit
+	}
+	dbRequest := &storage.GetAuditEventsRequest{
+		Actor:   request.Actor,
+		Action:  request.Action,
+		Outcome: request.Outcome,
+		IP:      request.IP,
+		// One extra event tells whether there is a next page.
+		Limit: limit + 1,
+		After: after,
+	}
+	if !request.From.IsZero() {
+		from := request.From.UTC()
+		dbRequest.From = &from
+	}
+	if !request.To.IsZero() {
+		to := request.To.UTC()
+		dbRequest.To = &to
+	}
+	events, err := s.db.GetAuditEvents(ctx, dbRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error get audit events from db: %w", err)
+	}
+
+	var nextCursor string
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[limit-1]
+		nextCursor = encodeHistoryCursor(&storage.HistoryCursor{
+			CreatedAt: last.CreatedAt,
+			Id:        last.Id,
+		})
+	}
+	resEvents := make([]*AuditEvent, 0, len(events))
+	for _, event := range events {
+		resEvents = append(resEvents, &AuditEvent{
+			Id:        event.Id,
+			Actor:     event.ActorName,
+			Action:    event.Action,
+			Target:    event.Target,
+			IP:        event.IP,
+			Outcome:   event.Outcome,
+			Details:   event.Details,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return &GetAuditEventsResponse{
+		Events:     resEvents,
+		NextCursor: nextCursor,
+	}, nil
+}
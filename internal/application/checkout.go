@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"sort"
+)
+
+func (s *Service) Checkout(ctx context.Context, request *CheckoutRequest) (_ *CheckoutResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditCheckout, "", &err)
+
+	userId, err := request.Principal.userId()
+	if err != nil {
+		return nil, err
+	}
+	cart, err := mergeCart(request.Items)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.db.Checkout(ctx, &storage.CheckoutRequest{
+		UserId: userId,
+		Items:  cart,
+	})
+	if err != nil {
+		return nil, purchaseError(err, "error checkout in db")
+	}
+	return &CheckoutResponse{
+		Total: res.Total,
+	}, nil
+}
+
+// mergeCart folds repeated items into a single line and sorts the cart by item
+// name, so that concurrent checkouts lock item rows in the same order.
+func mergeCart(items []*CartItem) ([]*storage.CartItem, error) {
+	var v validator
+	v.check(len(items) > 0, "items", RuleRequired, "must not be empty")
+	quantities := make(map[string]int, len(items))
+	for i, cartItem := range items {
+		v.check(cartItem.Item != "", fmt.Sprintf("items[%d].item", i), RuleRequired, "is required")
+		quantity := cartItem.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		field := fmt.Sprintf("items[%d].quantity", i)
+		if !v.amount(field, quantity) {
+			continue
+		}
+		// The lines of an item are bought as one, their sum is bounded too.
+		if v.check(quantities[cartItem.Item] <= maxAmount-quantity, field, RuleMax,
+			fmt.Sprintf("must be at most %d together with the other lines of the item", maxAmount)) {
+			quantities[cartItem.Item] += quantity
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	cart := make([]*storage.CartItem, 0, len(quantities))
+	for item, quantity := range quantities {
+		cart = append(cart, &storage.CartItem{
+			Item:     item,
+			Quantity: quantity,
+		})
+	}
+	sort.Slice(cart, func(i, j int) bool {
+		return cart[i].Item < cart[j].Item
+	})
+	return cart, nil
+}
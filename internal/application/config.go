@@ -1,6 +1,254 @@
 package application
 
+import (
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
 type Config struct {
 	Name   string `env:"NAME" envDefault:"labels-api" yaml:"name"`
 	Secret string `env:"SECRET" yaml:"secret"`
+	// Admins lists usernames that get the admin role at startup. Roles are
+	// stored on users, removing a name from the list doesn't demote it.
+	Admins []string `env:"ADMINS" envSeparator:"," yaml:"admins"`
+	// ReturnWindow is how long after the purchase an order can be returned.
+	ReturnWindow time.Duration `env:"RETURN_WINDOW" envDefault:"336h" yaml:"return-window"`
+	// AccessTokenTTL is how long an access token is accepted after it was issued.
+	AccessTokenTTL time.Duration `env:"ACCESS_TOKEN_TTL" envDefault:"15m" yaml:"access-token-ttl"`
+	// RefreshTokenTTL is how long a refresh token can be exchanged for a new
+	// pair of tokens.
+	RefreshTokenTTL time.Duration `env:"REFRESH_TOKEN_TTL" envDefault:"720h" yaml:"refresh-token-ttl"`
+	// TokenIssuer and TokenAudience are put into the iss and aud claims and
+	// checked on every request.
+	TokenIssuer   string `env:"TOKEN_ISSUER" envDefault:"avito-shop" yaml:"token-issuer"`
+	TokenAudience string `env:"TOKEN_AUDIENCE" envDefault:"avito-shop" yaml:"token-audience"`
+	// SigningKeys lists the keys tokens are signed and verified with as
+	// "kid:alg:path" entries. alg is RS256, ES256, EdDSA or HS256 and path is a
+	// PEM private key, or a file with the secret for HS256. A ":<RFC3339 time>"
+	// suffix retires the key at that time. Empty means HS256 with Secret and no
+	// kid; otherwise Secret only verifies tokens issued before the keys.
+	SigningKeys []string `env:"SIGNING_KEYS" envSeparator:"," yaml:"signing-keys"`
+	// SecretRetiredAt is when Secret stopped signing tokens, required when both
+	// Secret and SigningKeys are set. Tokens without kid are verified with
+	// Secret for SigningKeyGracePeriod after it.
+	SecretRetiredAt time.Time `env:"SECRET_RETIRED_AT" yaml:"secret-retired-at"`
+	// ActiveSigningKey is the kid new tokens are signed with, empty means the
+	// first key of SigningKeys that is not retired.
+	ActiveSigningKey string `env:"ACTIVE_SIGNING_KEY" yaml:"active-signing-key"`
+	// SigningKeyGracePeriod is how long a retired key keeps verifying tokens,
+	// zero means AccessTokenTTL, so every token it signed can still be used.
+	SigningKeyGracePeriod time.Duration `env:"SIGNING_KEY_GRACE_PERIOD" yaml:"signing-key-grace-period"`
+	// RevocationSyncInterval is how often revoked tokens are reloaded from the
+	// database and expired revocations are cleaned up. A token revoked through
+	// another instance is accepted here for at most this long.
+	RevocationSyncInterval time.Duration `env:"REVOCATION_SYNC_INTERVAL" envDefault:"10s" yaml:"revocation-sync-interval"`
+	// AutoRegister keeps the legacy behaviour of /api/auth creating an account
+	// for an unknown username instead of rejecting the login.
+	AutoRegister bool `env:"AUTO_REGISTER" envDefault:"false" yaml:"auto-register"`
+	// UsernameMinLength and UsernameMaxLength bound the username length in
+	// characters, zero disables the bound.
+	UsernameMinLength int `env:"USERNAME_MIN_LENGTH" envDefault:"3" yaml:"username-min-length"`
+	UsernameMaxLength int `env:"USERNAME_MAX_LENGTH" envDefault:"32" yaml:"username-max-length"`
+	// UsernamePattern is a regular expression a new username must match,
+	// empty allows any username.
+	UsernamePattern string `env:"USERNAME_PATTERN" envDefault:"^[A-Za-z0-9_.-]+$" yaml:"username-pattern"`
+	// PasswordMinLength is the minimum password length in characters.
+	PasswordMinLength int `env:"PASSWORD_MIN_LENGTH" envDefault:"8" yaml:"password-min-length"`
+	// PasswordMaxLength is the maximum password length in bytes, bcrypt
+	// ignores everything past 72 bytes.
+	PasswordMaxLength int `env:"PASSWORD_MAX_LENGTH" envDefault:"72" yaml:"password-max-length"`
+	// PasswordRequireLetterAndDigit requires at least one letter and one digit.
+	PasswordRequireLetterAndDigit bool `env:"PASSWORD_REQUIRE_LETTER_AND_DIGIT" envDefault:"true" yaml:"password-require-letter-and-digit"`
+	// PasswordHash is the algorithm of new password hashes, bcrypt or
+	// argon2id. Hashes of the other algorithm or with other parameters keep
+	// working and are replaced on the next successful login.
+	PasswordHash string `env:"PASSWORD_HASH" envDefault:"bcrypt" yaml:"password-hash"`
+	// BcryptCost is the cost of new bcrypt hashes.
+	BcryptCost int `env:"BCRYPT_COST" envDefault:"10" yaml:"bcrypt-cost"`
+	// Argon2Time, Argon2Memory in KiB and Argon2Threads are the parameters of
+	// new argon2id hashes.
+	Argon2Time    uint32 `env:"ARGON2_TIME" envDefault:"2" yaml:"argon2-time"`
+	Argon2Memory  uint32 `env:"ARGON2_MEMORY" envDefault:"19456" yaml:"argon2-memory"`
+	Argon2Threads uint8  `env:"ARGON2_THREADS" envDefault:"1" yaml:"argon2-threads"`
+	// LoginFreeAttemptsPerUser and LoginFreeAttemptsPerIP are how many
+	// logins for a username or from an address go without delay, zero turns
+	// the throttling off for that kind of key. A successful login doesn't
+	// count. Every further attempt blocks the key for LoginBackoffBase,
+	// doubled with each attempt.
+	LoginFreeAttemptsPerUser int           `env:"LOGIN_FREE_ATTEMPTS_PER_USER" envDefault:"5" yaml:"login-free-attempts-per-user"`
+	LoginFreeAttemptsPerIP   int           `env:"LOGIN_FREE_ATTEMPTS_PER_IP" envDefault:"20" yaml:"login-free-attempts-per-ip"`
+	LoginBackoffBase         time.Duration `env:"LOGIN_BACKOFF_BASE" envDefault:"1s" yaml:"login-backoff-base"`
+	// LoginLockoutAfter is the number of attempts past the free ones that
+	// locks the key out for LoginLockoutDuration, which also caps the backoff.
+	// Zero leaves only the backoff.
+	LoginLockoutAfter    int           `env:"LOGIN_LOCKOUT_AFTER" envDefault:"10" yaml:"login-lockout-after"`
+	LoginLockoutDuration time.Duration `env:"LOGIN_LOCKOUT_DURATION" envDefault:"15m" yaml:"login-lockout-duration"`
+	// LoginFailureWindow is how long an attempt is remembered, a key that has
+	// not been tried for that long starts counting from zero.
+	LoginFailureWindow time.Duration `env:"LOGIN_FAILURE_WINDOW" envDefault:"1h" yaml:"login-failure-window"`
+	// IdempotencyKeyTTL is how long a request can be replayed with its
+	// Idempotency-Key, after that the key is forgotten and can be used again.
+	IdempotencyKeyTTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h" yaml:"idempotency-key-ttl"`
+	// CleanupInterval is how often the state that is no longer needed, such
+	// as expired idempotency keys, is deleted from the database.
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" envDefault:"5m" yaml:"cleanup-interval"`
+}
+
+const (
+	defaultReturnWindow = 14 * 24 * time.Hour
+
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	defaultTokenIssuer     = "avito-shop"
+	defaultTokenAudience   = "avito-shop"
+
+	defaultRevocationSyncInterval = 10 * time.Second
+
+	defaultBcryptCost    = bcrypt.DefaultCost
+	defaultArgon2Time    = 2
+	defaultArgon2Memory  = 19 * 1024
+	defaultArgon2Threads = 1
+
+	defaultLoginBackoffBase     = time.Second
+	defaultLoginLockoutDuration = 15 * time.Minute
+	defaultLoginFailureWindow   = time.Hour
+
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+	defaultCleanupInterval   = 5 * time.Minute
+)
+
+// The settings fall back to the defaults when they are missing, which happens
+// with a yaml config that predates them.
+
+func (c *Config) returnWindow() time.Duration {
+	if c.ReturnWindow <= 0 {
+		return defaultReturnWindow
+	}
+	return c.ReturnWindow
+}
+
+func (c *Config) accessTokenTTL() time.Duration {
+	if c.AccessTokenTTL <= 0 {
+		return defaultAccessTokenTTL
+	}
+	return c.AccessTokenTTL
+}
+
+func (c *Config) refreshTokenTTL() time.Duration {
+	if c.RefreshTokenTTL <= 0 {
+		return defaultRefreshTokenTTL
+	}
+	return c.RefreshTokenTTL
+}
+
+func (c *Config) tokenIssuer() string {
+	if c.TokenIssuer == "" {
+		return defaultTokenIssuer
+	}
+	return c.TokenIssuer
+}
+
+func (c *Config) tokenAudience() string {
+	if c.TokenAudience == "" {
+		return defaultTokenAudience
+	}
+	return c.TokenAudience
+}
+
+func (c *Config) revocationSyncInterval() time.Duration {
+	if c.RevocationSyncInterval <= 0 {
+		return defaultRevocationSyncInterval
+	}
+	return c.RevocationSyncInterval
+}
+
+func (c *Config) signingKeyGracePeriod() time.Duration {
+	if c.SigningKeyGracePeriod <= 0 {
+		return c.accessTokenTTL()
+	}
+	return c.SigningKeyGracePeriod
+}
+
+func (c *Config) idempotencyKeyTTL() time.Duration {
+	if c.IdempotencyKeyTTL <= 0 {
+		return defaultIdempotencyKeyTTL
+	}
+	return c.IdempotencyKeyTTL
+}
+
+func (c *Config) cleanupInterval() time.Duration {
+	if c.CleanupInterval <= 0 {
+		return defaultCleanupInterval
+	}
+	return c.CleanupInterval
+}
+
+func (c *Config) passwordHash() string {
+	if c.PasswordHash == "" {
+		return hashBcrypt
+	}
+	return c.PasswordHash
+}
+
+func (c *Config) bcryptCost() int {
+	if c.BcryptCost <= 0 {
+		return defaultBcryptCost
+	}
+	return c.BcryptCost
+}
+
+func (c *Config) argon2Params() argon2Params {
+	params := argon2Params{
+		time:    c.Argon2Time,
+		memory:  c.Argon2Memory,
+		threads: c.Argon2Threads,
+	}
+	if params.time == 0 {
+		params.time = defaultArgon2Time
+	}
+	if params.memory == 0 {
+		params.memory = defaultArgon2Memory
+	}
+	if params.threads == 0 {
+		params.threads = defaultArgon2Threads
+	}
+	return params
+}
+
+func (c *Config) loginBackoffBase() time.Duration {
+	if c.LoginBackoffBase <= 0 {
+		return defaultLoginBackoffBase
+	}
+	return c.LoginBackoffBase
+}
+
+func (c *Config) loginLockoutDuration() time.Duration {
+	if c.LoginLockoutDuration <= 0 {
+		return defaultLoginLockoutDuration
+	}
+	return c.LoginLockoutDuration
+}
+
+func (c *Config) loginFailureWindow() time.Duration {
+	if c.LoginFailureWindow <= 0 {
+		return defaultLoginFailureWindow
+	}
+	return c.LoginFailureWindow
+}
+
+// loginDelays lists how long a key is blocked after each attempt past the
+// free ones: LoginBackoffBase doubled each time, up to the first one that
+// locks the key out. The storage repeats the last delay for the attempts
+// that follow.
+func (c *Config) loginDelays() []time.Duration {
+	lockout := c.loginLockoutDuration()
+	delay := c.loginBackoffBase()
+	var delays []time.Duration
+	for over := 1; ; over++ {
+		if (c.LoginLockoutAfter > 0 && over >= c.LoginLockoutAfter) || delay >= lockout {
+			return append(delays, lockout)
+		}
+		delays = append(delays, delay)
+		delay *= 2
+	}
 }
@@ -4,48 +4,89 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/golang-jwt/jwt"
 	"github.com/azaliaz/avito-shop/internal/storage"
-	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"unicode/utf8"
 )
 
-const hashCost = 10
+// legacyDummyPassHash stands in for the dummy hash if hashing it fails.
+const legacyDummyPassHash = "$2a$10$BaZWnWzCru2yy64fHEFC5e0TB4eDbCzkPFzXjIOkAxcuVMR8FFraW"
 
-func (s *Service) Auth(ctx context.Context, request *AuthRequest) (*AuthResponse, error) {
-	if request.Password == "" {
-		return nil, errors.New("password cannot be empty")
+func (s *Service) Auth(ctx context.Context, request *AuthRequest) (_ *AuthResponse, err error) {
+	var v validator
+	v.username("username", request.Username)
+	v.check(request.Password != "", "password", RuleRequired, "is required")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	keys := s.loginThrottleKeys(ctx, request.Username)
+	if err := s.countLoginAttempt(ctx, keys); err != nil {
+		return nil, err
+	}
+	// Only the attempts that get to the password check are recorded, the
+	// malformed and throttled ones are cheap to repeat and would flood the log.
+	defer s.auditFailure(ctx, storage.AuditLogin, request.Username, &err)
+
+	var res *storage.AuthResponse
+	var created bool
+	if s.config.AutoRegister {
+		res, created, err = s.authOrRegister(ctx, request)
+	} else {
+		res, err = s.db.GetCredentials(ctx, request.Username)
 	}
-	hashedRequestPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), hashCost)
-	if err != nil {
-		return nil, fmt.Errorf("error generate password hash: %w", err)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		s.verifyPassword(s.dummyPassHash(), request.Password)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, ErrUnknownUser)
 	}
-	res, err := s.db.Auth(ctx, &storage.AuthRequest{
-		UserName: request.Username,
-		PassHash: string(hashedRequestPassword),
-	})
 	if err != nil {
 		return nil, fmt.Errorf("error auth in db: %w", err)
 	}
-	err = bcrypt.CompareHashAndPassword([]byte(res.PassHash), []byte(request.Password))
+	// A new account has just been hashed with this password, checking it
+	// would only double the cost of the login.
+	if !created {
+		ok, outdated := s.verifyPassword(res.PassHash, request.Password)
+		if !ok {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, ErrBadPassword)
+		}
+		if outdated {
+			s.rehashPassword(ctx, res, request.Password)
+		}
+	}
+	if err := s.clearLoginAttempts(ctx, keys); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, res.UserId, Role(res.Role), true)
+}
+
+// authOrRegister is the legacy login that creates the account when the
+// username is not taken yet, it is used only when AutoRegister is enabled.
+// The password is hashed only for a new account, created reports that the
+// account was created with it.
+func (s *Service) authOrRegister(ctx context.Context, request *AuthRequest) (_ *storage.AuthResponse, created bool, err error) {
+	res, err := s.db.GetCredentials(ctx, request.Username)
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		return res, false, err
+	}
+	passHash, err := s.hashPassword(request.Password)
 	if err != nil {
-		return nil, errors.New("invalid creds")
+		return nil, false, fmt.Errorf("error generate password hash: %w", err)
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": res.UserId,
+	res, err = s.db.Auth(ctx, &storage.AuthRequest{
+		UserName: request.Username,
+		PassHash: passHash,
 	})
-	t, err := token.SignedString([]byte(s.config.Secret))
 	if err != nil {
-		return nil, fmt.Errorf("error sign token: %w", err)
+		return nil, false, err
 	}
-	return &AuthResponse{
-		Token: t,
-	}, nil
+	// The name may have been registered concurrently, the existing account
+	// is returned then.
+	return res, res.PassHash == passHash, nil
 }
 
 func (s *Service) GetInfo(ctx context.Context, request *GetInfoRequest) (*GetInfoResponse, error) {
-	userId, err := s.userIdFromToken(request.Token)
+	userId, err := s.subjectId(ctx, request.Principal, request.UserName)
 	if err != nil {
-		return nil, fmt.Errorf("error get user id from token: %w", err)
+		return nil, err
 	}
 
 	inventory, err := s.db.GetInventory(ctx, userId)
@@ -73,6 +114,7 @@ func (s *Service) GetInfo(ctx context.Context, request *GetInfoRequest) (*GetInf
 			Amount:    transaction.Amount,
 			FromUser:  transaction.FromUser,
 			ToUser:    transaction.ToUser,
+			Memo:      transaction.Memo,
 			CreatedAt: transaction.CreatedAt,
 		})
 	}
@@ -82,6 +124,7 @@ func (s *Service) GetInfo(ctx context.Context, request *GetInfoRequest) (*GetInf
 			Amount:    transaction.Amount,
 			FromUser:  transaction.FromUser,
 			ToUser:    transaction.ToUser,
+			Memo:      transaction.Memo,
 			CreatedAt: transaction.CreatedAt,
 		})
 	}
@@ -94,52 +137,105 @@ func (s *Service) GetInfo(ctx context.Context, request *GetInfoRequest) (*GetInf
 		Inventory: resInventory,
 	}, nil
 }
-func (s *Service) SendCoin(ctx context.Context, request *SendCoinRequest) (*SendCoinResponse, error) {
-	userId, err := s.userIdFromToken(request.Token)
+func (s *Service) SendCoin(ctx context.Context, request *SendCoinRequest) (_ *SendCoinResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditSendCoin, request.ToUser, &err)
+
+	userId, err := request.Principal.userId()
 	if err != nil {
-		return nil, fmt.Errorf("error get user id from token: %w", err)
+		return nil, err
 	}
 
-	_, err = s.db.SendCoin(ctx, &storage.SendCoinRequest{
-		UserId: userId,
-		Amount: request.Amount,
-		ToUser: request.ToUser,
+	memo := strings.TrimSpace(request.Memo)
+	var v validator
+	v.amount("amount", request.Amount)
+	v.username("toUser", request.ToUser)
+	if utf8.RuneCountInString(memo) > maxMemoLength {
+		v.add(FieldError{Field: "memo", Rule: RuleMax, Err: ErrMemoTooLong,
+			Message: fmt.Sprintf("must be at most %d characters long", maxMemoLength)})
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if len(request.IdempotencyKey) > maxIdempotencyKeyLength {
+		return nil, ErrIdempotencyKeyTooLong
+	}
+	res, err := s.db.SendCoin(ctx, &storage.SendCoinRequest{
+		UserId:            userId,
+		Amount:            request.Amount,
+		ToUser:            request.ToUser,
+		Memo:              memo,
+		IdempotencyKey:    request.IdempotencyKey,
+		IdempotencyKeyTTL: s.config.idempotencyKeyTTL(),
 	})
-	if err != nil {
+	switch {
+	case errors.Is(err, storage.ErrRecipientNotFound):
+		return nil, ErrRecipientNotFound
+	case errors.Is(err, storage.ErrSelfTransfer):
+		return nil, &ValidationError{Fields: []FieldError{
+			{Field: "toUser", Rule: RuleNotSelf, Message: "must not be the sender", Err: ErrSelfTransfer},
+		}}
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		return nil, ErrInsufficientFunds
+	case errors.Is(err, storage.ErrIdempotencyKeyReused):
+		return nil, ErrIdempotencyKeyReused
+	case err != nil:
 		return nil, fmt.Errorf("error send coin in db: %w", err)
 	}
-	return &SendCoinResponse{}, nil
+	return &SendCoinResponse{
+		Replayed: res.Replayed,
+	}, nil
 }
-func (s *Service) BuyItem(ctx context.Context, request *BuyItemRequest) (*BuyItemResponse, error) {
-	userId, err := s.userIdFromToken(request.Token)
+func (s *Service) BuyItem(ctx context.Context, request *BuyItemRequest) (_ *BuyItemResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditBuyItem, request.Item, &err)
+
+	userId, err := request.Principal.userId()
 	if err != nil {
-		return nil, fmt.Errorf("error get user id from token: %w", err)
-	}
-	_, err = s.db.BuyItem(ctx, &storage.BuyItemRequest{
-		UserId: userId,
-		Item:   request.Item,
+		return nil, err
+	}
+	quantity := request.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	var v validator
+	v.check(request.Item != "", "item", RuleRequired, "is required")
+	v.amount("quantity", quantity)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if len(request.IdempotencyKey) > maxIdempotencyKeyLength {
+		return nil, ErrIdempotencyKeyTooLong
+	}
+	res, err := s.db.BuyItem(ctx, &storage.BuyItemRequest{
+		UserId:            userId,
+		Item:              request.Item,
+		Quantity:          quantity,
+		IdempotencyKey:    request.IdempotencyKey,
+		IdempotencyKeyTTL: s.config.idempotencyKeyTTL(),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error buy item in db: %w", err)
+		return nil, purchaseError(err, "error buy item in db")
 	}
-	return &BuyItemResponse{}, nil
+	return &BuyItemResponse{
+		Replayed: res.Replayed,
+	}, nil
 }
 
-func (s *Service) userIdFromToken(token string) (uint64, error) {
-	claims := jwt.MapClaims{}
-	t, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.config.Secret), nil
-	})
-	if err != nil {
-		return 0, fmt.Errorf("error parse token: %w", err)
-	}
-	if !t.Valid {
-		return 0, errors.New("invalid token")
+// purchaseError translates storage purchase failures into the application
+// errors, wrapping anything unexpected with msg.
+func purchaseError(err error, msg string) error {
+	switch {
+	case errors.Is(err, storage.ErrItemNotFound):
+		return ErrItemNotFound
+	case errors.Is(err, storage.ErrItemSoldOut):
+		return ErrItemSoldOut
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		return ErrInsufficientFunds
+	case errors.Is(err, storage.ErrIdempotencyKeyReused):
+		return ErrIdempotencyKeyReused
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
 	}
-	floatUserId, ok := claims["user_id"].(float64)
-	if !ok {
-		return 0, errors.New("invalid user_id in token")
-	}
-	userId := uint64(floatUserId)
-	return userId, nil
 }
+
@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+func (s *Service) GetHistory(ctx context.Context, request *GetHistoryRequest) (*GetHistoryResponse, error) {
+	userId, err := s.subjectId(ctx, request.Principal, request.UserName)
+	if err != nil {
+		return nil, err
+	}
+	var v validator
+	switch request.Direction {
+	case "", storage.DirectionSent, storage.DirectionReceived:
+	default:
+		v.add(FieldError{Field: "direction", Rule: RuleOneOf, Message: "must be sent or received"})
+	}
+	v.optionalUsername("counterparty", request.Counterparty)
+	v.limit("limit", request.Limit, maxHistoryLimit)
+	v.timeRange(request.From, request.To)
+	after := v.cursor("cursor", request.Cursor)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+	dbRequest := &storage.GetHistoryRequest{
+		UserId:       userId,
+		Direction:    request.Direction,
+		Counterparty: request.Counterparty,
+		// One extra entry tells whether there is a next page.
+		Limit: limit + 1,
+		After: after,
+	}
+	if !request.From.IsZero() {
+		from := request.From.UTC()
+		dbRequest.From = &from
+	}
+	if !request.To.IsZero() {
+		to := request.To.UTC()
+		dbRequest.To = &to
+	}
+	history, err := s.db.GetHistory(ctx, dbRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error get history from db: %w", err)
+	}
+
+	var nextCursor string
+	if len(history) > limit {
+		history = history[:limit]
+		last := history[limit-1]
+		nextCursor = encodeHistoryCursor(&storage.HistoryCursor{
+			CreatedAt: last.CreatedAt,
+			Id:        last.Id,
+		})
+	}
+	transactions := make([]*HistoryEntry, 0, len(history))
+	for _, entry := range history {
+		transactions = append(transactions, &HistoryEntry{
+			Id:        entry.Id,
+			Direction: entry.Direction,
+			FromUser:  entry.FromUser,
+			ToUser:    entry.ToUser,
+			Amount:    entry.Amount,
+			Memo:      entry.Memo,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	return &GetHistoryResponse{
+		Transactions: transactions,
+		NextCursor:   nextCursor,
+	}, nil
+}
+
+// encodeHistoryCursor packs the position of the last entry of a page into an
+// opaque string, clients only pass it back to get the next page.
+func encodeHistoryCursor(cursor *storage.HistoryCursor) string {
+	raw := fmt.Sprintf("%d:%d", cursor.CreatedAt.UnixMicro(), cursor.Id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeHistoryCursor(cursor string) (*storage.HistoryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	rawTime, rawId, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	micros, err := strconv.ParseInt(rawTime, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(rawId, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &storage.HistoryCursor{
+		CreatedAt: time.UnixMicro(micros).UTC(),
+		Id:        id,
+	}, nil
+}
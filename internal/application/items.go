@@ -0,0 +1,196 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+)
+
+func (s *Service) GetItems(ctx context.Context, _ *GetItemsRequest) (*GetItemsResponse, error) {
+	items, err := s.db.GetItems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error get items from db: %w", err)
+	}
+	resItems := make([]*Item, 0, len(items))
+	for _, item := range items {
+		resItems = append(resItems, &Item{
+			Name:  item.Name,
+			Price: item.Price,
+			Stock: item.Stock,
+		})
+	}
+	return &GetItemsResponse{
+		Items: resItems,
+	}, nil
+}
+
+func (s *Service) GetItem(ctx context.Context, request *GetItemRequest) (*GetItemResponse, error) {
+	item, err := s.db.GetItem(ctx, request.Name)
+	if errors.Is(err, storage.ErrItemNotFound) {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error get item from db: %w", err)
+	}
+	return &GetItemResponse{
+		Item: &Item{
+			Name:  item.Name,
+			Price: item.Price,
+			Stock: item.Stock,
+		},
+	}, nil
+}
+
+func (s *Service) CreateItem(ctx context.Context, request *CreateItemRequest) (_ *CreateItemResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditCreateItem, request.Name, &err)
+
+	if err := authorize(request.Principal, permManageCatalog); err != nil {
+		return nil, err
+	}
+	var v validator
+	v.itemName("name", request.Name)
+	v.amount("price", request.Price)
+	if request.Stock != nil {
+		v.check(*request.Stock >= 0, "stock", RuleMin, "must not be negative")
+		v.check(*request.Stock <= maxAmount, "stock", RuleMax, fmt.Sprintf("must be at most %d", maxAmount))
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	_, err = s.db.CreateItem(ctx, &storage.CreateItemRequest{
+		Name:  request.Name,
+		Price: request.Price,
+		Stock: request.Stock,
+	})
+	if errors.Is(err, storage.ErrItemExists) {
+		return nil, ErrItemExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error create item in db: %w", err)
+	}
+	return &CreateItemResponse{}, nil
+}
+
+func (s *Service) UpdateItemPrice(ctx context.Context, request *UpdateItemPriceRequest) (_ *UpdateItemPriceResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditUpdateItemPrice, request.Name, &err)
+
+	if err := authorize(request.Principal, permManageCatalog); err != nil {
+		return nil, err
+	}
+	var v validator
+	v.amount("price", request.Price)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	_, err = s.db.UpdateItemPrice(ctx, &storage.UpdateItemPriceRequest{
+		Name:  request.Name,
+		Price: request.Price,
+	})
+	if errors.Is(err, storage.ErrItemNotFound) {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error update item price in db: %w", err)
+	}
+	return &UpdateItemPriceResponse{}, nil
+}
+
+func (s *Service) RenameItem(ctx context.Context, request *RenameItemRequest) (_ *RenameItemResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditRenameItem, request.Name, &err)
+
+	if err := authorize(request.Principal, permManageCatalog); err != nil {
+		return nil, err
+	}
+	var v validator
+	v.itemName("name", request.NewName)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	_, err = s.db.RenameItem(ctx, &storage.RenameItemRequest{
+		Name:    request.Name,
+		NewName: request.NewName,
+	})
+	if errors.Is(err, storage.ErrItemNotFound) {
+		return nil, ErrItemNotFound
+	}
+	if errors.Is(err, storage.ErrItemExists) {
+		return nil, ErrItemExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error rename item in db: %w", err)
+	}
+	return &RenameItemResponse{}, nil
+}
+
+func (s *Service) RetireItem(ctx context.Context, request *RetireItemRequest) (_ *RetireItemResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditRetireItem, request.Name, &err)
+
+	if err := authorize(request.Principal, permManageCatalog); err != nil {
+		return nil, err
+	}
+	err = s.db.RetireItem(ctx, request.Name)
+	if errors.Is(err, storage.ErrItemNotFound) {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retire item in db: %w", err)
+	}
+	return &RetireItemResponse{}, nil
+}
+
+func (s *Service) RestockItem(ctx context.Context, request *RestockItemRequest) (_ *RestockItemResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditRestockItem, request.Name, &err)
+
+	if err := authorize(request.Principal, permManageCatalog); err != nil {
+		return nil, err
+	}
+	var v validator
+	v.amount("amount", request.Amount)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	res, err := s.db.RestockItem(ctx, &storage.RestockItemRequest{
+		Name:   request.Name,
+		Amount: request.Amount,
+	})
+	switch {
+	case errors.Is(err, storage.ErrItemNotFound):
+		return nil, ErrItemNotFound
+	case errors.Is(err, storage.ErrItemUnlimited):
+		return nil, ErrItemUnlimited
+	case err != nil:
+		return nil, fmt.Errorf("error restock item in db: %w", err)
+	}
+	return &RestockItemResponse{
+		Stock: res.Stock,
+	}, nil
+}
+
+func (s *Service) GetItemPrices(ctx context.Context, request *GetItemPricesRequest) (*GetItemPricesResponse, error) {
+	if err := authorize(request.Principal, permReadAll); err != nil {
+		return nil, err
+	}
+	prices, err := s.db.GetItemPrices(ctx, request.Name)
+	if errors.Is(err, storage.ErrItemNotFound) {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error get item prices from db: %w", err)
+	}
+	resPrices := make([]*ItemPrice, 0, len(prices))
+	for _, price := range prices {
+		resPrices = append(resPrices, &ItemPrice{
+			Price:     price.Price,
+			ValidFrom: price.ValidFrom,
+		})
+	}
+	return &GetItemPricesResponse{
+		Prices: resPrices,
+	}, nil
+}
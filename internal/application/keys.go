@@ -0,0 +1,244 @@
+package application
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+func (s *Service) GetJWKS(_ context.Context, _ *GetJWKSRequest) (*GetJWKSResponse, error) {
+	now := time.Now()
+	keys := make([]*JSONWebKey, 0, len(s.keys.keys))
+	for _, key := range s.keys.keys {
+		if !key.verifies(now, s.config.signingKeyGracePeriod()) {
+			continue
+		}
+		jwk, ok := key.jwk()
+		if !ok {
+			continue
+		}
+		keys = append(keys, jwk)
+	}
+	return &GetJWKSResponse{
+		Keys: keys,
+	}, nil
+}
+
+// signingKey is a key tokens are signed or verified with.
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	// private signs tokens, public verifies them, both are the secret for
+	// HS256.
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+	// retiredAt is when the key stops signing, zero means never. A retired
+	// key keeps verifying tokens for the grace period.
+	retiredAt time.Time
+}
+
+func (k *signingKey) signs(now time.Time) bool {
+	return k.retiredAt.IsZero() || now.Before(k.retiredAt)
+}
+
+func (k *signingKey) verifies(now time.Time, gracePeriod time.Duration) bool {
+	return k.retiredAt.IsZero() || now.Before(k.retiredAt.Add(gracePeriod))
+}
+
+// jwk returns the public part of the key, symmetric keys are never published.
+func (k *signingKey) jwk() (*JSONWebKey, bool) {
+	jwk := &JSONWebKey{
+		Kid: k.kid,
+		Alg: k.method.Alg(),
+		Use: "sig",
+	}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (public.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = public.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(public.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(public.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	default:
+		return nil, false
+	}
+	return jwk, true
+}
+
+// keySet holds every key that may verify a token, in config order.
+type keySet struct {
+	keys []*signingKey
+	// active is the kid preferred for signing, empty means the first key that
+	// is not retired.
+	active string
+}
+
+// legacyKeySet signs with Secret and HS256 and without a kid, which is how
+// tokens were issued before signing keys became configurable.
+func legacyKeySet(config *Config) *keySet {
+	return &keySet{
+		keys: []*signingKey{legacyKey(config.Secret)},
+	}
+}
+
+func legacyKey(secret string) *signingKey {
+	return &signingKey{
+		method:  jwt.SigningMethodHS256,
+		private: []byte(secret),
+		public:  []byte(secret),
+	}
+}
+
+func loadKeySet(config *Config) (*keySet, error) {
+	if len(config.SigningKeys) == 0 {
+		return legacyKeySet(config), nil
+	}
+	set := &keySet{
+		active: config.ActiveSigningKey,
+	}
+	for _, entry := range config.SigningKeys {
+		key, err := parseSigningKey(entry)
+		if err != nil {
+			return nil, err
+		}
+		if set.find(key.kid) != nil {
+			return nil, fmt.Errorf("duplicate signing key %q", key.kid)
+		}
+		set.keys = append(set.keys, key)
+	}
+	// Tokens issued before the keys were configured have no kid and keep
+	// being accepted while Secret is set. The retirement time must outlive
+	// restarts, otherwise such tokens would never stop being accepted.
+	if config.Secret != "" {
+		if config.SecretRetiredAt.IsZero() {
+			return nil, fmt.Errorf("retirement time of the secret is required with signing keys")
+		}
+		key := legacyKey(config.Secret)
+		key.retiredAt = config.SecretRetiredAt
+		set.keys = append(set.keys, key)
+	}
+	if set.active != "" {
+		key := set.find(set.active)
+		if key == nil {
+			return nil, fmt.Errorf("active signing key %q is not configured", set.active)
+		}
+		if !key.signs(time.Now()) {
+			return nil, fmt.Errorf("active signing key %q is retired", set.active)
+		}
+	}
+	if _, err := set.signingKey(time.Now()); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// parseSigningKey parses a "kid:alg:path[:retired-at]" entry of
+// Config.SigningKeys.
+func parseSigningKey(entry string) (*signingKey, error) {
+	parts := strings.SplitN(entry, ":", 4)
+	if len(parts) < 3 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid signing key %q, want kid:alg:path[:retired-at]", entry)
+	}
+	key := &signingKey{
+		kid: parts[0],
+	}
+	if len(parts) == 4 {
+		retiredAt, err := time.Parse(time.RFC3339, parts[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid retirement time of signing key %q: %w", key.kid, err)
+		}
+		key.retiredAt = retiredAt
+	}
+	data, err := os.ReadFile(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("error read signing key %q: %w", key.kid, err)
+	}
+	switch parts[1] {
+	case jwt.SigningMethodRS256.Alg():
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("error parse signing key %q: %w", key.kid, err)
+		}
+		key.method, key.private, key.public = jwt.SigningMethodRS256, private, &private.PublicKey
+	case jwt.SigningMethodES256.Alg():
+		private, err := jwt.ParseECPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("error parse signing key %q: %w", key.kid, err)
+		}
+		if private.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("signing key %q must use the P-256 curve", key.kid)
+		}
+		key.method, key.private, key.public = jwt.SigningMethodES256, private, &private.PublicKey
+	case jwt.SigningMethodEdDSA.Alg():
+		private, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("error parse signing key %q: %w", key.kid, err)
+		}
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, private, private.(ed25519.PrivateKey).Public()
+	case jwt.SigningMethodHS256.Alg():
+		secret := []byte(strings.TrimSpace(string(data)))
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("signing key %q is empty", key.kid)
+		}
+		key.method, key.private, key.public = jwt.SigningMethodHS256, secret, secret
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q of signing key %q", parts[1], key.kid)
+	}
+	return key, nil
+}
+
+func (k *keySet) find(kid string) *signingKey {
+	for _, key := range k.keys {
+		if key.kid == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+// signingKey returns the key new tokens are signed with.
+func (k *keySet) signingKey(now time.Time) (*signingKey, error) {
+	if key := k.find(k.active); k.active != "" && key != nil && key.signs(now) {
+		return key, nil
+	}
+	for _, key := range k.keys {
+		if key.signs(now) {
+			return key, nil
+		}
+	}
+	return nil, errors.New("no signing key is available, all keys are retired")
+}
+
+// verificationKey returns the key for a token signed with kid and method.
+func (k *keySet) verificationKey(kid string, method jwt.SigningMethod, now time.Time, gracePeriod time.Duration) (crypto.PublicKey, error) {
+	key := k.find(kid)
+	if key == nil {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if !key.verifies(now, gracePeriod) {
+		return nil, fmt.Errorf("signing key %q is retired", kid)
+	}
+	if method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q", method.Alg())
+	}
+	return key.public, nil
+}
@@ -0,0 +1,27 @@
+package application
+
+import (
+	"context"
+	"fmt"
+)
+
+func (s *Service) CheckLedger(ctx context.Context, request *CheckLedgerRequest) (*CheckLedgerResponse, error) {
+	if err := authorize(request.Principal, permReadAll); err != nil {
+		return nil, err
+	}
+	mismatches, err := s.db.CheckBalances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error check balances in db: %w", err)
+	}
+	resMismatches := make([]*BalanceMismatch, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		resMismatches = append(resMismatches, &BalanceMismatch{
+			UserName:      mismatch.UserName,
+			Balance:       mismatch.Balance,
+			LedgerBalance: mismatch.LedgerBalance,
+		})
+	}
+	return &CheckLedgerResponse{
+		Mismatches: resMismatches,
+	}, nil
+}
@@ -40,6 +40,21 @@ func (m *MockShopService) EXPECT() *MockShopServiceMockRecorder {
 	return m.recorder
 }
 
+// AdjustBalance mocks base method.
+func (m *MockShopService) AdjustBalance(ctx context.Context, request *application.AdjustBalanceRequest) (*application.AdjustBalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBalance", ctx, request)
+	ret0, _ := ret[0].(*application.AdjustBalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustBalance indicates an expected call of AdjustBalance.
+func (mr *MockShopServiceMockRecorder) AdjustBalance(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBalance", reflect.TypeOf((*MockShopService)(nil).AdjustBalance), ctx, request)
+}
+
 // Auth mocks base method.
 func (m *MockShopService) Auth(ctx context.Context, request *application.AuthRequest) (*application.AuthResponse, error) {
 	m.ctrl.T.Helper()
@@ -55,6 +70,21 @@ func (mr *MockShopServiceMockRecorder) Auth(ctx, request any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Auth", reflect.TypeOf((*MockShopService)(nil).Auth), ctx, request)
 }
 
+// Authenticate mocks base method.
+func (m *MockShopService) Authenticate(ctx context.Context, request *application.AuthenticateRequest) (*application.AuthenticateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, request)
+	ret0, _ := ret[0].(*application.AuthenticateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockShopServiceMockRecorder) Authenticate(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockShopService)(nil).Authenticate), ctx, request)
+}
+
 // BuyItem mocks base method.
 func (m *MockShopService) BuyItem(ctx context.Context, request *application.BuyItemRequest) (*application.BuyItemResponse, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +100,96 @@ func (mr *MockShopServiceMockRecorder) BuyItem(ctx, request any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockShopService)(nil).BuyItem), ctx, request)
 }
 
+// ChangePassword mocks base method.
+func (m *MockShopService) ChangePassword(ctx context.Context, request *application.ChangePasswordRequest) (*application.ChangePasswordResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, request)
+	ret0, _ := ret[0].(*application.ChangePasswordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockShopServiceMockRecorder) ChangePassword(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockShopService)(nil).ChangePassword), ctx, request)
+}
+
+// CheckLedger mocks base method.
+func (m *MockShopService) CheckLedger(ctx context.Context, request *application.CheckLedgerRequest) (*application.CheckLedgerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLedger", ctx, request)
+	ret0, _ := ret[0].(*application.CheckLedgerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLedger indicates an expected call of CheckLedger.
+func (mr *MockShopServiceMockRecorder) CheckLedger(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLedger", reflect.TypeOf((*MockShopService)(nil).CheckLedger), ctx, request)
+}
+
+// Checkout mocks base method.
+func (m *MockShopService) Checkout(ctx context.Context, request *application.CheckoutRequest) (*application.CheckoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", ctx, request)
+	ret0, _ := ret[0].(*application.CheckoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockShopServiceMockRecorder) Checkout(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockShopService)(nil).Checkout), ctx, request)
+}
+
+// CreateItem mocks base method.
+func (m *MockShopService) CreateItem(ctx context.Context, request *application.CreateItemRequest) (*application.CreateItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", ctx, request)
+	ret0, _ := ret[0].(*application.CreateItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockShopServiceMockRecorder) CreateItem(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockShopService)(nil).CreateItem), ctx, request)
+}
+
+// GetAuditEvents mocks base method.
+func (m *MockShopService) GetAuditEvents(ctx context.Context, request *application.GetAuditEventsRequest) (*application.GetAuditEventsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEvents", ctx, request)
+	ret0, _ := ret[0].(*application.GetAuditEventsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEvents indicates an expected call of GetAuditEvents.
+func (mr *MockShopServiceMockRecorder) GetAuditEvents(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEvents", reflect.TypeOf((*MockShopService)(nil).GetAuditEvents), ctx, request)
+}
+
+// GetHistory mocks base method.
+func (m *MockShopService) GetHistory(ctx context.Context, request *application.GetHistoryRequest) (*application.GetHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, request)
+	ret0, _ := ret[0].(*application.GetHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockShopServiceMockRecorder) GetHistory(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockShopService)(nil).GetHistory), ctx, request)
+}
+
 // GetInfo mocks base method.
 func (m *MockShopService) GetInfo(ctx context.Context, request *application.GetInfoRequest) (*application.GetInfoResponse, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +205,201 @@ func (mr *MockShopServiceMockRecorder) GetInfo(ctx, request any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockShopService)(nil).GetInfo), ctx, request)
 }
 
+// GetItem mocks base method.
+func (m *MockShopService) GetItem(ctx context.Context, request *application.GetItemRequest) (*application.GetItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, request)
+	ret0, _ := ret[0].(*application.GetItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockShopServiceMockRecorder) GetItem(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockShopService)(nil).GetItem), ctx, request)
+}
+
+// GetItemPrices mocks base method.
+func (m *MockShopService) GetItemPrices(ctx context.Context, request *application.GetItemPricesRequest) (*application.GetItemPricesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemPrices", ctx, request)
+	ret0, _ := ret[0].(*application.GetItemPricesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemPrices indicates an expected call of GetItemPrices.
+func (mr *MockShopServiceMockRecorder) GetItemPrices(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemPrices", reflect.TypeOf((*MockShopService)(nil).GetItemPrices), ctx, request)
+}
+
+// GetItems mocks base method.
+func (m *MockShopService) GetItems(ctx context.Context, request *application.GetItemsRequest) (*application.GetItemsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, request)
+	ret0, _ := ret[0].(*application.GetItemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockShopServiceMockRecorder) GetItems(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockShopService)(nil).GetItems), ctx, request)
+}
+
+// GetJWKS mocks base method.
+func (m *MockShopService) GetJWKS(ctx context.Context, request *application.GetJWKSRequest) (*application.GetJWKSResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJWKS", ctx, request)
+	ret0, _ := ret[0].(*application.GetJWKSResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJWKS indicates an expected call of GetJWKS.
+func (mr *MockShopServiceMockRecorder) GetJWKS(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJWKS", reflect.TypeOf((*MockShopService)(nil).GetJWKS), ctx, request)
+}
+
+// GetOrders mocks base method.
+func (m *MockShopService) GetOrders(ctx context.Context, request *application.GetOrdersRequest) (*application.GetOrdersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders", ctx, request)
+	ret0, _ := ret[0].(*application.GetOrdersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockShopServiceMockRecorder) GetOrders(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockShopService)(nil).GetOrders), ctx, request)
+}
+
+// Logout mocks base method.
+func (m *MockShopService) Logout(ctx context.Context, request *application.LogoutRequest) (*application.LogoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, request)
+	ret0, _ := ret[0].(*application.LogoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockShopServiceMockRecorder) Logout(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockShopService)(nil).Logout), ctx, request)
+}
+
+// Refresh mocks base method.
+func (m *MockShopService) Refresh(ctx context.Context, request *application.RefreshRequest) (*application.RefreshResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, request)
+	ret0, _ := ret[0].(*application.RefreshResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockShopServiceMockRecorder) Refresh(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockShopService)(nil).Refresh), ctx, request)
+}
+
+// Register mocks base method.
+func (m *MockShopService) Register(ctx context.Context, request *application.RegisterRequest) (*application.RegisterResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, request)
+	ret0, _ := ret[0].(*application.RegisterResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockShopServiceMockRecorder) Register(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockShopService)(nil).Register), ctx, request)
+}
+
+// RenameItem mocks base method.
+func (m *MockShopService) RenameItem(ctx context.Context, request *application.RenameItemRequest) (*application.RenameItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameItem", ctx, request)
+	ret0, _ := ret[0].(*application.RenameItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameItem indicates an expected call of RenameItem.
+func (mr *MockShopServiceMockRecorder) RenameItem(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameItem", reflect.TypeOf((*MockShopService)(nil).RenameItem), ctx, request)
+}
+
+// RestockItem mocks base method.
+func (m *MockShopService) RestockItem(ctx context.Context, request *application.RestockItemRequest) (*application.RestockItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestockItem", ctx, request)
+	ret0, _ := ret[0].(*application.RestockItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestockItem indicates an expected call of RestockItem.
+func (mr *MockShopServiceMockRecorder) RestockItem(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestockItem", reflect.TypeOf((*MockShopService)(nil).RestockItem), ctx, request)
+}
+
+// RetireItem mocks base method.
+func (m *MockShopService) RetireItem(ctx context.Context, request *application.RetireItemRequest) (*application.RetireItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireItem", ctx, request)
+	ret0, _ := ret[0].(*application.RetireItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetireItem indicates an expected call of RetireItem.
+func (mr *MockShopServiceMockRecorder) RetireItem(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireItem", reflect.TypeOf((*MockShopService)(nil).RetireItem), ctx, request)
+}
+
+// ReturnOrder mocks base method.
+func (m *MockShopService) ReturnOrder(ctx context.Context, request *application.ReturnOrderRequest) (*application.ReturnOrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnOrder", ctx, request)
+	ret0, _ := ret[0].(*application.ReturnOrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReturnOrder indicates an expected call of ReturnOrder.
+func (mr *MockShopServiceMockRecorder) ReturnOrder(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnOrder", reflect.TypeOf((*MockShopService)(nil).ReturnOrder), ctx, request)
+}
+
+// RevokeUserSessions mocks base method.
+func (m *MockShopService) RevokeUserSessions(ctx context.Context, request *application.RevokeUserSessionsRequest) (*application.RevokeUserSessionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessions", ctx, request)
+	ret0, _ := ret[0].(*application.RevokeUserSessionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *MockShopServiceMockRecorder) RevokeUserSessions(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*MockShopService)(nil).RevokeUserSessions), ctx, request)
+}
+
 // SendCoin mocks base method.
 func (m *MockShopService) SendCoin(ctx context.Context, request *application.SendCoinRequest) (*application.SendCoinResponse, error) {
 	m.ctrl.T.Helper()
@@ -99,3 +414,33 @@ func (mr *MockShopServiceMockRecorder) SendCoin(ctx, request any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCoin", reflect.TypeOf((*MockShopService)(nil).SendCoin), ctx, request)
 }
+
+// SetUserRole mocks base method.
+func (m *MockShopService) SetUserRole(ctx context.Context, request *application.SetUserRoleRequest) (*application.SetUserRoleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", ctx, request)
+	ret0, _ := ret[0].(*application.SetUserRoleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockShopServiceMockRecorder) SetUserRole(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockShopService)(nil).SetUserRole), ctx, request)
+}
+
+// UpdateItemPrice mocks base method.
+func (m *MockShopService) UpdateItemPrice(ctx context.Context, request *application.UpdateItemPriceRequest) (*application.UpdateItemPriceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPrice", ctx, request)
+	ret0, _ := ret[0].(*application.UpdateItemPriceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemPrice indicates an expected call of UpdateItemPrice.
+func (mr *MockShopServiceMockRecorder) UpdateItemPrice(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPrice", reflect.TypeOf((*MockShopService)(nil).UpdateItemPrice), ctx, request)
+}
@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+)
+
+const (
+	defaultOrdersLimit = 20
+	maxOrdersLimit     = 100
+)
+
+func (s *Service) GetOrders(ctx context.Context, request *GetOrdersRequest) (*GetOrdersResponse, error) {
+	userId, err := s.subjectId(ctx, request.Principal, request.UserName)
+	if err != nil {
+		return nil, err
+	}
+	var v validator
+	v.limit("limit", request.Limit, maxOrdersLimit)
+	v.check(request.Offset >= 0, "offset", RuleMin, "must not be negative")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultOrdersLimit
+	}
+	res, err := s.db.GetOrders(ctx, &storage.GetOrdersRequest{
+		UserId: userId,
+		Limit:  limit,
+		Offset: request.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error get orders from db: %w", err)
+	}
+	orders := make([]*Order, 0, len(res.Orders))
+	for _, order := range res.Orders {
+		refunds := make([]*Refund, 0, len(order.Refunds))
+		for _, refund := range order.Refunds {
+			refunds = append(refunds, &Refund{
+				Quantity:  refund.Quantity,
+				Amount:    refund.Amount,
+				CreatedAt: refund.CreatedAt,
+			})
+		}
+		orders = append(orders, &Order{
+			Id:               order.Id,
+			Item:             order.Item,
+			Price:            order.Price,
+			Quantity:         order.Quantity,
+			ReturnedQuantity: order.ReturnedQuantity,
+			Refunds:          refunds,
+			CreatedAt:        order.CreatedAt,
+		})
+	}
+	return &GetOrdersResponse{
+		Orders: orders,
+		Total:  res.Total,
+	}, nil
+}
+
+func (s *Service) ReturnOrder(ctx context.Context, request *ReturnOrderRequest) (_ *ReturnOrderResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditReturnOrder, fmt.Sprintf("order:%d", request.OrderId), &err)
+
+	userId, err := request.Principal.userId()
+	if err != nil {
+		return nil, err
+	}
+	// A zero quantity returns the whole rest of the order.
+	var v validator
+	if request.Quantity != 0 {
+		v.amount("quantity", request.Quantity)
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	res, err := s.db.ReturnOrder(ctx, &storage.ReturnOrderRequest{
+		UserId:       userId,
+		OrderId:      request.OrderId,
+		Quantity:     request.Quantity,
+		ReturnWindow: s.config.returnWindow(),
+	})
+	switch {
+	case errors.Is(err, storage.ErrOrderNotFound):
+		return nil, ErrOrderNotFound
+	case errors.Is(err, storage.ErrReturnExpired):
+		return nil, ErrReturnExpired
+	case errors.Is(err, storage.ErrNothingToReturn):
+		return nil, ErrNothingToReturn
+	case err != nil:
+		return nil, fmt.Errorf("error return order in db: %w", err)
+	}
+	return &ReturnOrderResponse{
+		Amount: res.Amount,
+	}, nil
+}
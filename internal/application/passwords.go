@@ -0,0 +1,195 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"log/slog"
+	"strings"
+)
+
+// Password hash algorithms, see Config.PasswordHash.
+const (
+	hashBcrypt   = "bcrypt"
+	hashArgon2id = "argon2id"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+func (s *Service) ChangePassword(ctx context.Context, request *ChangePasswordRequest) (_ *ChangePasswordResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditChangePassword, "", &err)
+
+	principal := request.Principal
+	if principal == nil {
+		return nil, ErrUnauthenticated
+	}
+	var v validator
+	v.check(request.CurrentPassword != "", "currentPassword", RuleRequired, "is required")
+	s.checkPassword(&v, "newPassword", request.NewPassword)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	user, err := s.db.GetUser(ctx, principal.UserId)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error get user from db: %w", err)
+	}
+	// Guessing the current password is throttled like logins, otherwise a
+	// stolen access token would allow to brute-force it.
+	keys := s.loginThrottleKeys(ctx, user.UserName)
+	if err := s.countLoginAttempt(ctx, keys); err != nil {
+		return nil, err
+	}
+	credentials, err := s.db.GetCredentials(ctx, user.UserName)
+	if err != nil {
+		return nil, fmt.Errorf("error get credentials from db: %w", err)
+	}
+	if ok, _ := s.verifyPassword(credentials.PassHash, request.CurrentPassword); !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, ErrBadPassword)
+	}
+	passHash, err := s.hashPassword(request.NewPassword)
+	if err != nil {
+		return nil, fmt.Errorf("error generate password hash: %w", err)
+	}
+	err = s.db.ChangePassword(ctx, &storage.ChangePasswordRequest{
+		UserId:   user.UserId,
+		PassHash: passHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error change password in db: %w", err)
+	}
+	if err := s.clearLoginAttempts(ctx, keys); err != nil {
+		return nil, err
+	}
+	tokens, err := s.issueTokens(ctx, user.UserId, Role(user.Role), false)
+	if err != nil {
+		return nil, err
+	}
+	return &ChangePasswordResponse{
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	}, nil
+}
+
+// hashPassword hashes a new password with the configured algorithm.
+func (s *Service) hashPassword(password string) (string, error) {
+	if s.config.passwordHash() == hashArgon2id {
+		return s.config.argon2Params().hash(password)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.config.bcryptCost())
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// verifyPassword checks password against a stored hash of either algorithm.
+// outdated tells that the hash doesn't use the configured algorithm or
+// parameters and should be replaced.
+func (s *Service) verifyPassword(hash, password string) (ok, outdated bool) {
+	if strings.HasPrefix(hash, "$"+hashArgon2id+"$") {
+		params, salt, key, err := decodeArgon2Hash(hash)
+		if err != nil {
+			return false, false
+		}
+		computed := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+		if subtle.ConstantTimeCompare(computed, key) != 1 {
+			return false, false
+		}
+		return true, s.config.passwordHash() != hashArgon2id || params != s.config.argon2Params()
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, err != nil || s.config.passwordHash() != hashBcrypt || cost != s.config.bcryptCost()
+}
+
+// dummyPassHash is compared against when the user does not exist, so that an
+// unknown username takes as long to reject as a wrong password. It is hashed
+// once with the configured algorithm.
+func (s *Service) dummyPassHash() string {
+	s.dummyHashOnce.Do(func() {
+		hash, err := s.hashPassword("dummy password")
+		if err != nil {
+			hash = legacyDummyPassHash
+		}
+		s.dummyHash = hash
+	})
+	return s.dummyHash
+}
+
+// rehashPassword replaces an outdated hash after a successful login. A failure
+// only postpones the upgrade to the next login, so it doesn't fail the login.
+func (s *Service) rehashPassword(ctx context.Context, user *storage.AuthResponse, password string) {
+	passHash, err := s.hashPassword(password)
+	if err == nil {
+		err = s.db.UpdatePassHash(ctx, &storage.UpdatePassHashRequest{
+			UserId:      user.UserId,
+			OldPassHash: user.PassHash,
+			PassHash:    passHash,
+		})
+	}
+	if err != nil {
+		s.log.Warn("rehash password", slog.Uint64("userId", user.UserId), slog.String("err", err.Error()))
+	}
+}
+
+type argon2Params struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// hash returns the hash in the PHC string format, the one of the reference
+// implementation.
+func (p argon2Params) hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, argon2KeyLength)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", hashArgon2id, argon2.Version, p.memory, p.time, p.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func decodeArgon2Hash(hash string) (params argon2Params, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errors.New("invalid argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads)
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+	if len(salt) == 0 || len(key) == 0 {
+		return params, nil, nil, errors.New("invalid argon2id hash")
+	}
+	return params, salt, key, nil
+}
@@ -0,0 +1,165 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"sync"
+	"time"
+)
+
+func (s *Service) Logout(ctx context.Context, request *LogoutRequest) (_ *LogoutResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditLogout, "", &err)
+
+	principal := request.Principal
+	if principal == nil {
+		return nil, ErrUnauthenticated
+	}
+	var refreshTokenHash string
+	if request.RefreshToken != "" {
+		refreshTokenHash = hashRefreshToken(request.RefreshToken)
+	}
+	err = s.db.RevokeToken(ctx, &storage.RevokeTokenRequest{
+		Jti:              principal.TokenId,
+		UserId:           principal.UserId,
+		ExpiresAt:        principal.ExpiresAt,
+		RefreshTokenHash: refreshTokenHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error revoke token in db: %w", err)
+	}
+	s.revocations.revokeToken(principal.TokenId, principal.ExpiresAt)
+	return &LogoutResponse{}, nil
+}
+
+func (s *Service) RevokeUserSessions(ctx context.Context, request *RevokeUserSessionsRequest) (_ *RevokeUserSessionsResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditRevokeSessions, request.UserName, &err)
+
+	if err := authorize(request.Principal, permManageUsers); err != nil {
+		return nil, err
+	}
+	var v validator
+	if !v.username("username", request.UserName) {
+		return nil, v.err()
+	}
+	if err := s.revokeUserTokens(ctx, request.UserName); err != nil {
+		return nil, err
+	}
+	return &RevokeUserSessionsResponse{}, nil
+}
+
+func (s *Service) revokeUserTokens(ctx context.Context, userName string) error {
+	res, err := s.db.RevokeUserTokens(ctx, &storage.RevokeUserTokensRequest{
+		UserName: userName,
+		// The cutoff is taken from the clock that stamps iat, the database
+		// clock may be off by more than the gap to the next login.
+		RevokedBefore: time.Now(),
+		TokenTTL:      s.config.accessTokenTTL(),
+	})
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error revoke user tokens in db: %w", err)
+	}
+	s.revocations.revokeSession(res.UserId, res.RevokedBefore, res.ExpiresAt)
+	return nil
+}
+
+// syncRevocations cleans up revocations of already expired tokens and loads
+// the ones made through other instances.
+func (s *Service) syncRevocations(ctx context.Context) error {
+	if err := s.db.DeleteExpiredRevocations(ctx); err != nil {
+		return fmt.Errorf("error delete expired revocations in db: %w", err)
+	}
+	revocations, err := s.db.GetRevocations(ctx)
+	if err != nil {
+		return fmt.Errorf("error get revocations from db: %w", err)
+	}
+	s.revocations.merge(revocations)
+	return nil
+}
+
+// revocationCache keeps the revocation list in memory, so that checking a
+// token doesn't cost a database round-trip per request.
+type revocationCache struct {
+	mu sync.RWMutex
+	// tokens maps a revoked jti to the expiry of its token.
+	tokens map[string]time.Time
+	// sessions maps a user to the revocation of all tokens issued before it.
+	sessions map[uint64]sessionRevocation
+}
+
+type sessionRevocation struct {
+	revokedBefore time.Time
+	expiresAt     time.Time
+}
+
+func newRevocationCache() *revocationCache {
+	return &revocationCache{
+		tokens:   make(map[string]time.Time),
+		sessions: make(map[uint64]sessionRevocation),
+	}
+}
+
+func (c *revocationCache) isRevoked(principal *Principal) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if _, ok := c.tokens[principal.TokenId]; ok {
+		return true
+	}
+	session, ok := c.sessions[principal.UserId]
+	return ok && principal.IssuedAt.Before(session.revokedBefore)
+}
+
+func (c *revocationCache) revokeToken(jti string, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[jti] = expiresAt
+}
+
+func (c *revocationCache) revokeSession(userId uint64, revokedBefore, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if current, ok := c.sessions[userId]; ok && current.revokedBefore.After(revokedBefore) {
+		return
+	}
+	c.sessions[userId] = sessionRevocation{
+		revokedBefore: revokedBefore,
+		expiresAt:     expiresAt,
+	}
+}
+
+// merge adds the revocations loaded from the database and drops the expired
+// ones. Entries are never dropped just because the snapshot misses them, a
+// revocation made here could have committed after the snapshot was read.
+func (c *revocationCache) merge(revocations *storage.Revocations) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, token := range revocations.Tokens {
+		c.tokens[token.Jti] = token.ExpiresAt
+	}
+	for _, session := range revocations.Sessions {
+		if current, ok := c.sessions[session.UserId]; ok && current.revokedBefore.After(session.RevokedBefore) {
+			continue
+		}
+		c.sessions[session.UserId] = sessionRevocation{
+			revokedBefore: session.RevokedBefore,
+			expiresAt:     session.ExpiresAt,
+		}
+	}
+	for jti, expiresAt := range c.tokens {
+		if !expiresAt.After(now) {
+			delete(c.tokens, jti)
+		}
+	}
+	for userId, session := range c.sessions {
+		if !session.expiresAt.After(now) {
+			delete(c.sessions, userId)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"log/slog"
+	"slices"
+)
+
+// Role is stored on the user and copied into the access token, so checking it
+// doesn't cost a database round-trip. Changing a role revokes the tokens
+// issued with the old one.
+type Role string
+
+const (
+	RoleUser Role = "user"
+	// RoleAuditor can read everyone's data but change nothing.
+	RoleAuditor Role = "auditor"
+	// RoleAdmin manages the catalog, balances and other users.
+	RoleAdmin Role = "admin"
+)
+
+func (r Role) valid() bool {
+	switch r {
+	case RoleUser, RoleAuditor, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// permission is what an operation needs beyond being authenticated, every
+// user may work with their own account.
+type permission string
+
+const (
+	permReadAll        permission = "read all"
+	permManageCatalog  permission = "manage catalog"
+	permManageUsers    permission = "manage users"
+	permManageBalances permission = "manage balances"
+	// permReadAudit is kept from admins, so that the audit log is reviewed by
+	// someone other than the people whose actions it records.
+	permReadAudit permission = "read audit"
+)
+
+var rolePermissions = map[Role][]permission{
+	RoleAuditor: {permReadAll, permReadAudit},
+	RoleAdmin:   {permReadAll, permManageCatalog, permManageUsers, permManageBalances},
+}
+
+func authorize(principal *Principal, perm permission) error {
+	if principal == nil {
+		return ErrUnauthenticated
+	}
+	if !slices.Contains(rolePermissions[principal.Role], perm) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// subjectId returns the user a read request is about: the principal itself,
+// or the named user for those allowed to read everyone's data.
+func (s *Service) subjectId(ctx context.Context, principal *Principal, userName string) (uint64, error) {
+	if userName == "" {
+		return principal.userId()
+	}
+	var v validator
+	if !v.username("username", userName) {
+		return 0, v.err()
+	}
+	if err := authorize(principal, permReadAll); err != nil {
+		return 0, err
+	}
+	user, err := s.db.GetUserByName(ctx, userName)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return 0, ErrUserNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error get user from db: %w", err)
+	}
+	return user.UserId, nil
+}
+
+func (s *Service) SetUserRole(ctx context.Context, request *SetUserRoleRequest) (_ *SetUserRoleResponse, err error) {
+	ctx = withPrincipal(ctx, request.Principal)
+	defer s.auditFailure(ctx, storage.AuditSetUserRole, request.UserName, &err)
+
+	if err := authorize(request.Principal, permManageUsers); err != nil {
+		return nil, err
+	}
+	var v validator
+	v.username("username", request.UserName)
+	if !request.Role.valid() {
+		v.add(FieldError{Field: "role", Rule: RuleOneOf, Message: "must be user, auditor or admin", Err: ErrUnknownRole})
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	res, err := s.db.SetUserRole(ctx, &storage.SetUserRoleRequest{
+		UserName: request.UserName,
+		Role:     string(request.Role),
+	})
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error set user role in db: %w", err)
+	}
+	if res.Changed {
+		// Tokens carry the role, the old ones must not outlive the change.
+		if err := s.revokeUserTokens(ctx, request.UserName); err != nil {
+			return nil, err
+		}
+	}
+	return &SetUserRoleResponse{}, nil
+}
+
+// grantAdmins gives the admin role to the users listed in the config, so that
+// a fresh installation has someone to hand out the other roles.
+func (s *Service) grantAdmins(ctx context.Context) error {
+	for _, userName := range s.config.Admins {
+		_, err := s.db.SetUserRole(ctx, &storage.SetUserRoleRequest{
+			UserName: userName,
+			Role:     string(RoleAdmin),
+		})
+		if errors.Is(err, storage.ErrUserNotFound) {
+			s.log.Warn("admin from config is not registered", slog.String("username", userName))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error grant admin role in db: %w", err)
+		}
+	}
+	return nil
+}
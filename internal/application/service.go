@@ -2,18 +2,118 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/azaliaz/avito-shop/internal/storage"
+	"golang.org/x/crypto/bcrypt"
 	"log/slog"
+	"regexp"
+	"sync"
 	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=./mocks/service_mock.go -package=mocks
 
 type ShopService interface {
+	Authenticate(ctx context.Context, request *AuthenticateRequest) (*AuthenticateResponse, error)
 	Auth(ctx context.Context, request *AuthRequest) (*AuthResponse, error)
+	Register(ctx context.Context, request *RegisterRequest) (*RegisterResponse, error)
+	Refresh(ctx context.Context, request *RefreshRequest) (*RefreshResponse, error)
+	Logout(ctx context.Context, request *LogoutRequest) (*LogoutResponse, error)
+	ChangePassword(ctx context.Context, request *ChangePasswordRequest) (*ChangePasswordResponse, error)
+	RevokeUserSessions(ctx context.Context, request *RevokeUserSessionsRequest) (*RevokeUserSessionsResponse, error)
+	SetUserRole(ctx context.Context, request *SetUserRoleRequest) (*SetUserRoleResponse, error)
+	AdjustBalance(ctx context.Context, request *AdjustBalanceRequest) (*AdjustBalanceResponse, error)
+	GetJWKS(ctx context.Context, request *GetJWKSRequest) (*GetJWKSResponse, error)
 	GetInfo(ctx context.Context, request *GetInfoRequest) (*GetInfoResponse, error)
 	SendCoin(ctx context.Context, request *SendCoinRequest) (*SendCoinResponse, error)
 	BuyItem(ctx context.Context, request *BuyItemRequest) (*BuyItemResponse, error)
+	GetItems(ctx context.Context, request *GetItemsRequest) (*GetItemsResponse, error)
+	GetItem(ctx context.Context, request *GetItemRequest) (*GetItemResponse, error)
+	CreateItem(ctx context.Context, request *CreateItemRequest) (*CreateItemResponse, error)
+	UpdateItemPrice(ctx context.Context, request *UpdateItemPriceRequest) (*UpdateItemPriceResponse, error)
+	RenameItem(ctx context.Context, request *RenameItemRequest) (*RenameItemResponse, error)
+	RetireItem(ctx context.Context, request *RetireItemRequest) (*RetireItemResponse, error)
+	GetItemPrices(ctx context.Context, request *GetItemPricesRequest) (*GetItemPricesResponse, error)
+	RestockItem(ctx context.Context, request *RestockItemRequest) (*RestockItemResponse, error)
+	Checkout(ctx context.Context, request *CheckoutRequest) (*CheckoutResponse, error)
+	GetOrders(ctx context.Context, request *GetOrdersRequest) (*GetOrdersResponse, error)
+	ReturnOrder(ctx context.Context, request *ReturnOrderRequest) (*ReturnOrderResponse, error)
+	CheckLedger(ctx context.Context, request *CheckLedgerRequest) (*CheckLedgerResponse, error)
+	GetHistory(ctx context.Context, request *GetHistoryRequest) (*GetHistoryResponse, error)
+	GetAuditEvents(ctx context.Context, request *GetAuditEventsRequest) (*GetAuditEventsResponse, error)
+}
+
+var (
+	// ErrInvalidRequest is wrapped by ValidationError, which tells the fields
+	// of the request that break a rule.
+	ErrInvalidRequest    = errors.New("invalid request")
+	ErrItemNotFound      = errors.New("item not found")
+	ErrItemExists        = errors.New("item already exists")
+	ErrForbidden         = errors.New("forbidden")
+	ErrItemSoldOut       = errors.New("item is sold out")
+	ErrItemUnlimited     = errors.New("item stock is unlimited")
+	ErrInsufficientFunds = errors.New("not enough coins")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrReturnExpired     = errors.New("return window has expired")
+	ErrNothingToReturn   = errors.New("not enough units to return")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again
+	// with a different operation or different parameters.
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was used for another request")
+	ErrIdempotencyKeyTooLong = errors.New("idempotency key is too long")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrMemoTooLong           = errors.New("memo is too long")
+	// ErrInvalidCredentials is the only login failure shown to clients, it
+	// wraps ErrUnknownUser or ErrBadPassword with the actual reason.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUnknownUser        = errors.New("unknown user")
+	ErrBadPassword        = errors.New("bad password")
+	ErrUserExists         = errors.New("user already exists")
+	ErrInvalidUsername    = errors.New("invalid username")
+	ErrInvalidPassword    = errors.New("invalid password")
+	// ErrInvalidRefreshToken is returned for unknown, expired and reused
+	// refresh tokens, a reuse additionally wraps ErrRefreshTokenReused.
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrInvalidToken        = errors.New("invalid token")
+	// ErrUnauthenticated is returned when a request that needs a user comes
+	// without a principal.
+	ErrUnauthenticated = errors.New("request is not authenticated")
+	ErrUserNotFound    = errors.New("user not found")
+	// ErrRecipientNotFound is returned when coins are sent to an unknown user.
+	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrSelfTransfer      = errors.New("cannot send coins to yourself")
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrReasonRequired    = errors.New("reason is required")
+	ErrReasonTooLong     = errors.New("reason is too long")
+	// ErrTooManyAttempts is wrapped by TooManyAttemptsError.
+	ErrTooManyAttempts = errors.New("too many login attempts")
+)
+
+const (
+	// maxIdempotencyKeyLength limits the Idempotency-Key value accepted from clients.
+	maxIdempotencyKeyLength = 255
+	// maxMemoLength limits the transfer memo, in characters.
+	maxMemoLength = 200
+)
+
+// Principal is the user a request was authenticated as, the facades resolve it
+// with Authenticate once per request and pass it along instead of the token.
+type Principal struct {
+	UserId uint64
+	Role   Role
+	// TokenId is the jti of the access token.
+	TokenId   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type AuthenticateRequest struct {
+	Token string
+}
+
+type AuthenticateResponse struct {
+	Principal *Principal
 }
 
 type AuthRequest struct {
@@ -22,11 +122,117 @@ type AuthRequest struct {
 }
 
 type AuthResponse struct {
-	Token string
+	Token        string
+	RefreshToken string
+	// ExpiresIn is the lifetime of Token.
+	ExpiresIn time.Duration
+}
+
+type RegisterRequest struct {
+	Password string
+	Username string
+}
+
+type RegisterResponse struct {
+	Token        string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+type RefreshRequest struct {
+	RefreshToken string
+}
+
+type RefreshResponse struct {
+	Token        string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+type LogoutRequest struct {
+	Principal *Principal
+	// RefreshToken is revoked together with the access token, it is optional.
+	RefreshToken string
+}
+
+type LogoutResponse struct{}
+
+type ChangePasswordRequest struct {
+	Principal       *Principal
+	CurrentPassword string
+	NewPassword     string
+}
+
+// ChangePasswordResponse carries a new pair of tokens, the refresh tokens
+// issued before the change no longer work.
+type ChangePasswordResponse struct {
+	Token        string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+type RevokeUserSessionsRequest struct {
+	Principal *Principal
+	UserName  string
+}
+
+type RevokeUserSessionsResponse struct{}
+
+type SetUserRoleRequest struct {
+	Principal *Principal
+	UserName  string
+	Role      Role
+}
+
+type SetUserRoleResponse struct{}
+
+type AdjustBalanceRequest struct {
+	Principal *Principal
+	UserName  string
+	// Amount is credited when positive and debited when negative.
+	Amount int
+	// Reason is shown as the memo of the transfer, it is required and at most
+	// maxMemoLength characters long.
+	Reason string
+	// Force allows a debit to take the balance below zero.
+	Force bool
+	// IdempotencyKey makes retries of the same adjustment safe, empty disables it.
+	IdempotencyKey string
+}
+
+type AdjustBalanceResponse struct {
+	Balance int
+	// Replayed is set when the adjustment was not run again because the
+	// idempotency key had already been used by a successful request.
+	Replayed bool
+}
+
+type GetJWKSRequest struct{}
+
+type GetJWKSResponse struct {
+	Keys []*JSONWebKey
+}
+
+// JSONWebKey is the public part of a signing key as described in RFC 7517,
+// fields that don't apply to the key type are empty.
+type JSONWebKey struct {
+	Kty string
+	Kid string
+	Alg string
+	Use string
+	// N and E are the RSA modulus and exponent.
+	N string
+	E string
+	// Crv, X and Y are the EC or OKP curve and point, Y is empty for OKP.
+	Crv string
+	X   string
+	Y   string
 }
 
 type GetInfoRequest struct {
-	Token string
+	Principal *Principal
+	// UserName selects another user, only auditors and admins may set it.
+	UserName string
 }
 
 type GetInfoResponse struct {
@@ -44,6 +250,75 @@ type Transaction struct {
 	Amount    int
 	FromUser  string
 	ToUser    string
+	Memo      string
+	CreatedAt time.Time
+}
+
+type GetHistoryRequest struct {
+	Principal *Principal
+	// UserName selects another user, only auditors and admins may set it.
+	UserName string
+	// Direction is "sent", "received" or empty for both.
+	Direction string
+	// Counterparty keeps only transfers with this user, empty disables it.
+	Counterparty string
+	// From and To bound the transfer time as [From, To), zero means unbounded.
+	From time.Time
+	To   time.Time
+	// Limit is the page size, zero means the default page size.
+	Limit int
+	// Cursor is NextCursor of the previous page, empty for the first page.
+	Cursor string
+}
+
+type GetHistoryResponse struct {
+	Transactions []*HistoryEntry
+	// NextCursor points to the next page, it is empty on the last page.
+	NextCursor string
+}
+
+type HistoryEntry struct {
+	Id        uint64
+	Direction string
+	FromUser  string
+	ToUser    string
+	Amount    int
+	Memo      string
+	CreatedAt time.Time
+}
+
+type GetAuditEventsRequest struct {
+	Principal *Principal
+	// Actor, Action, Outcome and IP keep only matching events, empty disables
+	// the filter.
+	Actor   string
+	Action  string
+	Outcome string
+	IP      string
+	// From and To bound the event time as [From, To), zero means unbounded.
+	From time.Time
+	To   time.Time
+	// Limit is the page size, zero means the default page size.
+	Limit int
+	// Cursor is NextCursor of the previous page, empty for the first page.
+	Cursor string
+}
+
+type GetAuditEventsResponse struct {
+	Events []*AuditEvent
+	// NextCursor points to the next page, it is empty on the last page.
+	NextCursor string
+}
+
+type AuditEvent struct {
+	Id uint64
+	// Actor is the username of who performed the operation, empty if unknown.
+	Actor     string
+	Action    string
+	Target    string
+	IP        string
+	Outcome   string
+	Details   map[string]any
 	CreatedAt time.Time
 }
 
@@ -53,25 +328,197 @@ type ProductStock struct {
 }
 
 type SendCoinRequest struct {
-	Token  string
-	Amount int
-	ToUser string
+	Principal *Principal
+	Amount    int
+	ToUser    string
+	// Memo is an optional message for the receiver, at most maxMemoLength
+	// characters long.
+	Memo string
+	// IdempotencyKey makes retries of the same transfer safe, empty disables it.
+	IdempotencyKey string
 }
 
-type SendCoinResponse struct{}
+type SendCoinResponse struct {
+	// Replayed is set when the transfer was not run again because the
+	// idempotency key had already been used by a successful request.
+	Replayed bool
+}
 
 type BuyItemRequest struct {
-	Token string
-	Item  string
+	Principal *Principal
+	Item      string
+	// Quantity is the number of units to buy, zero means a single unit.
+	Quantity int
+	// IdempotencyKey makes retries of the same purchase safe, empty disables it.
+	IdempotencyKey string
 }
 
 type BuyItemResponse struct {
+	// Replayed is set when the purchase was not run again because the
+	// idempotency key had already been used by a successful request.
+	Replayed bool
+}
+
+type CartItem struct {
+	Item     string
+	Quantity int
+}
+
+type CheckoutRequest struct {
+	Principal *Principal
+	Items     []*CartItem
+}
+
+type CheckoutResponse struct {
+	Total int
+}
+
+type Order struct {
+	Id               uint64
+	Item             string
+	Price            int
+	Quantity         int
+	ReturnedQuantity int
+	Refunds          []*Refund
+	CreatedAt        time.Time
+}
+
+type Refund struct {
+	Quantity  int
+	Amount    int
+	CreatedAt time.Time
+}
+
+type GetOrdersRequest struct {
+	Principal *Principal
+	// UserName selects another user, only auditors and admins may set it.
+	UserName string
+	// Limit is the page size, zero means the default page size.
+	Limit  int
+	Offset int
+}
+
+type GetOrdersResponse struct {
+	Orders []*Order
+	Total  int
+}
+
+type ReturnOrderRequest struct {
+	Principal *Principal
+	OrderId   uint64
+	// Quantity is the number of units to return, zero means all units that
+	// have not been returned yet.
+	Quantity int
+}
+
+type ReturnOrderResponse struct {
+	Amount int
+}
+
+type CheckLedgerRequest struct {
+	Principal *Principal
+}
+
+type CheckLedgerResponse struct {
+	// Mismatches lists users whose cached balance disagrees with the ledger,
+	// it is empty when the balances are consistent.
+	Mismatches []*BalanceMismatch
+}
+
+type BalanceMismatch struct {
+	UserName      string
+	Balance       int64
+	LedgerBalance int64
+}
+
+type Item struct {
+	Name  string
+	Price int
+	// Stock is the number of units left, nil means unlimited supply.
+	Stock *int
+}
+
+type GetItemsRequest struct{}
+
+type GetItemsResponse struct {
+	Items []*Item
+}
+
+type GetItemRequest struct {
+	Name string
+}
+
+type GetItemResponse struct {
+	Item *Item
+}
+
+type CreateItemRequest struct {
+	Principal *Principal
+	Name      string
+	Price     int
+	Stock     *int
+}
+
+type CreateItemResponse struct{}
+
+type UpdateItemPriceRequest struct {
+	Principal *Principal
+	Name      string
+	Price     int
+}
+
+type UpdateItemPriceResponse struct{}
+
+type RenameItemRequest struct {
+	Principal *Principal
+	Name      string
+	NewName   string
+}
+
+type RenameItemResponse struct{}
+
+type RetireItemRequest struct {
+	Principal *Principal
+	Name      string
+}
+
+type RetireItemResponse struct{}
+
+type GetItemPricesRequest struct {
+	Principal *Principal
+	Name      string
+}
+
+type GetItemPricesResponse struct {
+	Prices []*ItemPrice
+}
+
+type RestockItemRequest struct {
+	Principal *Principal
+	Name      string
+	Amount    int
+}
+
+type RestockItemResponse struct {
+	Stock int
+}
+
+type ItemPrice struct {
+	Price     int
+	ValidFrom time.Time
 }
 
 type Service struct {
-	log    *slog.Logger
-	config *Config
-	db     storage.ShopStorage
+	log         *slog.Logger
+	config      *Config
+	db          storage.ShopStorage
+	revocations *revocationCache
+	keys        *keySet
+	stop        chan struct{}
+	stopOnce    sync.Once
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
 func NewService(
@@ -80,20 +527,79 @@ func NewService(
 	db storage.ShopStorage,
 ) *Service {
 	return &Service{
-		log:    logger,
-		config: config,
-		db:     db,
+		log:         logger,
+		config:      config,
+		db:          db,
+		revocations: newRevocationCache(),
+		keys:        legacyKeySet(config),
+		stop:        make(chan struct{}),
 	}
 }
 
 func (s *Service) Init() error {
+	if _, err := regexp.Compile(s.config.UsernamePattern); err != nil {
+		return fmt.Errorf("invalid username pattern: %w", err)
+	}
+	switch s.config.passwordHash() {
+	case hashBcrypt, hashArgon2id:
+	default:
+		return fmt.Errorf("unknown password hash %q", s.config.PasswordHash)
+	}
+	if cost := s.config.bcryptCost(); cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	keys, err := loadKeySet(s.config)
+	if err != nil {
+		return fmt.Errorf("error load signing keys: %w", err)
+	}
+	s.keys = keys
+	if err := s.syncRevocations(context.Background()); err != nil {
+		return fmt.Errorf("error load token revocations: %w", err)
+	}
+	if err := s.grantAdmins(context.Background()); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (s *Service) Run(ctx context.Context) {
+	syncTicker := time.NewTicker(s.config.revocationSyncInterval())
+	defer syncTicker.Stop()
+	cleanupTicker := time.NewTicker(s.config.cleanupInterval())
+	defer cleanupTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case <-syncTicker.C:
+			if err := s.syncRevocations(ctx); err != nil {
+				s.log.Error("sync token revocations", slog.String("err", err.Error()))
+			}
+		case <-cleanupTicker.C:
+			s.cleanup(ctx)
+		}
+	}
+}
 
+// cleanup deletes the state that is no longer needed, a failed step is retried
+// on the next tick.
+func (s *Service) cleanup(ctx context.Context) {
+	if err := s.db.DeleteStaleAuthAttempts(ctx, s.config.loginFailureWindow()); err != nil {
+		s.log.Error("delete stale auth attempts", slog.String("err", err.Error()))
+	}
+	if err := s.db.DeleteExpiredIdempotencyKeys(ctx); err != nil {
+		s.log.Error("delete expired idempotency keys", slog.String("err", err.Error()))
+	}
+	if err := s.db.DeleteExpiredRefreshTokens(ctx); err != nil {
+		s.log.Error("delete expired refresh tokens", slog.String("err", err.Error()))
+	}
 }
 
+// Stop ends Run, it may be called more than once.
 func (s *Service) Stop() {
-
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
 }
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdjustBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.AdjustBalanceRequest
+		want func(storage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error)
+	}{
+		{
+			name: "credit",
+			req:  &application.AdjustBalanceRequest{Principal: adminPrincipal, UserName: "user2", Amount: 100, Reason: " bonus "},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				mockStorage.EXPECT().AdjustBalance(gomock.Any(), &storage.AdjustBalanceRequest{
+					AdminId:           1,
+					UserName:          "user2",
+					Amount:            100,
+					Reason:            "bonus",
+					IdempotencyKeyTTL: 24 * time.Hour,
+				}).Return(&storage.AdjustBalanceResponse{Balance: 1100}, nil)
+				return &application.AdjustBalanceResponse{Balance: 1100}, nil
+			},
+		},
+		{
+			name: "forced debit",
+			req: &application.AdjustBalanceRequest{Principal: adminPrincipal, UserName: "user2", Amount: -2000,
+				Reason: "duplicate grant", Force: true, IdempotencyKey: "key"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				mockStorage.EXPECT().AdjustBalance(gomock.Any(), &storage.AdjustBalanceRequest{
+					AdminId:           1,
+					UserName:          "user2",
+					Amount:            -2000,
+					Reason:            "duplicate grant",
+					Force:             true,
+					IdempotencyKey:    "key",
+					IdempotencyKeyTTL: 24 * time.Hour,
+				}).Return(&storage.AdjustBalanceResponse{Balance: -1000, Replayed: true}, nil)
+				return &application.AdjustBalanceResponse{Balance: -1000, Replayed: true}, nil
+			},
+		},
+		{
+			name: "insufficient funds",
+			req:  &application.AdjustBalanceRequest{Principal: adminPrincipal, UserName: "user2", Amount: -2000, Reason: "mistake"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				mockStorage.EXPECT().AdjustBalance(gomock.Any(), gomock.Any()).Return(nil, storage.ErrInsufficientFunds)
+				return nil, application.ErrInsufficientFunds
+			},
+		},
+		{
+			name: "forced credit",
+			req:  &application.AdjustBalanceRequest{Principal: adminPrincipal, UserName: "user2", Amount: 100, Reason: "bonus", Force: true},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				return nil, invalidField("force", application.RuleNotAllowed, "is only allowed for a debit", nil)
+			},
+		},
+		{
+			name: "zero amount",
+			req:  &application.AdjustBalanceRequest{Principal: adminPrincipal, UserName: "user2", Reason: "bonus"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				return nil, invalidField("amount", application.RuleRequired, "must not be zero", nil)
+			},
+		},
+		{
+			name: "missing reason",
+			req:  &application.AdjustBalanceRequest{Principal: adminPrincipal, UserName: "user2", Amount: 100, Reason: "  "},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				return nil, invalidField("reason", application.RuleRequired, "is required", application.ErrReasonRequired)
+			},
+		},
+		{
+			name: "reason too long",
+			req:  &application.AdjustBalanceRequest{Principal: adminPrincipal, UserName: "user2", Amount: 100, Reason: strings.Repeat("a", 201)},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				return nil, invalidField("reason", application.RuleMax, "must be at most 200 characters long", application.ErrReasonTooLong)
+			},
+		},
+		{
+			name: "auditor",
+			req:  &application.AdjustBalanceRequest{Principal: auditorPrincipal, UserName: "user2", Amount: 100, Reason: "bonus"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				return nil, application.ErrForbidden
+			},
+		},
+		{
+			name: "user not found",
+			req:  &application.AdjustBalanceRequest{Principal: adminPrincipal, UserName: "nobody", Amount: 100, Reason: "bonus"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				mockStorage.EXPECT().AdjustBalance(gomock.Any(), gomock.Any()).Return(nil, storage.ErrUserNotFound)
+				return nil, application.ErrUserNotFound
+			},
+		},
+		{
+			name: "error adjust balance in storage",
+			req:  &application.AdjustBalanceRequest{Principal: adminPrincipal, UserName: "user2", Amount: 100, Reason: "bonus"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AdjustBalanceResponse, error) {
+				mockStorage.EXPECT().AdjustBalance(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("storage error"))
+				return nil, fmt.Errorf("error adjust balance in db: %w", fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.AdjustBalance(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
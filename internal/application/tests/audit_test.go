@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// allowAuditFailures lets a test run operations that fail without spelling
+// out the audit event of every failure.
+func allowAuditFailures(mockStorage *mocks.MockShopStorage) {
+	mockStorage.EXPECT().RecordAuditEvent(gomock.Any(), gomock.Cond(func(event *storage.AuditEvent) bool {
+		return event.Outcome == storage.AuditFailure
+	})).Return(nil).AnyTimes()
+}
+
+func TestAuditFailure(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	mockStorage.EXPECT().SendCoin(gomock.Any(), gomock.Any()).Return(nil, storage.ErrInsufficientFunds)
+	mockStorage.EXPECT().RecordAuditEvent(gomock.Cond(func(ctx context.Context) bool {
+		return storage.ActorFromContext(ctx) == storage.Actor{UserId: 1, IP: "10.0.0.1"}
+	}), &storage.AuditEvent{
+		Action:  storage.AuditSendCoin,
+		Target:  "user2",
+		Outcome: storage.AuditFailure,
+		Details: map[string]any{"error": "not enough coins"},
+	}).Return(nil)
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	ctx := application.WithClientIP(context.Background(), "10.0.0.1")
+	_, err := app.SendCoin(ctx, &application.SendCoinRequest{Principal: userPrincipal, Amount: 100, ToUser: "user2"})
+
+	assert.Error(t, err)
+}
+
+func TestAuditFailure_Login(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(&storage.AuthResponse{
+		UserId:   1,
+		UserName: "user1",
+		PassHash: passHash,
+	}, nil)
+	mockStorage.EXPECT().RecordAuditEvent(gomock.Cond(func(ctx context.Context) bool {
+		return storage.ActorFromContext(ctx) == storage.Actor{IP: "10.0.0.1"}
+	}), &storage.AuditEvent{
+		Action:  storage.AuditLogin,
+		Target:  "user1",
+		Outcome: storage.AuditFailure,
+		Details: map[string]any{"error": "invalid credentials: bad password"},
+	}).Return(nil)
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	ctx := application.WithClientIP(context.Background(), "10.0.0.1")
+	_, err := app.Auth(ctx, &application.AuthRequest{Username: "user1", Password: "wrong"})
+
+	assert.ErrorIs(t, err, application.ErrInvalidCredentials)
+}
+
+func TestAuditFailure_LoginRejectedBeforePasswordCheck(t *testing.T) {
+	// The mock fails the test on any call, a malformed attempt touches neither
+	// the throttle nor the audit log.
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	_, err := app.Auth(context.Background(), &application.AuthRequest{Username: "user 1", Password: "pass"})
+
+	assert.ErrorIs(t, err, application.ErrInvalidRequest)
+}
+
+func TestAuditActor(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	mockStorage.EXPECT().RetireItem(gomock.Cond(func(ctx context.Context) bool {
+		return storage.ActorFromContext(ctx) == storage.Actor{UserId: 1, IP: "10.0.0.1"}
+	}), "cup").Return(nil)
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	ctx := application.WithClientIP(context.Background(), "10.0.0.1")
+	_, err := app.RetireItem(ctx, &application.RetireItemRequest{Principal: adminPrincipal, Name: "cup"})
+
+	assert.NoError(t, err)
+}
+
+func TestGetAuditEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		req  *application.GetAuditEventsRequest
+		want func(storage *mocks.MockShopStorage) (*application.GetAuditEventsResponse, error)
+	}{
+		{
+			name: "last page",
+			req: &application.GetAuditEventsRequest{Principal: auditorPrincipal, Actor: "admin", Outcome: storage.AuditSuccess,
+				From: from},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetAuditEventsResponse, error) {
+				mockStorage.EXPECT().GetAuditEvents(gomock.Any(), &storage.GetAuditEventsRequest{
+					Actor:   "admin",
+					Outcome: storage.AuditSuccess,
+					From:    &from,
+					Limit:   51,
+				}).Return([]*storage.AuditEvent{{
+					Id:        7,
+					ActorId:   1,
+					ActorName: "admin",
+					Action:    storage.AuditSetUserRole,
+					Target:    "user2",
+					IP:        "10.0.0.1",
+					Outcome:   storage.AuditSuccess,
+					Details:   map[string]any{"role": "auditor", "previousRole": "user"},
+					CreatedAt: createdAt,
+				}}, nil)
+				return &application.GetAuditEventsResponse{
+					Events: []*application.AuditEvent{{
+						Id:        7,
+						Actor:     "admin",
+						Action:    storage.AuditSetUserRole,
+						Target:    "user2",
+						IP:        "10.0.0.1",
+						Outcome:   storage.AuditSuccess,
+						Details:   map[string]any{"role": "auditor", "previousRole": "user"},
+						CreatedAt: createdAt,
+					}},
+				}, nil
+			},
+		},
+		{
+			name: "next page",
+			req:  &application.GetAuditEventsRequest{Principal: auditorPrincipal, Limit: 1},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetAuditEventsResponse, error) {
+				mockStorage.EXPECT().GetAuditEvents(gomock.Any(), &storage.GetAuditEventsRequest{Limit: 2}).Return([]*storage.AuditEvent{
+					{Id: 8, Action: storage.AuditLogin, Outcome: storage.AuditFailure, CreatedAt: createdAt},
+					{Id: 7, Action: storage.AuditLogin, Outcome: storage.AuditSuccess, CreatedAt: createdAt},
+				}, nil)
+				return &application.GetAuditEventsResponse{
+					Events: []*application.AuditEvent{
+						{Id: 8, Action: storage.AuditLogin, Outcome: storage.AuditFailure, CreatedAt: createdAt},
+					},
+					NextCursor: "MTczODQxMTIwMDAwMDAwMDo4",
+				}, nil
+			},
+		},
+		{
+			name: "admin",
+			req:  &application.GetAuditEventsRequest{Principal: adminPrincipal},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetAuditEventsResponse, error) {
+				return nil, application.ErrForbidden
+			},
+		},
+		{
+			name: "unknown outcome",
+			req:  &application.GetAuditEventsRequest{Principal: auditorPrincipal, Outcome: "maybe"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetAuditEventsResponse, error) {
+				return nil, invalidField("outcome", application.RuleOneOf, "must be success or failure", nil)
+			},
+		},
+		{
+			name: "limit too large",
+			req:  &application.GetAuditEventsRequest{Principal: auditorPrincipal, Limit: 501},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetAuditEventsResponse, error) {
+				return nil, invalidField("limit", application.RuleMax, "must be between 1 and 500", nil)
+			},
+		},
+		{
+			name: "invalid cursor",
+			req:  &application.GetAuditEventsRequest{Principal: auditorPrincipal, Cursor: "!"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetAuditEventsResponse, error) {
+				return nil, invalidField("cursor", application.RuleFormat, "is not a valid cursor", application.ErrInvalidCursor)
+			},
+		},
+		{
+			name: "error get audit events from storage",
+			req:  &application.GetAuditEventsRequest{Principal: auditorPrincipal},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetAuditEventsResponse, error) {
+				mockStorage.EXPECT().GetAuditEvents(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("storage error"))
+				return nil, fmt.Errorf("error get audit events from db: %w", fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.GetAuditEvents(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
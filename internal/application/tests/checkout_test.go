@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"math"
+	"testing"
+)
+
+func TestCheckout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.CheckoutRequest
+		want func(storage *mocks.MockShopStorage) (*application.CheckoutResponse, error)
+	}{
+		{
+			name: "success",
+			req: &application.CheckoutRequest{
+				Principal: adminPrincipal,
+				Items: []*application.CartItem{
+					{Item: "pen", Quantity: 2},
+					{Item: "cup"},
+					{Item: "pen", Quantity: 3},
+				},
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.CheckoutResponse, error) {
+				mockStorage.EXPECT().Checkout(gomock.Any(), &storage.CheckoutRequest{
+					UserId: 1,
+					Items: []*storage.CartItem{
+						{Item: "cup", Quantity: 1},
+						{Item: "pen", Quantity: 5},
+					},
+				}).Return(&storage.CheckoutResponse{Total: 70}, nil)
+				return &application.CheckoutResponse{Total: 70}, nil
+			},
+		},
+		{
+			name: "empty cart",
+			req: &application.CheckoutRequest{
+				Principal: adminPrincipal,
+			},
+			want: func(_ *mocks.MockShopStorage) (*application.CheckoutResponse, error) {
+				return nil, invalidField("items", application.RuleRequired, "must not be empty", nil)
+			},
+		},
+		{
+			name: "negative quantity",
+			req: &application.CheckoutRequest{
+				Principal: adminPrincipal,
+				Items: []*application.CartItem{
+					{Item: "pen", Quantity: -2},
+				},
+			},
+			want: func(_ *mocks.MockShopStorage) (*application.CheckoutResponse, error) {
+				return nil, invalidField("items[0].quantity", application.RuleMin, "must be positive", nil)
+			},
+		},
+		{
+			name: "repeated item over the quantity bound",
+			req: &application.CheckoutRequest{
+				Principal: adminPrincipal,
+				Items: []*application.CartItem{
+					{Item: "pen", Quantity: math.MaxInt32},
+					{Item: "pen", Quantity: 1},
+				},
+			},
+			want: func(_ *mocks.MockShopStorage) (*application.CheckoutResponse, error) {
+				return nil, invalidField("items[1].quantity", application.RuleMax,
+					fmt.Sprintf("must be at most %d together with the other lines of the item", math.MaxInt32), nil)
+			},
+		},
+		{
+			name: "not enough coins",
+			req: &application.CheckoutRequest{
+				Principal: adminPrincipal,
+				Items: []*application.CartItem{
+					{Item: "pink-hoody", Quantity: 3},
+				},
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.CheckoutResponse, error) {
+				mockStorage.EXPECT().Checkout(gomock.Any(), &storage.CheckoutRequest{
+					UserId: 1,
+					Items: []*storage.CartItem{
+						{Item: "pink-hoody", Quantity: 3},
+					},
+				}).Return(nil, storage.ErrInsufficientFunds)
+				return nil, application.ErrInsufficientFunds
+			},
+		},
+		{
+			name: "error checkout in db",
+			req: &application.CheckoutRequest{
+				Principal: adminPrincipal,
+				Items: []*application.CartItem{
+					{Item: "cup", Quantity: 1},
+				},
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.CheckoutResponse, error) {
+				mockStorage.EXPECT().Checkout(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+				return nil, fmt.Errorf("error checkout in db: %w", fmt.Errorf("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.Checkout(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestGetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	first := time.Date(2025, 2, 14, 12, 0, 0, 0, time.UTC)
+	second := first.Add(-time.Hour)
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", second.UnixMicro(), 4)))
+
+	tests := []struct {
+		name string
+		req  *application.GetHistoryRequest
+		want func(storage *mocks.MockShopStorage) (*application.GetHistoryResponse, error)
+	}{
+		{
+			name: "first page with next cursor",
+			req: &application.GetHistoryRequest{
+				Principal:    adminPrincipal,
+				Direction:    "sent",
+				Counterparty: "user2",
+				Limit:        2,
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetHistoryResponse, error) {
+				mockStorage.EXPECT().GetHistory(gomock.Any(), &storage.GetHistoryRequest{
+					UserId:       1,
+					Direction:    "sent",
+					Counterparty: "user2",
+					Limit:        3,
+				}).Return([]*storage.HistoryEntry{
+					{Id: 5, Direction: "sent", FromUser: "user1", ToUser: "user2", Amount: 10, CreatedAt: first},
+					{Id: 4, Direction: "sent", FromUser: "user1", ToUser: "user2", Amount: 20, CreatedAt: second},
+					{Id: 1, Direction: "sent", FromUser: "user1", ToUser: "user2", Amount: 30, CreatedAt: second},
+				}, nil)
+				return &application.GetHistoryResponse{
+					Transactions: []*application.HistoryEntry{
+						{Id: 5, Direction: "sent", FromUser: "user1", ToUser: "user2", Amount: 10, CreatedAt: first},
+						{Id: 4, Direction: "sent", FromUser: "user1", ToUser: "user2", Amount: 20, CreatedAt: second},
+					},
+					NextCursor: cursor,
+				}, nil
+			},
+		},
+		{
+			name: "last page by cursor",
+			req: &application.GetHistoryRequest{
+				Principal: adminPrincipal,
+				From:      second.Add(-time.Hour),
+				To:        first,
+				Cursor:    cursor,
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetHistoryResponse, error) {
+				from := second.Add(-time.Hour)
+				to := first
+				mockStorage.EXPECT().GetHistory(gomock.Any(), &storage.GetHistoryRequest{
+					UserId: 1,
+					From:   &from,
+					To:     &to,
+					Limit:  21,
+					After:  &storage.HistoryCursor{CreatedAt: second, Id: 4},
+				}).Return([]*storage.HistoryEntry{
+					{Id: 1, Direction: "sent", FromUser: "user1", ToUser: "user2", Amount: 30, CreatedAt: second},
+				}, nil)
+				return &application.GetHistoryResponse{
+					Transactions: []*application.HistoryEntry{
+						{Id: 1, Direction: "sent", FromUser: "user1", ToUser: "user2", Amount: 30, CreatedAt: second},
+					},
+				}, nil
+			},
+		},
+		{
+			name: "invalid direction",
+			req: &application.GetHistoryRequest{
+				Principal: adminPrincipal,
+				Direction: "both",
+			},
+			want: func(_ *mocks.MockShopStorage) (*application.GetHistoryResponse, error) {
+				return nil, invalidField("direction", application.RuleOneOf, "must be sent or received", nil)
+			},
+		},
+		{
+			name: "invalid cursor",
+			req: &application.GetHistoryRequest{
+				Principal: adminPrincipal,
+				Cursor:    "abc",
+			},
+			want: func(_ *mocks.MockShopStorage) (*application.GetHistoryResponse, error) {
+				return nil, invalidField("cursor", application.RuleFormat, "is not a valid cursor", application.ErrInvalidCursor)
+			},
+		},
+		{
+			name: "empty date range",
+			req: &application.GetHistoryRequest{
+				Principal: adminPrincipal,
+				From:      first,
+				To:        second,
+			},
+			want: func(_ *mocks.MockShopStorage) (*application.GetHistoryResponse, error) {
+				return nil, invalidField("to", application.RuleAfter, "must be after from", nil)
+			},
+		},
+		{
+			name: "error get history from db",
+			req:  &application.GetHistoryRequest{Principal: adminPrincipal},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetHistoryResponse, error) {
+				mockStorage.EXPECT().GetHistory(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+				return nil, fmt.Errorf("error get history from db: %w", fmt.Errorf("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.GetHistory(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testKeys struct {
+	rsa     *rsa.PrivateKey
+	ecdsa   *ecdsa.PrivateKey
+	ed25519 ed25519.PrivateKey
+	paths   map[string]string
+}
+
+// newTestKeys writes one private key per algorithm into a temporary directory.
+func newTestKeys(t *testing.T) *testKeys {
+	dir := t.TempDir()
+	keys := &testKeys{paths: make(map[string]string)}
+	write := func(alg, pemType string, der []byte) {
+		path := filepath.Join(dir, alg+".pem")
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: der}), 0o600))
+		keys.paths[alg] = path
+	}
+
+	var err error
+	keys.rsa, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	write("RS256", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(keys.rsa))
+
+	keys.ecdsa, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(keys.ecdsa)
+	require.NoError(t, err)
+	write("ES256", "EC PRIVATE KEY", der)
+
+	_, keys.ed25519, err = ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKCS8PrivateKey(keys.ed25519)
+	require.NoError(t, err)
+	write("EdDSA", "PRIVATE KEY", der)
+
+	path := filepath.Join(dir, "HS256.key")
+	require.NoError(t, os.WriteFile(path, []byte("hmac-secret\n"), 0o600))
+	keys.paths["HS256"] = path
+	return keys
+}
+
+func (k *testKeys) entry(kid, alg string) string {
+	return fmt.Sprintf("%s:%s:%s", kid, alg, k.paths[alg])
+}
+
+// initService runs Init against a storage without revocations.
+func initService(t *testing.T, config *application.Config, mockStorage *mocks.MockShopStorage) (*application.Service, error) {
+	mockStorage.EXPECT().DeleteExpiredRevocations(gomock.Any()).Return(nil).AnyTimes()
+	mockStorage.EXPECT().GetRevocations(gomock.Any()).Return(&storage.Revocations{}, nil).AnyTimes()
+	app := application.NewService(nil, config, mockStorage)
+	return app, app.Init()
+}
+
+// checkAccepted fails unless the token passes authentication.
+func checkAccepted(t *testing.T, app *application.Service, token string) {
+	res, err := app.Authenticate(context.Background(), &application.AuthenticateRequest{Token: token})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), res.Principal.UserId)
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"user_id": 1,
+		"iss":     "avito-shop",
+		"aud":     "avito-shop",
+		"iat":     time.Now().Unix(),
+		"exp":     time.Now().Add(time.Minute).Unix(),
+		"jti":     "jti-" + kid,
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestSigningKeys(t *testing.T) {
+	keys := newTestKeys(t)
+
+	for _, alg := range []string{"RS256", "ES256", "EdDSA", "HS256"} {
+		t.Run(alg, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+			app, err := initService(t, &application.Config{
+				Secret:          "secret",
+				SecretRetiredAt: time.Now(),
+				SigningKeys:     []string{keys.entry("key-1", alg)},
+			}, mockStorage)
+			require.NoError(t, err)
+
+			mockStorage.EXPECT().GetCredentials(gomock.Any(), "log").Return(&storage.AuthResponse{
+				UserId:   1,
+				UserName: "log",
+				PassHash: passHash,
+			}, nil)
+			mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+			res, err := app.Auth(context.Background(), &application.AuthRequest{Username: "log", Password: "pass"})
+			require.NoError(t, err)
+
+			token, _, err := new(jwt.Parser).ParseUnverified(res.Token, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, "key-1", token.Header["kid"])
+			assert.Equal(t, alg, token.Header["alg"])
+
+			checkAccepted(t, app, res.Token)
+			// Tokens issued with Secret before the keys were configured still
+			// work during the grace period.
+			checkAccepted(t, app, adminToken)
+		})
+	}
+}
+
+func TestSigningKeyRotation(t *testing.T) {
+	keys := newTestKeys(t)
+	retired := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	expired := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	app, err := initService(t, &application.Config{
+		SigningKeys: []string{
+			keys.entry("old", "RS256") + ":" + retired,
+			keys.entry("expired", "ES256") + ":" + expired,
+			keys.entry("new", "EdDSA"),
+		},
+		SigningKeyGracePeriod: 10 * time.Minute,
+	}, mockStorage)
+	require.NoError(t, err)
+
+	checkAccepted(t, app, signTestToken(t, jwt.SigningMethodRS256, "old", keys.rsa))
+	checkAccepted(t, app, signTestToken(t, jwt.SigningMethodEdDSA, "new", keys.ed25519))
+
+	_, err = app.Authenticate(context.Background(), &application.AuthenticateRequest{
+		Token: signTestToken(t, jwt.SigningMethodES256, "expired", keys.ecdsa),
+	})
+	assert.ErrorContains(t, err, `signing key "expired" is retired`)
+	_, err = app.Authenticate(context.Background(), &application.AuthenticateRequest{
+		Token: signTestToken(t, jwt.SigningMethodRS256, "unknown", keys.rsa),
+	})
+	assert.ErrorContains(t, err, `unknown signing key "unknown"`)
+	_, err = app.Authenticate(context.Background(), &application.AuthenticateRequest{Token: adminToken})
+	assert.ErrorContains(t, err, `unknown signing key ""`)
+
+	res, err := app.GetJWKS(context.Background(), &application.GetJWKSRequest{})
+	require.NoError(t, err)
+	kids := make([]string, 0, len(res.Keys))
+	for _, key := range res.Keys {
+		kids = append(kids, key.Kid)
+	}
+	assert.Equal(t, []string{"old", "new"}, kids)
+}
+
+func TestSigningKeys_SecretRetirement(t *testing.T) {
+	keys := newTestKeys(t)
+	newApp := func(retiredAt time.Time) *application.Service {
+		app, err := initService(t, &application.Config{
+			Secret:                "secret",
+			SecretRetiredAt:       retiredAt,
+			SigningKeys:           []string{keys.entry("key-1", "EdDSA")},
+			SigningKeyGracePeriod: 10 * time.Minute,
+		}, mocks.NewMockShopStorage(gomock.NewController(t)))
+		require.NoError(t, err)
+		return app
+	}
+
+	checkAccepted(t, newApp(time.Now().Add(-time.Minute)), adminToken)
+
+	// A restart doesn't extend the grace period of the secret.
+	_, err := newApp(time.Now().Add(-time.Hour)).Authenticate(context.Background(), &application.AuthenticateRequest{Token: adminToken})
+	assert.ErrorContains(t, err, `signing key "" is retired`)
+}
+
+func TestSigningKeysConfig(t *testing.T) {
+	keys := newTestKeys(t)
+
+	tests := []struct {
+		name    string
+		config  *application.Config
+		wantErr string
+	}{
+		{
+			name: "unknown active key",
+			config: &application.Config{
+				SigningKeys:      []string{keys.entry("key-1", "RS256")},
+				ActiveSigningKey: "key-2",
+			},
+			wantErr: `active signing key "key-2" is not configured`,
+		},
+		{
+			name: "retired active key",
+			config: &application.Config{
+				SigningKeys:      []string{keys.entry("key-1", "RS256") + ":2020-01-01T00:00:00Z"},
+				ActiveSigningKey: "key-1",
+			},
+			wantErr: `active signing key "key-1" is retired`,
+		},
+		{
+			name: "unsupported algorithm",
+			config: &application.Config{
+				SigningKeys: []string{"key-1:PS256:" + keys.paths["RS256"]},
+			},
+			wantErr: `unsupported algorithm "PS256" of signing key "key-1"`,
+		},
+		{
+			name: "key does not match algorithm",
+			config: &application.Config{
+				SigningKeys: []string{"key-1:ES256:" + keys.paths["RS256"]},
+			},
+			wantErr: `error parse signing key "key-1"`,
+		},
+		{
+			name: "duplicate kid",
+			config: &application.Config{
+				SigningKeys: []string{keys.entry("key-1", "RS256"), keys.entry("key-1", "ES256")},
+			},
+			wantErr: `duplicate signing key "key-1"`,
+		},
+		{
+			name: "secret without retirement time",
+			config: &application.Config{
+				Secret:      "secret",
+				SigningKeys: []string{keys.entry("key-1", "RS256")},
+			},
+			wantErr: "retirement time of the secret is required with signing keys",
+		},
+		{
+			name: "all keys retired",
+			config: &application.Config{
+				SigningKeys: []string{keys.entry("key-1", "RS256") + ":2020-01-01T00:00:00Z"},
+			},
+			wantErr: "no signing key is available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := initService(t, tt.config, mocks.NewMockShopStorage(gomock.NewController(t)))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestGetJWKS(t *testing.T) {
+	keys := newTestKeys(t)
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	app, err := initService(t, &application.Config{
+		Secret:          "secret",
+		SecretRetiredAt: time.Now(),
+		SigningKeys: []string{
+			keys.entry("rsa", "RS256"),
+			keys.entry("ec", "ES256"),
+			keys.entry("ed", "EdDSA"),
+			keys.entry("hmac", "HS256"),
+		},
+	}, mockStorage)
+	require.NoError(t, err)
+
+	res, err := app.GetJWKS(context.Background(), &application.GetJWKSRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Keys, 3)
+
+	assert.Equal(t, "RSA", res.Keys[0].Kty)
+	assert.Equal(t, "RS256", res.Keys[0].Alg)
+	assert.Equal(t, "AQAB", res.Keys[0].E)
+	assert.NotEmpty(t, res.Keys[0].N)
+
+	assert.Equal(t, "EC", res.Keys[1].Kty)
+	assert.Equal(t, "P-256", res.Keys[1].Crv)
+	assert.Len(t, res.Keys[1].X, 43)
+	assert.Len(t, res.Keys[1].Y, 43)
+
+	assert.Equal(t, &application.JSONWebKey{
+		Kty: "OKP",
+		Kid: "ed",
+		Alg: "EdDSA",
+		Use: "sig",
+		Crv: "Ed25519",
+		X:   strings.TrimRight(jwt.EncodeSegment(keys.ed25519.Public().(ed25519.PublicKey)), "="),
+	}, res.Keys[2])
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestCheckLedger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.CheckLedgerRequest
+		want func(storage *mocks.MockShopStorage) (*application.CheckLedgerResponse, error)
+	}{
+		{
+			name: "success",
+			req:  &application.CheckLedgerRequest{Principal: adminPrincipal},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.CheckLedgerResponse, error) {
+				mockStorage.EXPECT().CheckBalances(gomock.Any()).Return([]*storage.BalanceMismatch{
+					{UserId: 2, UserName: "user2", Balance: 900, LedgerBalance: 1000},
+				}, nil)
+				return &application.CheckLedgerResponse{
+					Mismatches: []*application.BalanceMismatch{
+						{UserName: "user2", Balance: 900, LedgerBalance: 1000},
+					},
+				}, nil
+			},
+		},
+		{
+			name: "forbidden",
+			req:  &application.CheckLedgerRequest{Principal: userPrincipal},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.CheckLedgerResponse, error) {
+				return nil, application.ErrForbidden
+			},
+		},
+		{
+			name: "error check balances in db",
+			req:  &application.CheckLedgerRequest{Principal: adminPrincipal},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.CheckLedgerResponse, error) {
+				mockStorage.EXPECT().CheckBalances(gomock.Any()).Return(nil, fmt.Errorf("error"))
+				return nil, fmt.Errorf("error check balances in db: %w", fmt.Errorf("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.CheckLedger(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
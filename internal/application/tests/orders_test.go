@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestGetOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	createdAt := time.Date(2025, 2, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		req  *application.GetOrdersRequest
+		want func(storage *mocks.MockShopStorage) (*application.GetOrdersResponse, error)
+	}{
+		{
+			name: "success with default limit",
+			req: &application.GetOrdersRequest{
+				Principal: adminPrincipal,
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetOrdersResponse, error) {
+				mockStorage.EXPECT().GetOrders(gomock.Any(), &storage.GetOrdersRequest{
+					UserId: 1,
+					Limit:  20,
+				}).Return(&storage.GetOrdersResponse{
+					Orders: []*storage.Order{
+						{
+							Id: 3, Item: "pen", Price: 10, Quantity: 5, ReturnedQuantity: 2, CreatedAt: createdAt,
+							Refunds: []*storage.Refund{
+								{Quantity: 2, Amount: 20, CreatedAt: createdAt},
+							},
+						},
+					},
+					Total: 1,
+				}, nil)
+				return &application.GetOrdersResponse{
+					Orders: []*application.Order{
+						{
+							Id: 3, Item: "pen", Price: 10, Quantity: 5, ReturnedQuantity: 2, CreatedAt: createdAt,
+							Refunds: []*application.Refund{
+								{Quantity: 2, Amount: 20, CreatedAt: createdAt},
+							},
+						},
+					},
+					Total: 1,
+				}, nil
+			},
+		},
+		{
+			name: "success with page",
+			req: &application.GetOrdersRequest{
+				Principal: adminPrincipal,
+				Limit:     5,
+				Offset:    10,
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetOrdersResponse, error) {
+				mockStorage.EXPECT().GetOrders(gomock.Any(), &storage.GetOrdersRequest{
+					UserId: 1,
+					Limit:  5,
+					Offset: 10,
+				}).Return(&storage.GetOrdersResponse{Orders: []*storage.Order{}, Total: 3}, nil)
+				return &application.GetOrdersResponse{Orders: []*application.Order{}, Total: 3}, nil
+			},
+		},
+		{
+			name: "limit too big",
+			req: &application.GetOrdersRequest{
+				Principal: adminPrincipal,
+				Limit:     1000,
+			},
+			want: func(_ *mocks.MockShopStorage) (*application.GetOrdersResponse, error) {
+				return nil, invalidField("limit", application.RuleMax, "must be between 1 and 100", nil)
+			},
+		},
+		{
+			name: "negative offset",
+			req: &application.GetOrdersRequest{
+				Principal: adminPrincipal,
+				Offset:    -1,
+			},
+			want: func(_ *mocks.MockShopStorage) (*application.GetOrdersResponse, error) {
+				return nil, invalidField("offset", application.RuleMin, "must not be negative", nil)
+			},
+		},
+		{
+			name: "error get orders from db",
+			req: &application.GetOrdersRequest{
+				Principal: adminPrincipal,
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.GetOrdersResponse, error) {
+				mockStorage.EXPECT().GetOrders(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+				return nil, fmt.Errorf("error get orders from db: %w", fmt.Errorf("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.GetOrders(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestReturnOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	config := &application.Config{Secret: "secret", ReturnWindow: 14 * 24 * time.Hour}
+
+	tests := []struct {
+		name string
+		req  *application.ReturnOrderRequest
+		want func(storage *mocks.MockShopStorage) (*application.ReturnOrderResponse, error)
+	}{
+		{
+			name: "success",
+			req: &application.ReturnOrderRequest{
+				Principal: adminPrincipal,
+				OrderId:   7,
+				Quantity:  2,
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ReturnOrderResponse, error) {
+				mockStorage.EXPECT().ReturnOrder(gomock.Any(), &storage.ReturnOrderRequest{
+					UserId:       1,
+					OrderId:      7,
+					Quantity:     2,
+					ReturnWindow: 14 * 24 * time.Hour,
+				}).Return(&storage.ReturnOrderResponse{Amount: 40}, nil)
+				return &application.ReturnOrderResponse{Amount: 40}, nil
+			},
+		},
+		{
+			name: "negative quantity",
+			req: &application.ReturnOrderRequest{
+				Principal: adminPrincipal,
+				OrderId:   7,
+				Quantity:  -1,
+			},
+			want: func(_ *mocks.MockShopStorage) (*application.ReturnOrderResponse, error) {
+				return nil, invalidField("quantity", application.RuleMin, "must be positive", nil)
+			},
+		},
+		{
+			name: "order not found",
+			req: &application.ReturnOrderRequest{
+				Principal: adminPrincipal,
+				OrderId:   8,
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ReturnOrderResponse, error) {
+				mockStorage.EXPECT().ReturnOrder(gomock.Any(), gomock.Any()).Return(nil, storage.ErrOrderNotFound)
+				return nil, application.ErrOrderNotFound
+			},
+		},
+		{
+			name: "return window expired",
+			req: &application.ReturnOrderRequest{
+				Principal: adminPrincipal,
+				OrderId:   1,
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ReturnOrderResponse, error) {
+				mockStorage.EXPECT().ReturnOrder(gomock.Any(), gomock.Any()).Return(nil, storage.ErrReturnExpired)
+				return nil, application.ErrReturnExpired
+			},
+		},
+		{
+			name: "error return order in db",
+			req: &application.ReturnOrderRequest{
+				Principal: adminPrincipal,
+				OrderId:   1,
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ReturnOrderResponse, error) {
+				mockStorage.EXPECT().ReturnOrder(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+				return nil, fmt.Errorf("error return order in db: %w", fmt.Errorf("error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, config, mockStorage)
+			got, err := app.ReturnOrder(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestReturnOrder_DefaultReturnWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStorage := mocks.NewMockShopStorage(ctrl)
+	allowAuditFailures(mockStorage)
+	mockStorage.EXPECT().ReturnOrder(gomock.Any(), &storage.ReturnOrderRequest{
+		UserId:       1,
+		OrderId:      7,
+		ReturnWindow: 14 * 24 * time.Hour,
+	}).Return(&storage.ReturnOrderResponse{Amount: 20}, nil)
+
+	// A config without the window, such as an older yaml one, keeps the default.
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	got, err := app.ReturnOrder(context.Background(), &application.ReturnOrderRequest{
+		Principal: adminPrincipal,
+		OrderId:   7,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &application.ReturnOrderResponse{Amount: 20}, got)
+}
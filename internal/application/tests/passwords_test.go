@@ -0,0 +1,239 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// argon2Config hashes with cheap argon2id parameters to keep the tests fast.
+func argon2Config() *application.Config {
+	return &application.Config{
+		Secret:        "secret",
+		PasswordHash:  "argon2id",
+		Argon2Time:    1,
+		Argon2Memory:  1024,
+		Argon2Threads: 1,
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	user := &storage.User{UserId: 1, UserName: "user1", Role: "user"}
+	credentials := &storage.AuthResponse{UserId: 1, UserName: "user1", PassHash: passHash, Role: "user"}
+
+	tests := []struct {
+		name   string
+		config *application.Config
+		req    *application.ChangePasswordRequest
+		want   func(mockStorage *mocks.MockShopStorage) (*application.ChangePasswordResponse, error)
+	}{
+		{
+			name:   "success",
+			config: registrationConfig(),
+			req: &application.ChangePasswordRequest{
+				Principal:       userPrincipal,
+				CurrentPassword: "pass",
+				NewPassword:     "secret42",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ChangePasswordResponse, error) {
+				mockStorage.EXPECT().GetUser(gomock.Any(), uint64(1)).Return(user, nil)
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(credentials, nil)
+				mockStorage.EXPECT().ChangePassword(gomock.Any(), gomock.Cond(func(request *storage.ChangePasswordRequest) bool {
+					return request.UserId == 1 &&
+						bcrypt.CompareHashAndPassword([]byte(request.PassHash), []byte("secret42")) == nil
+				})).Return(nil)
+				mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), session(false)).Return(nil)
+				return &application.ChangePasswordResponse{
+					ExpiresIn: 15 * time.Minute,
+				}, nil
+			},
+		},
+		{
+			name:   "unauthenticated",
+			config: registrationConfig(),
+			req: &application.ChangePasswordRequest{
+				CurrentPassword: "pass",
+				NewPassword:     "secret42",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ChangePasswordResponse, error) {
+				return nil, application.ErrUnauthenticated
+			},
+		},
+		{
+			name:   "weak new password",
+			config: registrationConfig(),
+			req: &application.ChangePasswordRequest{
+				Principal:       userPrincipal,
+				CurrentPassword: "pass",
+				NewPassword:     "secret",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ChangePasswordResponse, error) {
+				return nil, invalidField("newPassword", application.RuleFormat, "must contain a letter and a digit", application.ErrInvalidPassword)
+			},
+		},
+		{
+			name:   "wrong current password",
+			config: registrationConfig(),
+			req: &application.ChangePasswordRequest{
+				Principal:       userPrincipal,
+				CurrentPassword: "wrong",
+				NewPassword:     "secret42",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ChangePasswordResponse, error) {
+				mockStorage.EXPECT().GetUser(gomock.Any(), uint64(1)).Return(user, nil)
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(credentials, nil)
+				return nil, fmt.Errorf("%w: %w", application.ErrInvalidCredentials, application.ErrBadPassword)
+			},
+		},
+		{
+			name: "throttled",
+			config: &application.Config{
+				Secret:                   "secret",
+				LoginFreeAttemptsPerUser: 5,
+			},
+			req: &application.ChangePasswordRequest{
+				Principal:       userPrincipal,
+				CurrentPassword: "wrong",
+				NewPassword:     "secret42",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ChangePasswordResponse, error) {
+				mockStorage.EXPECT().GetUser(gomock.Any(), uint64(1)).Return(user, nil)
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), gomock.Any()).
+					Return(&storage.CountAuthAttemptResponse{RetryAfter: 10 * time.Second}, nil)
+				return nil, &application.TooManyAttemptsError{RetryAfter: 10 * time.Second}
+			},
+		},
+		{
+			name:   "error change password in storage",
+			config: registrationConfig(),
+			req: &application.ChangePasswordRequest{
+				Principal:       userPrincipal,
+				CurrentPassword: "pass",
+				NewPassword:     "secret42",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.ChangePasswordResponse, error) {
+				mockStorage.EXPECT().GetUser(gomock.Any(), uint64(1)).Return(user, nil)
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(credentials, nil)
+				mockStorage.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).Return(fmt.Errorf("storage error"))
+				return nil, fmt.Errorf("error change password in db: %w", fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, tt.config, mockStorage)
+			got, err := app.ChangePassword(context.Background(), tt.req)
+			if got != nil {
+				checkTokens(t, &got.Token, &got.RefreshToken, 1)
+			}
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestPasswordHashUpgrade(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name   string
+		config *application.Config
+		want   func(mockStorage *mocks.MockShopStorage)
+	}{
+		{
+			name:   "bcrypt to argon2id",
+			config: argon2Config(),
+			want: func(mockStorage *mocks.MockShopStorage) {
+				mockStorage.EXPECT().UpdatePassHash(gomock.Any(), gomock.Cond(func(request *storage.UpdatePassHashRequest) bool {
+					return request.UserId == 1 && request.OldPassHash == passHash &&
+						strings.HasPrefix(request.PassHash, "$argon2id$v=19$m=1024,t=1,p=1$")
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "bcrypt cost",
+			config: &application.Config{Secret: "secret", BcryptCost: bcrypt.MinCost},
+			want: func(mockStorage *mocks.MockShopStorage) {
+				mockStorage.EXPECT().UpdatePassHash(gomock.Any(), gomock.Cond(func(request *storage.UpdatePassHashRequest) bool {
+					cost, err := bcrypt.Cost([]byte(request.PassHash))
+					return err == nil && cost == bcrypt.MinCost
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "up to date",
+			config: &application.Config{Secret: "secret", BcryptCost: 10},
+			want:   func(mockStorage *mocks.MockShopStorage) {},
+		},
+		{
+			name:   "update fails",
+			config: argon2Config(),
+			want: func(mockStorage *mocks.MockShopStorage) {
+				mockStorage.EXPECT().UpdatePassHash(gomock.Any(), gomock.Any()).Return(fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(&storage.AuthResponse{
+				UserId:   1,
+				UserName: "user1",
+				PassHash: passHash,
+			}, nil)
+			mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+			tt.want(mockStorage)
+
+			app := application.NewService(logger, tt.config, mockStorage)
+			_, err := app.Auth(context.Background(), &application.AuthRequest{Username: "user1", Password: "pass"})
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestArgon2id(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	allowAuditFailures(mockStorage)
+	var stored string
+	mockStorage.EXPECT().Register(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *storage.RegisterRequest) (*storage.RegisterResponse, error) {
+			stored = request.PassHash
+			return &storage.RegisterResponse{UserId: 1}, nil
+		})
+	mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").DoAndReturn(
+		func(context.Context, string) (*storage.AuthResponse, error) {
+			return &storage.AuthResponse{UserId: 1, UserName: "user1", PassHash: stored}, nil
+		}).Times(2)
+	mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	app := application.NewService(nil, argon2Config(), mockStorage)
+	_, err := app.Register(context.Background(), &application.RegisterRequest{Username: "user1", Password: "secret42"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored, "$argon2id$"))
+
+	_, err = app.Auth(context.Background(), &application.AuthRequest{Username: "user1", Password: "secret42"})
+	assert.NoError(t, err, "no rehash is expected for a hash with the configured parameters")
+	_, err = app.Auth(context.Background(), &application.AuthRequest{Username: "user1", Password: "secret43"})
+	assert.ErrorIs(t, err, application.ErrBadPassword)
+}
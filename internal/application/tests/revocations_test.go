@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// tokenExpiresAt is the exp of adminToken.
+var tokenExpiresAt = time.Unix(4102444800, 0)
+
+// revokeUserTokensRequest matches the revocation of userName's tokens with
+// the default access token lifetime, the cutoff is the time of the call.
+func revokeUserTokensRequest(userName string) gomock.Matcher {
+	return gomock.Cond(func(request *storage.RevokeUserTokensRequest) bool {
+		return request.UserName == userName &&
+			request.TokenTTL == 15*time.Minute &&
+			time.Since(request.RevokedBefore) < time.Minute
+	})
+}
+
+func TestLogout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.LogoutRequest
+		want func(storage *mocks.MockShopStorage) (*application.LogoutResponse, error)
+	}{
+		{
+			name: "success",
+			req:  &application.LogoutRequest{Principal: adminPrincipal},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.LogoutResponse, error) {
+				mockStorage.EXPECT().RevokeToken(gomock.Any(), &storage.RevokeTokenRequest{
+					Jti:       "test-1",
+					UserId:    1,
+					ExpiresAt: tokenExpiresAt,
+				}).Return(nil)
+				return &application.LogoutResponse{}, nil
+			},
+		},
+		{
+			name: "with refresh token",
+			req:  &application.LogoutRequest{Principal: adminPrincipal, RefreshToken: "refresh"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.LogoutResponse, error) {
+				mockStorage.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, request *storage.RevokeTokenRequest) error {
+						assert.NotEmpty(t, request.RefreshTokenHash)
+						assert.NotEqual(t, "refresh", request.RefreshTokenHash)
+						return nil
+					})
+				return &application.LogoutResponse{}, nil
+			},
+		},
+		{
+			name: "error revoke in storage",
+			req:  &application.LogoutRequest{Principal: adminPrincipal},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.LogoutResponse, error) {
+				mockStorage.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Return(fmt.Errorf("storage error"))
+				return nil, fmt.Errorf("error revoke token in db: %w", fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.Logout(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestLogout_Unauthenticated(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	allowAuditFailures(mockStorage)
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	_, err := app.Logout(context.Background(), &application.LogoutRequest{})
+
+	assert.ErrorIs(t, err, application.ErrUnauthenticated)
+}
+
+func TestLogout_RevokesToken(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	mockStorage.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Return(nil)
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	_, err := app.Logout(context.Background(), &application.LogoutRequest{Principal: adminPrincipal})
+	require.NoError(t, err)
+
+	_, err = app.Authenticate(context.Background(), &application.AuthenticateRequest{Token: adminToken})
+	assert.ErrorIs(t, err, application.ErrInvalidToken)
+	assert.ErrorContains(t, err, "token is revoked")
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.RevokeUserSessionsRequest
+		want func(storage *mocks.MockShopStorage) (*application.RevokeUserSessionsResponse, error)
+	}{
+		{
+			name: "success",
+			req:  &application.RevokeUserSessionsRequest{Principal: adminPrincipal, UserName: "user2"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RevokeUserSessionsResponse, error) {
+				mockStorage.EXPECT().RevokeUserTokens(gomock.Any(), revokeUserTokensRequest("user2")).Return(&storage.RevokeUserTokensResponse{
+					UserId:        2,
+					RevokedBefore: time.Now(),
+					ExpiresAt:     time.Now().Add(15 * time.Minute),
+				}, nil)
+				return &application.RevokeUserSessionsResponse{}, nil
+			},
+		},
+		{
+			name: "forbidden",
+			req:  &application.RevokeUserSessionsRequest{Principal: userPrincipal, UserName: "user2"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RevokeUserSessionsResponse, error) {
+				return nil, application.ErrForbidden
+			},
+		},
+		{
+			name: "user not found",
+			req:  &application.RevokeUserSessionsRequest{Principal: adminPrincipal, UserName: "nobody"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RevokeUserSessionsResponse, error) {
+				mockStorage.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any()).Return(nil, storage.ErrUserNotFound)
+				return nil, application.ErrUserNotFound
+			},
+		},
+		{
+			name: "error revoke in storage",
+			req:  &application.RevokeUserSessionsRequest{Principal: adminPrincipal, UserName: "user2"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RevokeUserSessionsResponse, error) {
+				mockStorage.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("storage error"))
+				return nil, fmt.Errorf("error revoke user tokens in db: %w", fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.RevokeUserSessions(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestRevokeUserSessions_RejectsIssuedTokens(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	mockStorage.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any()).Return(&storage.RevokeUserTokensResponse{
+		UserId:        1,
+		RevokedBefore: time.Now(),
+		ExpiresAt:     time.Now().Add(15 * time.Minute),
+	}, nil)
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	_, err := app.RevokeUserSessions(context.Background(), &application.RevokeUserSessionsRequest{Principal: adminPrincipal, UserName: "admin"})
+	require.NoError(t, err)
+
+	_, err = app.Authenticate(context.Background(), &application.AuthenticateRequest{Token: adminToken})
+	assert.ErrorContains(t, err, "token is revoked")
+}
+
+func TestInit_LoadsRevocations(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	mockStorage.EXPECT().DeleteExpiredRevocations(gomock.Any()).Return(nil)
+	mockStorage.EXPECT().GetRevocations(gomock.Any()).Return(&storage.Revocations{
+		Tokens: []*storage.RevokedToken{
+			{Jti: "test-1", ExpiresAt: tokenExpiresAt},
+		},
+	}, nil)
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	require.NoError(t, app.Init())
+
+	_, err := app.Authenticate(context.Background(), &application.AuthenticateRequest{Token: adminToken})
+	assert.ErrorContains(t, err, "token is revoked")
+}
+
+func TestRevokeUserSessions_ReloginWithinSecond(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(&storage.AuthResponse{
+		UserId:   2,
+		UserName: "user1",
+		PassHash: passHash,
+	}, nil).Times(2)
+	mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockStorage.EXPECT().RevokeUserTokens(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *storage.RevokeUserTokensRequest) (*storage.RevokeUserTokensResponse, error) {
+			return &storage.RevokeUserTokensResponse{
+				UserId:        2,
+				RevokedBefore: request.RevokedBefore,
+				ExpiresAt:     request.RevokedBefore.Add(request.TokenTTL),
+			}, nil
+		})
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	ctx := context.Background()
+	auth := &application.AuthRequest{Username: "user1", Password: "pass"}
+	before, err := app.Auth(ctx, auth)
+	require.NoError(t, err)
+	_, err = app.RevokeUserSessions(ctx, &application.RevokeUserSessionsRequest{Principal: adminPrincipal, UserName: "user1"})
+	require.NoError(t, err)
+	// Logging in again right away lands in the second of the revocation.
+	after, err := app.Auth(ctx, auth)
+	require.NoError(t, err)
+
+	_, err = app.Authenticate(ctx, &application.AuthenticateRequest{Token: before.Token})
+	assert.ErrorContains(t, err, "token is revoked")
+	res, err := app.Authenticate(ctx, &application.AuthenticateRequest{Token: after.Token})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), res.Principal.UserId)
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestSetUserRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.SetUserRoleRequest
+		want func(storage *mocks.MockShopStorage) (*application.SetUserRoleResponse, error)
+	}{
+		{
+			name: "success",
+			req:  &application.SetUserRoleRequest{Principal: adminPrincipal, UserName: "user2", Role: application.RoleAuditor},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.SetUserRoleResponse, error) {
+				mockStorage.EXPECT().SetUserRole(gomock.Any(), &storage.SetUserRoleRequest{
+					UserName: "user2",
+					Role:     "auditor",
+				}).Return(&storage.SetUserRoleResponse{UserId: 2, Changed: true}, nil)
+				mockStorage.EXPECT().RevokeUserTokens(gomock.Any(), revokeUserTokensRequest("user2")).Return(&storage.RevokeUserTokensResponse{UserId: 2}, nil)
+				return &application.SetUserRoleResponse{}, nil
+			},
+		},
+		{
+			name: "role unchanged",
+			req:  &application.SetUserRoleRequest{Principal: adminPrincipal, UserName: "user2", Role: application.RoleUser},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.SetUserRoleResponse, error) {
+				mockStorage.EXPECT().SetUserRole(gomock.Any(), gomock.Any()).Return(&storage.SetUserRoleResponse{UserId: 2}, nil)
+				return &application.SetUserRoleResponse{}, nil
+			},
+		},
+		{
+			name: "unknown role",
+			req:  &application.SetUserRoleRequest{Principal: adminPrincipal, UserName: "user2", Role: "root"},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.SetUserRoleResponse, error) {
+				return nil, invalidField("role", application.RuleOneOf, "must be user, auditor or admin", application.ErrUnknownRole)
+			},
+		},
+		{
+			name: "auditor",
+			req:  &application.SetUserRoleRequest{Principal: auditorPrincipal, UserName: "user2", Role: application.RoleAdmin},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.SetUserRoleResponse, error) {
+				return nil, application.ErrForbidden
+			},
+		},
+		{
+			name: "user not found",
+			req:  &application.SetUserRoleRequest{Principal: adminPrincipal, UserName: "nobody", Role: application.RoleAdmin},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.SetUserRoleResponse, error) {
+				mockStorage.EXPECT().SetUserRole(gomock.Any(), gomock.Any()).Return(nil, storage.ErrUserNotFound)
+				return nil, application.ErrUserNotFound
+			},
+		},
+		{
+			name: "error set role in storage",
+			req:  &application.SetUserRoleRequest{Principal: adminPrincipal, UserName: "user2", Role: application.RoleAdmin},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.SetUserRoleResponse, error) {
+				mockStorage.EXPECT().SetUserRole(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("storage error"))
+				return nil, fmt.Errorf("error set user role in db: %w", fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.SetUserRole(context.Background(), tt.req)
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestGetInfo_OtherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name      string
+		principal *application.Principal
+		want      func(storage *mocks.MockShopStorage) error
+	}{
+		{
+			name:      "auditor",
+			principal: auditorPrincipal,
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().GetUserByName(gomock.Any(), "user2").Return(&storage.User{UserId: 2, UserName: "user2"}, nil)
+				mockStorage.EXPECT().GetInventory(gomock.Any(), uint64(2)).Return(nil, fmt.Errorf("storage error"))
+				return fmt.Errorf("error get inventory from db: %w", fmt.Errorf("storage error"))
+			},
+		},
+		{
+			name:      "user",
+			principal: userPrincipal,
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				return application.ErrForbidden
+			},
+		},
+		{
+			name:      "user not found",
+			principal: adminPrincipal,
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().GetUserByName(gomock.Any(), "user2").Return(nil, storage.ErrUserNotFound)
+				return application.ErrUserNotFound
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			_, err := app.GetInfo(context.Background(), &application.GetInfoRequest{Principal: tt.principal, UserName: "user2"})
+
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestTokenCarriesRole(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	mockStorage.EXPECT().GetCredentials(gomock.Any(), "log").Return(&storage.AuthResponse{
+		UserId:   1,
+		UserName: "log",
+		PassHash: passHash,
+		Role:     "auditor",
+	}, nil)
+	mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	res, err := app.Auth(context.Background(), &application.AuthRequest{Username: "log", Password: "pass"})
+	require.NoError(t, err)
+
+	authenticated, err := app.Authenticate(context.Background(), &application.AuthenticateRequest{Token: res.Token})
+	require.NoError(t, err)
+	assert.Equal(t, application.RoleAuditor, authenticated.Principal.Role)
+}
+
+func TestInit_GrantsAdmins(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	mockStorage.EXPECT().DeleteExpiredRevocations(gomock.Any()).Return(nil)
+	mockStorage.EXPECT().GetRevocations(gomock.Any()).Return(&storage.Revocations{}, nil)
+	mockStorage.EXPECT().SetUserRole(gomock.Any(), &storage.SetUserRoleRequest{
+		UserName: "admin",
+		Role:     "admin",
+	}).Return(&storage.SetUserRoleResponse{UserId: 1, Changed: true}, nil)
+
+	app := application.NewService(nil, &application.Config{Secret: "secret", Admins: []string{"admin"}}, mockStorage)
+	require.NoError(t, app.Init())
+}
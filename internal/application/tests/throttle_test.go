@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestAuthThrottle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	config := &application.Config{
+		Secret:                   "secret",
+		LoginFreeAttemptsPerUser: 2,
+		LoginFreeAttemptsPerIP:   5,
+		LoginBackoffBase:         time.Second,
+		LoginLockoutAfter:        3,
+		LoginLockoutDuration:     10 * time.Minute,
+		LoginFailureWindow:       time.Hour,
+	}
+	delays := []time.Duration{time.Second, 2 * time.Second, 10 * time.Minute}
+	userKey := storage.CountAuthAttemptRequest{Key: "user:user1", Free: 2, Delays: delays, Window: time.Hour}
+	ipKey := storage.CountAuthAttemptRequest{Key: "ip:10.0.0.1", Free: 5, Delays: delays, Window: time.Hour}
+	badPassword := fmt.Errorf("%w: %w", application.ErrInvalidCredentials, application.ErrBadPassword)
+
+	tests := []struct {
+		name     string
+		ip       string
+		password string
+		want     func(mockStorage *mocks.MockShopStorage) error
+	}{
+		{
+			name:     "blocked",
+			ip:       "10.0.0.1",
+			password: "pass",
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &userKey).
+					Return(&storage.CountAuthAttemptResponse{RetryAfter: 30 * time.Second}, nil)
+				return &application.TooManyAttemptsError{RetryAfter: 30 * time.Second}
+			},
+		},
+		{
+			name:     "blocked address releases the username",
+			ip:       "10.0.0.1",
+			password: "pass",
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &userKey).
+					Return(&storage.CountAuthAttemptResponse{Attempts: 1}, nil)
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &ipKey).
+					Return(&storage.CountAuthAttemptResponse{RetryAfter: 4 * time.Second}, nil)
+				mockStorage.EXPECT().ReleaseAuthAttempt(gomock.Any(), &storage.ReleaseAuthAttemptRequest{
+					Key:  "user:user1",
+					Free: 2,
+				}).Return(nil)
+				return &application.TooManyAttemptsError{RetryAfter: 4 * time.Second}
+			},
+		},
+		{
+			name:     "failure stays counted",
+			ip:       "10.0.0.1",
+			password: "wrong",
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &userKey).
+					Return(&storage.CountAuthAttemptResponse{Attempts: 3}, nil)
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &ipKey).
+					Return(&storage.CountAuthAttemptResponse{Attempts: 1}, nil)
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(&storage.AuthResponse{
+					UserId:   1,
+					UserName: "user1",
+					PassHash: passHash,
+				}, nil)
+				allowAuditFailures(mockStorage)
+				return badPassword
+			},
+		},
+		{
+			name:     "unknown user counts",
+			ip:       "10.0.0.1",
+			password: "pass",
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &userKey).
+					Return(&storage.CountAuthAttemptResponse{Attempts: 1}, nil)
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &ipKey).
+					Return(&storage.CountAuthAttemptResponse{Attempts: 1}, nil)
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(nil, storage.ErrUserNotFound)
+				allowAuditFailures(mockStorage)
+				return fmt.Errorf("%w: %w", application.ErrInvalidCredentials, application.ErrUnknownUser)
+			},
+		},
+		{
+			name:     "success resets the username and releases the address",
+			ip:       "10.0.0.1",
+			password: "pass",
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &userKey).
+					Return(&storage.CountAuthAttemptResponse{Attempts: 3}, nil)
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &ipKey).
+					Return(&storage.CountAuthAttemptResponse{Attempts: 6}, nil)
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(&storage.AuthResponse{
+					UserId:   1,
+					UserName: "user1",
+					PassHash: passHash,
+				}, nil)
+				mockStorage.EXPECT().ClearAuthAttempts(gomock.Any(), "user:user1").Return(nil)
+				mockStorage.EXPECT().ReleaseAuthAttempt(gomock.Any(), &storage.ReleaseAuthAttemptRequest{
+					Key:  "ip:10.0.0.1",
+					Free: 5,
+				}).Return(nil)
+				mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+				return nil
+			},
+		},
+		{
+			name:     "success without address",
+			password: "pass",
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &userKey).
+					Return(&storage.CountAuthAttemptResponse{Attempts: 1}, nil)
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "user1").Return(&storage.AuthResponse{
+					UserId:   1,
+					UserName: "user1",
+					PassHash: passHash,
+				}, nil)
+				mockStorage.EXPECT().ClearAuthAttempts(gomock.Any(), "user:user1").Return(nil)
+				mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+				return nil
+			},
+		},
+		{
+			name:     "error count attempt",
+			ip:       "10.0.0.1",
+			password: "wrong",
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().CountAuthAttempt(gomock.Any(), &userKey).Return(nil, fmt.Errorf("storage error"))
+				allowAuditFailures(mockStorage)
+				return fmt.Errorf("error count auth attempt in db: %w", fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			want := tt.want(mockStorage)
+
+			app := application.NewService(nil, config, mockStorage)
+			ctx := context.Background()
+			if tt.ip != "" {
+				ctx = application.WithClientIP(ctx, tt.ip)
+			}
+			_, err := app.Auth(ctx, &application.AuthRequest{Username: "user1", Password: tt.password})
+
+			assert.Equal(t, want, err)
+		})
+	}
+}
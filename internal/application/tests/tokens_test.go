@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// checkTokens verifies an issued token pair and clears it, so that the rest of
+// the response can be compared with assert.Equal.
+func checkTokens(t *testing.T, token, refreshToken *string, userId uint64) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(*token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte("secret"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, float64(userId), claims["user_id"])
+	assert.Equal(t, "avito-shop", claims["iss"])
+	assert.Equal(t, "avito-shop", claims["aud"])
+	assert.NotEmpty(t, claims["jti"])
+	assert.True(t, claims.VerifyExpiresAt(time.Now().Add(14*time.Minute).Unix(), true))
+	assert.False(t, claims.VerifyExpiresAt(time.Now().Add(16*time.Minute).Unix(), true))
+	assert.NotEmpty(t, *refreshToken)
+	*token = ""
+	*refreshToken = ""
+}
+
+func TestRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.RefreshRequest
+		want func(storage *mocks.MockShopStorage) (*application.RefreshResponse, error)
+	}{
+		{
+			name: "success",
+			req: &application.RefreshRequest{
+				RefreshToken: "refresh",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RefreshResponse, error) {
+				mockStorage.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, request *storage.RotateRefreshTokenRequest) (*storage.RotateRefreshTokenResponse, error) {
+						assert.NotEqual(t, request.TokenHash, request.NewTokenHash)
+						assert.NotContains(t, request.TokenHash, "refresh")
+						assert.Equal(t, 720*time.Hour, request.TTL)
+						return &storage.RotateRefreshTokenResponse{UserId: 1}, nil
+					})
+				return &application.RefreshResponse{
+					ExpiresIn: 15 * time.Minute,
+				}, nil
+			},
+		},
+		{
+			name: "empty token",
+			req:  &application.RefreshRequest{},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RefreshResponse, error) {
+				return nil, application.ErrInvalidRefreshToken
+			},
+		},
+		{
+			name: "unknown token",
+			req: &application.RefreshRequest{
+				RefreshToken: "refresh",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RefreshResponse, error) {
+				mockStorage.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Return(nil, storage.ErrRefreshTokenNotFound)
+				return nil, application.ErrInvalidRefreshToken
+			},
+		},
+		{
+			name: "reused token",
+			req: &application.RefreshRequest{
+				RefreshToken: "refresh",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RefreshResponse, error) {
+				mockStorage.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Return(nil, storage.ErrRefreshTokenReused)
+				return nil, fmt.Errorf("%w: %w", application.ErrInvalidRefreshToken, application.ErrRefreshTokenReused)
+			},
+		},
+		{
+			name: "error rotate in storage",
+			req: &application.RefreshRequest{
+				RefreshToken: "refresh",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RefreshResponse, error) {
+				mockStorage.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("storage error"))
+				return nil, fmt.Errorf("error rotate refresh token in db: %w", fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			got, err := app.Refresh(context.Background(), tt.req)
+			if got != nil {
+				checkTokens(t, &got.Token, &got.RefreshToken, 1)
+			}
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mocks.NewMockShopStorage(gomock.NewController(t)))
+
+	res, err := app.Authenticate(context.Background(), &application.AuthenticateRequest{Token: adminToken})
+	require.NoError(t, err)
+	assert.Equal(t, adminPrincipal, res.Principal)
+
+	_, err = app.Authenticate(context.Background(), &application.AuthenticateRequest{Token: "invalid"})
+	assert.ErrorIs(t, err, application.ErrInvalidToken)
+
+	// Requests that skipped authentication are rejected before storage is used.
+	_, err = app.GetInfo(context.Background(), &application.GetInfoRequest{})
+	assert.ErrorIs(t, err, application.ErrUnauthenticated)
+}
+
+func TestTokenValidation(t *testing.T) {
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"user_id": 1,
+			"iss":     "avito-shop",
+			"aud":     "avito-shop",
+			"iat":     time.Now().Unix(),
+			"exp":     time.Now().Add(time.Minute).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		claims  func() jwt.MapClaims
+		method  jwt.SigningMethod
+		wantErr string
+	}{
+		{
+			name: "expired",
+			claims: func() jwt.MapClaims {
+				claims := valid()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return claims
+			},
+			wantErr: "Token is expired",
+		},
+		{
+			name: "without expiry",
+			claims: func() jwt.MapClaims {
+				claims := valid()
+				delete(claims, "exp")
+				return claims
+			},
+			wantErr: "token is expired",
+		},
+		{
+			name: "wrong issuer",
+			claims: func() jwt.MapClaims {
+				claims := valid()
+				claims["iss"] = "other"
+				return claims
+			},
+			wantErr: "invalid token issuer",
+		},
+		{
+			name: "wrong audience",
+			claims: func() jwt.MapClaims {
+				claims := valid()
+				claims["aud"] = "other"
+				return claims
+			},
+			wantErr: "invalid token audience",
+		},
+		{
+			name:    "unexpected signing method",
+			claims:  valid,
+			method:  jwt.SigningMethodHS512,
+			wantErr: "unexpected signing method",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == nil {
+				method = jwt.SigningMethodHS256
+			}
+			token, err := jwt.NewWithClaims(method, tt.claims()).SignedString([]byte("secret"))
+			require.NoError(t, err)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mocks.NewMockShopStorage(gomock.NewController(t)))
+			_, err = app.Authenticate(context.Background(), &application.AuthenticateRequest{Token: token})
+
+			assert.ErrorIs(t, err, application.ErrInvalidToken)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
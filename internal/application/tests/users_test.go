@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// passHash is the bcrypt hash of "pass".
+const passHash = "$2a$10$BaZWnWzCru2yy64fHEFC5e0TB4eDbCzkPFzXjIOkAxcuVMR8FFraW"
+
+func registrationConfig() *application.Config {
+	return &application.Config{
+		Secret:                        "secret",
+		UsernameMinLength:             3,
+		UsernameMaxLength:             8,
+		UsernamePattern:               "^[a-z0-9_]+$",
+		PasswordMinLength:             6,
+		PasswordMaxLength:             72,
+		PasswordRequireLetterAndDigit: true,
+	}
+}
+
+// session matches a refresh token created for a session that is, or is not,
+// recorded as a login.
+func session(login bool) gomock.Matcher {
+	return gomock.Cond(func(request *storage.CreateRefreshTokenRequest) bool {
+		return request.Login == login
+	})
+}
+
+func TestRegister(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.RegisterRequest
+		want func(storage *mocks.MockShopStorage) (*application.RegisterResponse, error)
+	}{
+		{
+			name: "success",
+			req: &application.RegisterRequest{
+				Username: "user_1",
+				Password: "secret1",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RegisterResponse, error) {
+				mockStorage.EXPECT().Register(gomock.Any(), gomock.Any()).Return(&storage.RegisterResponse{
+					UserId: 1,
+				}, nil)
+				// Registration is audited on its own, not as a login.
+				mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), session(false)).Return(nil)
+				return &application.RegisterResponse{
+					ExpiresIn: 15 * time.Minute,
+				}, nil
+			},
+		},
+		{
+			name: "username too short",
+			req: &application.RegisterRequest{
+				Username: "ab",
+				Password: "secret1",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RegisterResponse, error) {
+				return nil, invalidField("username", application.RuleMin, "must be at least 3 characters long", application.ErrInvalidUsername)
+			},
+		},
+		{
+			name: "username too long",
+			req: &application.RegisterRequest{
+				Username: "user_12345",
+				Password: "secret1",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RegisterResponse, error) {
+				return nil, invalidField("username", application.RuleMax, "must be at most 8 characters long", application.ErrInvalidUsername)
+			},
+		},
+		{
+			name: "username with forbidden characters",
+			req: &application.RegisterRequest{
+				Username: "User 1",
+				Password: "secret1",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RegisterResponse, error) {
+				return nil, invalidField("username", application.RuleFormat, "contains characters that are not allowed", application.ErrInvalidUsername)
+			},
+		},
+		{
+			name: "password too short",
+			req: &application.RegisterRequest{
+				Username: "user_1",
+				Password: "abc1",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RegisterResponse, error) {
+				return nil, invalidField("password", application.RuleMin, "must be at least 6 characters long", application.ErrInvalidPassword)
+			},
+		},
+		{
+			name: "password without digit",
+			req: &application.RegisterRequest{
+				Username: "user_1",
+				Password: "password",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RegisterResponse, error) {
+				return nil, invalidField("password", application.RuleFormat, "must contain a letter and a digit", application.ErrInvalidPassword)
+			},
+		},
+		{
+			name: "user exists",
+			req: &application.RegisterRequest{
+				Username: "user_1",
+				Password: "secret1",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RegisterResponse, error) {
+				mockStorage.EXPECT().Register(gomock.Any(), gomock.Any()).Return(nil, storage.ErrUserExists)
+				return nil, application.ErrUserExists
+			},
+		},
+		{
+			name: "error register in storage",
+			req: &application.RegisterRequest{
+				Username: "user_1",
+				Password: "secret1",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.RegisterResponse, error) {
+				mockStorage.EXPECT().Register(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("storage error"))
+				return nil, fmt.Errorf("error register in db: %w", fmt.Errorf("storage error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, registrationConfig(), mockStorage)
+			got, err := app.Register(context.Background(), tt.req)
+			if got != nil {
+				checkTokens(t, &got.Token, &got.RefreshToken, 1)
+			}
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestAuthWithoutAutoRegister(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.AuthRequest
+		want func(storage *mocks.MockShopStorage) (*application.AuthResponse, error)
+	}{
+		{
+			name: "success",
+			req: &application.AuthRequest{
+				Password: "pass",
+				Username: "log",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AuthResponse, error) {
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "log").Return(&storage.AuthResponse{
+					UserId:   1,
+					UserName: "log",
+					PassHash: passHash,
+				}, nil)
+				mockStorage.EXPECT().CreateRefreshToken(gomock.Any(), session(true)).Return(nil)
+				return &application.AuthResponse{
+					ExpiresIn: 15 * time.Minute,
+				}, nil
+			},
+		},
+		{
+			name: "unknown user",
+			req: &application.AuthRequest{
+				Password: "pass",
+				Username: "nobody",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AuthResponse, error) {
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "nobody").Return(nil, storage.ErrUserNotFound)
+				return nil, fmt.Errorf("%w: %w", application.ErrInvalidCredentials, application.ErrUnknownUser)
+			},
+		},
+		{
+			name: "bad password",
+			req: &application.AuthRequest{
+				Password: "wrong",
+				Username: "log",
+			},
+			want: func(mockStorage *mocks.MockShopStorage) (*application.AuthResponse, error) {
+				mockStorage.EXPECT().GetCredentials(gomock.Any(), "log").Return(&storage.AuthResponse{
+					UserId:   1,
+					UserName: "log",
+					PassHash: passHash,
+				}, nil)
+				return nil, fmt.Errorf("%w: %w", application.ErrInvalidCredentials, application.ErrBadPassword)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			want, wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, registrationConfig(), mockStorage)
+			got, err := app.Auth(context.Background(), tt.req)
+			if got != nil {
+				checkTokens(t, &got.Token, &got.RefreshToken, 1)
+			}
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
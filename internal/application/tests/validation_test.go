@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/azaliaz/avito-shop/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"math"
+	"testing"
+)
+
+// invalidField is the error of a request that breaks a single rule.
+func invalidField(field, rule, message string, err error) error {
+	return &application.ValidationError{Fields: []application.FieldError{
+		{Field: field, Rule: rule, Message: message, Err: err},
+	}}
+}
+
+func TestValidationError(t *testing.T) {
+	err := &application.ValidationError{Fields: []application.FieldError{
+		{Field: "amount", Rule: application.RuleMin, Message: "must be positive"},
+		{Field: "memo", Rule: application.RuleMax, Message: "must be at most 200 characters long", Err: application.ErrMemoTooLong},
+	}}
+
+	assert.Equal(t, "invalid request: amount must be positive; memo must be at most 200 characters long", err.Error())
+	assert.ErrorIs(t, err, application.ErrInvalidRequest)
+	assert.ErrorIs(t, err, application.ErrMemoTooLong)
+	assert.False(t, errors.Is(err, application.ErrReasonTooLong))
+}
+
+func TestSendCoinValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  *application.SendCoinRequest
+		want func(storage *mocks.MockShopStorage) error
+	}{
+		{
+			name: "zero amount",
+			req:  &application.SendCoinRequest{Principal: userPrincipal, ToUser: "user2"},
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				return invalidField("amount", application.RuleMin, "must be positive", nil)
+			},
+		},
+		{
+			name: "negative amount",
+			req:  &application.SendCoinRequest{Principal: userPrincipal, Amount: -10, ToUser: "user2"},
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				return invalidField("amount", application.RuleMin, "must be positive", nil)
+			},
+		},
+		{
+			name: "amount too large",
+			req:  &application.SendCoinRequest{Principal: userPrincipal, Amount: math.MaxInt32 + 1, ToUser: "user2"},
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				return invalidField("amount", application.RuleMax, "must be at most 2147483647", nil)
+			},
+		},
+		{
+			name: "every broken rule is reported",
+			req:  &application.SendCoinRequest{Principal: userPrincipal, Amount: -10, ToUser: "user 2"},
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				return &application.ValidationError{Fields: []application.FieldError{
+					{Field: "amount", Rule: application.RuleMin, Message: "must be positive"},
+					{Field: "toUser", Rule: application.RuleFormat, Message: "must not contain spaces or control characters"},
+				}}
+			},
+		},
+		{
+			name: "missing recipient",
+			req:  &application.SendCoinRequest{Principal: userPrincipal, Amount: 10},
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				return invalidField("toUser", application.RuleRequired, "is required", nil)
+			},
+		},
+		{
+			name: "self transfer",
+			req:  &application.SendCoinRequest{Principal: userPrincipal, Amount: 10, ToUser: "user1"},
+			want: func(mockStorage *mocks.MockShopStorage) error {
+				mockStorage.EXPECT().SendCoin(gomock.Any(), gomock.Any()).Return(nil, storage.ErrSelfTransfer)
+				return invalidField("toUser", application.RuleNotSelf, "must not be the sender", application.ErrSelfTransfer)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockShopStorage(ctrl)
+			allowAuditFailures(mockStorage)
+			wantErr := tt.want(mockStorage)
+
+			app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+			_, err := app.SendCoin(context.Background(), tt.req)
+
+			assert.Equal(t, wantErr, err)
+		})
+	}
+}
+
+func TestAuthValidation(t *testing.T) {
+	mockStorage := mocks.NewMockShopStorage(gomock.NewController(t))
+	allowAuditFailures(mockStorage)
+
+	app := application.NewService(nil, &application.Config{Secret: "secret"}, mockStorage)
+	_, err := app.Auth(context.Background(), &application.AuthRequest{})
+
+	assert.Equal(t, &application.ValidationError{Fields: []application.FieldError{
+		{Field: "username", Rule: application.RuleRequired, Message: "is required"},
+		{Field: "password", Rule: application.RuleRequired, Message: "is required"},
+	}}, err)
+}
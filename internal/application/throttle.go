@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"strings"
+	"time"
+)
+
+// TooManyAttemptsError is returned by Auth while logins for the username or
+// from the client address are blocked after failed attempts.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyAttempts, e.RetryAfter)
+}
+
+func (e *TooManyAttemptsError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+const (
+	userThrottlePrefix = "user:"
+	ipThrottlePrefix   = "ip:"
+)
+
+// throttleKey is what logins are counted under, along with how many attempts
+// it gets before the backoff starts.
+type throttleKey struct {
+	key  string
+	free int
+}
+
+// loginThrottleKeys returns the keys of a login for username, the address is
+// left out when the facade did not provide one.
+func (s *Service) loginThrottleKeys(ctx context.Context, username string) []throttleKey {
+	var keys []throttleKey
+	if s.config.LoginFreeAttemptsPerUser > 0 {
+		keys = append(keys, throttleKey{
+			key:  userThrottlePrefix + username,
+			free: s.config.LoginFreeAttemptsPerUser,
+		})
+	}
+	if ip := storage.ActorFromContext(ctx).IP; ip != "" && s.config.LoginFreeAttemptsPerIP > 0 {
+		keys = append(keys, throttleKey{
+			key:  ipThrottlePrefix + ip,
+			free: s.config.LoginFreeAttemptsPerIP,
+		})
+	}
+	return keys
+}
+
+// countLoginAttempt counts the attempt for each of keys before the password
+// is checked, so that parallel attempts can't all get past the limit, and
+// rejects it when one of them is blocked. The keys counted before the blocked
+// one are released, a rejected attempt doesn't count.
+func (s *Service) countLoginAttempt(ctx context.Context, keys []throttleKey) error {
+	delays := s.config.loginDelays()
+	for i, key := range keys {
+		res, err := s.db.CountAuthAttempt(ctx, &storage.CountAuthAttemptRequest{
+			Key:    key.key,
+			Free:   key.free,
+			Delays: delays,
+			Window: s.config.loginFailureWindow(),
+		})
+		if err != nil {
+			return fmt.Errorf("error count auth attempt in db: %w", err)
+		}
+		if res.RetryAfter > 0 {
+			if err := s.releaseLoginAttempt(ctx, keys[:i]); err != nil {
+				return err
+			}
+			return &TooManyAttemptsError{RetryAfter: res.RetryAfter}
+		}
+	}
+	return nil
+}
+
+func (s *Service) releaseLoginAttempt(ctx context.Context, keys []throttleKey) error {
+	for _, key := range keys {
+		err := s.db.ReleaseAuthAttempt(ctx, &storage.ReleaseAuthAttemptRequest{
+			Key:  key.key,
+			Free: key.free,
+		})
+		if err != nil {
+			return fmt.Errorf("error release auth attempt in db: %w", err)
+		}
+	}
+	return nil
+}
+
+// clearLoginAttempts resets the username after a successful login. The
+// address only gets this attempt back, so that guessing passwords for many
+// accounts from one address isn't reset by logging into one's own.
+func (s *Service) clearLoginAttempts(ctx context.Context, keys []throttleKey) error {
+	for _, key := range keys {
+		if !strings.HasPrefix(key.key, userThrottlePrefix) {
+			if err := s.releaseLoginAttempt(ctx, []throttleKey{key}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.db.ClearAuthAttempts(ctx, key.key); err != nil {
+			return fmt.Errorf("error clear auth attempts in db: %w", err)
+		}
+	}
+	return nil
+}
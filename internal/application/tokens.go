@@ -0,0 +1,208 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/golang-jwt/jwt"
+	"math"
+	"time"
+)
+
+func (s *Service) Refresh(ctx context.Context, request *RefreshRequest) (_ *RefreshResponse, err error) {
+	defer s.auditFailure(ctx, storage.AuditRefresh, "", &err)
+
+	if request.RefreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.db.RotateRefreshToken(ctx, &storage.RotateRefreshTokenRequest{
+		TokenHash:    hashRefreshToken(request.RefreshToken),
+		NewTokenHash: hashRefreshToken(refreshToken),
+		TTL:          s.config.refreshTokenTTL(),
+	})
+	if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if errors.Is(err, storage.ErrRefreshTokenReused) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRefreshToken, ErrRefreshTokenReused)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error rotate refresh token in db: %w", err)
+	}
+	token, err := s.signToken(res.UserId, Role(res.Role))
+	if err != nil {
+		return nil, err
+	}
+	return &RefreshResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.config.accessTokenTTL(),
+	}, nil
+}
+
+// issueTokens starts a new session for the user: a signed access token and a
+// refresh token that opens a new rotation family. login records the session
+// as a login in the audit log.
+func (s *Service) issueTokens(ctx context.Context, userId uint64, role Role, login bool) (*AuthResponse, error) {
+	token, err := s.signToken(userId, role)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	familyId, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.CreateRefreshToken(ctx, &storage.CreateRefreshTokenRequest{
+		UserId:    userId,
+		TokenHash: hashRefreshToken(refreshToken),
+		FamilyId:  familyId,
+		TTL:       s.config.refreshTokenTTL(),
+		Login:     login,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error create refresh token in db: %w", err)
+	}
+	return &AuthResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.config.accessTokenTTL(),
+	}, nil
+}
+
+func (s *Service) signToken(userId uint64, role Role) (string, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	key, err := s.keys.signingKey(now)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.method, jwt.MapClaims{
+		"user_id": userId,
+		"role":    string(role),
+		"iss":     s.config.tokenIssuer(),
+		"aud":     s.config.tokenAudience(),
+		"iat":     numericDate(now),
+		"exp":     now.Add(s.config.accessTokenTTL()).Unix(),
+		"jti":     jti,
+	})
+	if key.kid != "" {
+		token.Header["kid"] = key.kid
+	}
+	t, err := token.SignedString(key.private)
+	if err != nil {
+		return "", fmt.Errorf("error sign token: %w", err)
+	}
+	return t, nil
+}
+
+func (s *Service) Authenticate(ctx context.Context, request *AuthenticateRequest) (*AuthenticateResponse, error) {
+	principal, err := s.parseToken(request.Token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	return &AuthenticateResponse{Principal: principal}, nil
+}
+
+// userId returns the id of the authenticated user, a nil principal means the
+// facade didn't authenticate the request.
+func (p *Principal) userId() (uint64, error) {
+	if p == nil {
+		return 0, ErrUnauthenticated
+	}
+	return p.UserId, nil
+}
+
+func (s *Service) parseToken(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	t, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.keys.verificationKey(kid, token.Method, time.Now(), s.config.signingKeyGracePeriod())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error parse token: %w", err)
+	}
+	if !t.Valid {
+		return nil, errors.New("invalid token")
+	}
+	// Valid checks exp only when it is present, tokens without it are rejected.
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("token is expired")
+	}
+	if !claims.VerifyIssuer(s.config.tokenIssuer(), true) {
+		return nil, errors.New("invalid token issuer")
+	}
+	if !claims.VerifyAudience(s.config.tokenAudience(), true) {
+		return nil, errors.New("invalid token audience")
+	}
+	floatUserId, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, errors.New("invalid user_id in token")
+	}
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return nil, errors.New("invalid jti in token")
+	}
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return nil, errors.New("invalid iat in token")
+	}
+	// Tokens issued before roles were introduced carry none.
+	role := RoleUser
+	if rawRole, _ := claims["role"].(string); rawRole != "" {
+		role = Role(rawRole)
+	}
+	if !role.valid() {
+		return nil, errors.New("invalid role in token")
+	}
+	exp, _ := claims["exp"].(float64)
+	res := &Principal{
+		UserId:    uint64(floatUserId),
+		Role:      role,
+		TokenId:   jti,
+		IssuedAt:  time.UnixMicro(int64(math.Round(iat * 1e6))),
+		ExpiresAt: time.Unix(int64(exp), 0),
+	}
+	if s.revocations.isRevoked(res) {
+		return nil, errors.New("token is revoked")
+	}
+	return res, nil
+}
+
+// numericDate is t in seconds with a microsecond fraction. A whole second is
+// too coarse for iat, a token issued right after a revocation of all tokens
+// would fall within its second.
+func numericDate(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1e6
+}
+
+// randomToken returns n random bytes encoded for use in URLs and headers.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken is what is stored instead of the refresh token, so that a
+// database leak doesn't hand out working tokens.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
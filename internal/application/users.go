@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+func (s *Service) Register(ctx context.Context, request *RegisterRequest) (_ *RegisterResponse, err error) {
+	defer s.auditFailure(ctx, storage.AuditRegister, request.Username, &err)
+
+	var v validator
+	if err := s.checkUsername(&v, "username", request.Username); err != nil {
+		return nil, err
+	}
+	s.checkPassword(&v, "password", request.Password)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	passHash, err := s.hashPassword(request.Password)
+	if err != nil {
+		return nil, fmt.Errorf("error generate password hash: %w", err)
+	}
+	res, err := s.db.Register(ctx, &storage.RegisterRequest{
+		UserName: request.Username,
+		PassHash: passHash,
+	})
+	if errors.Is(err, storage.ErrUserExists) {
+		return nil, ErrUserExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error register in db: %w", err)
+	}
+	tokens, err := s.issueTokens(ctx, res.UserId, RoleUser, false)
+	if err != nil {
+		return nil, err
+	}
+	return &RegisterResponse{
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	}, nil
+}
+
+// checkUsername applies the configured username policy to a new account.
+func (s *Service) checkUsername(v *validator, field, username string) error {
+	invalid := func(rule, message string) {
+		v.add(FieldError{Field: field, Rule: rule, Message: message, Err: ErrInvalidUsername})
+	}
+	length := utf8.RuneCountInString(username)
+	if length == 0 {
+		invalid(RuleRequired, "is required")
+		return nil
+	}
+	if length < s.config.UsernameMinLength {
+		invalid(RuleMin, fmt.Sprintf("must be at least %d characters long", s.config.UsernameMinLength))
+		return nil
+	}
+	if s.config.UsernameMaxLength > 0 && length > s.config.UsernameMaxLength {
+		invalid(RuleMax, fmt.Sprintf("must be at most %d characters long", s.config.UsernameMaxLength))
+		return nil
+	}
+	if s.config.UsernamePattern == "" {
+		return nil
+	}
+	pattern, err := regexp.Compile(s.config.UsernamePattern)
+	if err != nil {
+		return fmt.Errorf("error compile username pattern: %w", err)
+	}
+	if !pattern.MatchString(username) {
+		invalid(RuleFormat, "contains characters that are not allowed")
+	}
+	return nil
+}
+
+// checkPassword applies the configured password policy to a new password.
+func (s *Service) checkPassword(v *validator, field, password string) {
+	invalid := func(rule, message string) {
+		v.add(FieldError{Field: field, Rule: rule, Message: message, Err: ErrInvalidPassword})
+	}
+	if password == "" {
+		invalid(RuleRequired, "is required")
+		return
+	}
+	if utf8.RuneCountInString(password) < s.config.PasswordMinLength {
+		invalid(RuleMin, fmt.Sprintf("must be at least %d characters long", s.config.PasswordMinLength))
+		return
+	}
+	if s.config.PasswordMaxLength > 0 && len(password) > s.config.PasswordMaxLength {
+		invalid(RuleMax, fmt.Sprintf("must be at most %d bytes long", s.config.PasswordMaxLength))
+		return
+	}
+	if !s.config.PasswordRequireLetterAndDigit {
+		return
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+	if !hasLetter || !hasDigit {
+		invalid(RuleFormat, "must contain a letter and a digit")
+	}
+}
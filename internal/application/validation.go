@@ -0,0 +1,160 @@
+package application
+
+import (
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rules a request field can break, reported in FieldError.Rule.
+const (
+	RuleRequired   = "required"
+	RuleType       = "type"
+	RuleMin        = "min"
+	RuleMax        = "max"
+	RuleFormat     = "format"
+	RuleOneOf      = "oneof"
+	RuleAfter      = "after"
+	RuleNotSelf    = "not_self"
+	RuleNotAllowed = "not_allowed"
+)
+
+const (
+	// maxAmount bounds coin amounts and quantities, they are stored as INT.
+	maxAmount = math.MaxInt32
+	// maxUsernameLength bounds a username that refers to an existing account,
+	// the configured policy of new accounts is usually stricter.
+	maxUsernameLength = 255
+	// maxItemNameLength bounds an item name, it is a segment of the item URLs.
+	maxItemNameLength = 255
+)
+
+// FieldError is a rule that a single field of a request breaks.
+type FieldError struct {
+	// Field is the name of the field as clients send it, such as toUser.
+	Field string
+	// Rule is one of the Rule constants.
+	Rule    string
+	Message string
+	// Err is a more specific error the violation wraps, such as
+	// ErrInvalidPassword, if there is one.
+	Err error
+}
+
+// ValidationError lists every rule a request breaks. It wraps
+// ErrInvalidRequest and the Err of each field.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	violations := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		violations = append(violations, field.Field+" "+field.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidRequest, strings.Join(violations, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := []error{ErrInvalidRequest}
+	for _, field := range e.Fields {
+		if field.Err != nil {
+			errs = append(errs, field.Err)
+		}
+	}
+	return errs
+}
+
+// validator collects the rules a request breaks, so that the client learns
+// about all of them at once rather than one per attempt.
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(field FieldError) {
+	v.fields = append(v.fields, field)
+}
+
+// check records the rule as broken unless ok and returns ok.
+func (v *validator) check(ok bool, field, rule, message string) bool {
+	if !ok {
+		v.add(FieldError{Field: field, Rule: rule, Message: message})
+	}
+	return ok
+}
+
+// err returns the ValidationError, nil if no rule is broken.
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// amount checks a coin amount or a quantity.
+func (v *validator) amount(field string, amount int) bool {
+	return v.check(amount > 0, field, RuleMin, "must be positive") &&
+		v.check(amount <= maxAmount, field, RuleMax, fmt.Sprintf("must be at most %d", maxAmount))
+}
+
+// limit checks a page size, zero asks for the default one.
+func (v *validator) limit(field string, limit, max int) bool {
+	return v.check(limit >= 0 && limit <= max, field, RuleMax, fmt.Sprintf("must be between 1 and %d", max))
+}
+
+// username checks a reference to an existing account. Accounts may predate
+// the current username policy, so it only rejects what no account can have.
+func (v *validator) username(field, username string) bool {
+	if !v.check(username != "", field, RuleRequired, "is required") {
+		return false
+	}
+	if !v.check(utf8.RuneCountInString(username) <= maxUsernameLength, field, RuleMax,
+		fmt.Sprintf("must be at most %d characters long", maxUsernameLength)) {
+		return false
+	}
+	return v.check(utf8.ValidString(username) && strings.IndexFunc(username, func(r rune) bool {
+		return unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}) < 0, field, RuleFormat, "must not contain spaces or control characters")
+}
+
+// itemName checks the name of a new or renamed item. The name is a segment of
+// /api/items/:name, which is matched without unescaping, so it is limited to
+// the characters a path segment carries as they are.
+func (v *validator) itemName(field, name string) bool {
+	if !v.check(name != "", field, RuleRequired, "is required") {
+		return false
+	}
+	if !v.check(len(name) <= maxItemNameLength, field, RuleMax,
+		fmt.Sprintf("must be at most %d characters long", maxItemNameLength)) {
+		return false
+	}
+	return v.check(name != "." && name != ".." && strings.IndexFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-._~", r))
+	}) < 0, field, RuleFormat, "must contain only latin letters, digits and -._~")
+}
+
+// optionalUsername checks a username filter, which may be empty.
+func (v *validator) optionalUsername(field, username string) bool {
+	return username == "" || v.username(field, username)
+}
+
+// timeRange checks that a from-to filter is not empty.
+func (v *validator) timeRange(from, to time.Time) bool {
+	return from.IsZero() || to.IsZero() || v.check(from.Before(to), "to", RuleAfter, "must be after from")
+}
+
+// cursor decodes a page cursor, an empty one asks for the first page.
+func (v *validator) cursor(field, cursor string) *storage.HistoryCursor {
+	if cursor == "" {
+		return nil
+	}
+	after, err := decodeHistoryCursor(cursor)
+	if err != nil {
+		v.add(FieldError{Field: field, Rule: RuleFormat, Message: "is not a valid cursor", Err: err})
+	}
+	return after
+}
@@ -0,0 +1,8 @@
+package grpc
+
+type Config struct {
+	Port uint64 `env:"PORT" yaml:"port"`
+	// IsAdditionalErrorsEnabled adds the full text of an internal error to
+	// its status message, like the details of the REST API.
+	IsAdditionalErrorsEnabled bool `env:"IS_ADDITIONAL_ERRORS_ENABLED" yaml:"is-additional-errors-enabled"`
+}
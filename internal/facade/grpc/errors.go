@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"errors"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"strings"
+)
+
+// internalErrorMessage is all a client learns about an unexpected failure.
+const internalErrorMessage = "internal error"
+
+// errorCodes maps the application errors to status codes the way the REST
+// API maps them to HTTP statuses, an error that matches none of them is
+// Internal.
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{application.ErrUnauthenticated, codes.Unauthenticated},
+	{application.ErrInvalidToken, codes.Unauthenticated},
+	{application.ErrInvalidCredentials, codes.Unauthenticated},
+	{application.ErrInvalidRefreshToken, codes.Unauthenticated},
+	{application.ErrForbidden, codes.PermissionDenied},
+	{application.ErrUserNotFound, codes.NotFound},
+	{application.ErrRecipientNotFound, codes.NotFound},
+	{application.ErrItemNotFound, codes.NotFound},
+	{application.ErrOrderNotFound, codes.NotFound},
+	{application.ErrUserExists, codes.AlreadyExists},
+	{application.ErrItemExists, codes.AlreadyExists},
+	{application.ErrItemSoldOut, codes.FailedPrecondition},
+	{application.ErrItemUnlimited, codes.FailedPrecondition},
+	{application.ErrInsufficientFunds, codes.FailedPrecondition},
+	{application.ErrReturnExpired, codes.FailedPrecondition},
+	{application.ErrNothingToReturn, codes.FailedPrecondition},
+	{application.ErrInvalidRequest, codes.InvalidArgument},
+	{application.ErrInvalidUsername, codes.InvalidArgument},
+	{application.ErrInvalidPassword, codes.InvalidArgument},
+	{application.ErrMemoTooLong, codes.InvalidArgument},
+	{application.ErrReasonRequired, codes.InvalidArgument},
+	{application.ErrReasonTooLong, codes.InvalidArgument},
+	{application.ErrUnknownRole, codes.InvalidArgument},
+	{application.ErrInvalidCursor, codes.InvalidArgument},
+	{application.ErrIdempotencyKeyTooLong, codes.InvalidArgument},
+	{application.ErrIdempotencyKeyReused, codes.InvalidArgument},
+	{application.ErrTooManyAttempts, codes.ResourceExhausted},
+}
+
+// statusError converts an application error to a status. The message is the
+// one of the matched error rather than the whole chain, except for a rejected
+// request, whose message tells what to fix. The broken rules of a request
+// travel as BadRequest details and the wait of a throttled login as
+// RetryInfo.
+func (api *Service) statusError(err error) error {
+	code, message := codes.Internal, internalErrorMessage
+	if api.config != nil && api.config.IsAdditionalErrorsEnabled {
+		message += ": " + err.Error()
+	}
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			code, message = known.code, known.err.Error()
+			if code == codes.InvalidArgument {
+				message = err.Error()
+			}
+			break
+		}
+	}
+	st := status.New(code, message)
+
+	var validationErr *application.ValidationError
+	if errors.As(err, &validationErr) {
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range validationErr.Fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Message,
+				Reason:      strings.ToUpper(field.Rule),
+			})
+		}
+		st = withDetails(st, badRequest)
+	}
+	var tooManyAttempts *application.TooManyAttemptsError
+	if errors.As(err, &tooManyAttempts) {
+		st = withDetails(st, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(tooManyAttempts.RetryAfter),
+		})
+	}
+	return st.Err()
+}
+
+// withDetails attaches a detail to the status, the status is sent without it
+// if it cannot be encoded.
+func withDetails(st *status.Status, detail protoadapt.MessageV1) *status.Status {
+	if detailed, err := st.WithDetails(detail); err == nil {
+		return detailed
+	}
+	return st
+}
@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/pkg/shoppb"
+)
+
+func (api *Service) Auth(ctx context.Context, req *shoppb.AuthRequest) (*shoppb.AuthResponse, error) {
+	res, err := api.app.Auth(ctx, &application.AuthRequest{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, api.statusError(err)
+	}
+	return &shoppb.AuthResponse{
+		Token:        res.Token,
+		RefreshToken: res.RefreshToken,
+		ExpiresIn:    int64(res.ExpiresIn.Seconds()),
+	}, nil
+}
+
+func (api *Service) GetInfo(ctx context.Context, _ *shoppb.GetInfoRequest) (*shoppb.GetInfoResponse, error) {
+	res, err := api.app.GetInfo(ctx, &application.GetInfoRequest{
+		Principal: principal(ctx),
+	})
+	if err != nil {
+		return nil, api.statusError(err)
+	}
+
+	inventory := make([]*shoppb.InventoryItem, 0, len(res.Inventory))
+	for _, productStock := range res.Inventory {
+		inventory = append(inventory, &shoppb.InventoryItem{
+			Type:     productStock.Type,
+			Quantity: int64(productStock.Quantity),
+		})
+	}
+	history := &shoppb.CoinHistory{}
+	if res.CoinHistory != nil {
+		for _, tr := range res.CoinHistory.Received {
+			history.Received = append(history.Received, &shoppb.ReceivedTransfer{
+				FromUser: tr.FromUser,
+				Amount:   int64(tr.Amount),
+				Memo:     tr.Memo,
+			})
+		}
+		for _, tr := range res.CoinHistory.Sent {
+			history.Sent = append(history.Sent, &shoppb.SentTransfer{
+				ToUser: tr.ToUser,
+				Amount: int64(tr.Amount),
+				Memo:   tr.Memo,
+			})
+		}
+	}
+	return &shoppb.GetInfoResponse{
+		Coins:       int64(res.Coins),
+		Inventory:   inventory,
+		CoinHistory: history,
+	}, nil
+}
+
+func (api *Service) SendCoin(ctx context.Context, req *shoppb.SendCoinRequest) (*shoppb.SendCoinResponse, error) {
+	res, err := api.app.SendCoin(ctx, &application.SendCoinRequest{
+		Principal:      principal(ctx),
+		Amount:         int(req.GetAmount()),
+		ToUser:         req.GetToUser(),
+		Memo:           req.GetMemo(),
+		IdempotencyKey: req.GetIdempotencyKey(),
+	})
+	if err != nil {
+		return nil, api.statusError(err)
+	}
+	return &shoppb.SendCoinResponse{Replayed: res.Replayed}, nil
+}
+
+func (api *Service) BuyItem(ctx context.Context, req *shoppb.BuyItemRequest) (*shoppb.BuyItemResponse, error) {
+	res, err := api.app.BuyItem(ctx, &application.BuyItemRequest{
+		Principal:      principal(ctx),
+		Item:           req.GetItem(),
+		Quantity:       int(req.GetQuantity()),
+		IdempotencyKey: req.GetIdempotencyKey(),
+	})
+	if err != nil {
+		return nil, api.statusError(err)
+	}
+	return &shoppb.BuyItemResponse{Replayed: res.Replayed}, nil
+}
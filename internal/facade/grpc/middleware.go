@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/pkg/shoppb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"strings"
+)
+
+// publicMethods can be called without a token.
+var publicMethods = map[string]bool{
+	shoppb.Shop_Auth_FullMethodName: true,
+}
+
+type principalKey struct{}
+
+// clientIP passes the address of the client on to the application, which
+// records it in the audit log and throttles logins by it.
+func (api *Service) clientIP(
+	ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip := p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		ctx = application.WithClientIP(ctx, ip)
+	}
+	return handler(ctx, req)
+}
+
+// requireAuth authenticates the bearer token of every call but the public
+// ones and passes the principal on to the handlers, like the REST middleware.
+func (api *Service) requireAuth(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+	token, ok := bearerToken(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+	res, err := api.app.Authenticate(ctx, &application.AuthenticateRequest{
+		Token: token,
+	})
+	if err != nil {
+		return nil, api.statusError(err)
+	}
+	return handler(context.WithValue(ctx, principalKey{}, res.Principal), req)
+}
+
+// bearerToken reads the token from the authorization metadata.
+func bearerToken(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(value, "Bearer "); ok && token != "" {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+// principal returns what requireAuth stored, nil for the public methods.
+func principal(ctx context.Context) *application.Principal {
+	p, _ := ctx.Value(principalKey{}).(*application.Principal)
+	return p
+}
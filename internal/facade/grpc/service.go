@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/pkg/shoppb"
+	"google.golang.org/grpc"
+	"log/slog"
+	"net"
+)
+
+type Service struct {
+	shoppb.UnimplementedShopServer
+
+	log      *slog.Logger
+	config   *Config
+	server   *grpc.Server
+	listener net.Listener
+	app      application.ShopService
+}
+
+func NewAPI(
+	logEntry *slog.Logger,
+	config *Config,
+	app application.ShopService,
+) *Service {
+	return &Service{
+		log:    logEntry,
+		config: config,
+		app:    app,
+	}
+}
+
+func (api *Service) Init() error {
+	addr := fmt.Sprintf(":%d", api.config.Port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error listen on %s: %w", addr, err)
+	}
+	api.listener = listener
+	api.server = api.NewServer()
+	return nil
+}
+
+// NewServer returns a server with the shop and its interceptors registered,
+// it is not listening yet.
+func (api *Service) NewServer() *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(api.clientIP, api.requireAuth))
+	shoppb.RegisterShopServer(server, api)
+	return server
+}
+
+func (api *Service) Run(_ context.Context) {
+	api.log.Info("start grpc server", "addr", api.listener.Addr().String())
+	if err := api.server.Serve(api.listener); err != nil {
+		api.log.Error("start grpc server", "addr", api.listener.Addr().String(), "err", err)
+	}
+}
+
+func (api *Service) Stop() {
+	if api.server != nil {
+		api.server.GracefulStop()
+	}
+}
@@ -0,0 +1,307 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/grpc"
+	"github.com/azaliaz/avito-shop/pkg/shoppb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+	"time"
+)
+
+// principal is what "Bearer token" authenticates as in the handler tests.
+var principal = &application.Principal{UserId: 1, TokenId: "jti"}
+
+func expectAuthenticated(mockApp *mocks.MockShopService) {
+	mockApp.EXPECT().Authenticate(gomock.Any(), &application.AuthenticateRequest{
+		Token: "token",
+	}).Return(&application.AuthenticateResponse{Principal: principal}, nil)
+}
+
+// newClient serves api over an in-memory listener and returns a client of it.
+func newClient(t *testing.T, api *grpc.Service) shoppb.ShopClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := api.NewServer()
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpclib.NewClient("passthrough:///bufnet",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return shoppb.NewShopClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestAuth_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Auth(gomock.Any(), &application.AuthRequest{
+		Username: "testuser",
+		Password: "password",
+	}).Return(&application.AuthResponse{
+		Token:        "token",
+		RefreshToken: "refresh",
+		ExpiresIn:    15 * time.Minute,
+	}, nil)
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	resp, err := client.Auth(context.Background(), &shoppb.AuthRequest{
+		Username: "testuser",
+		Password: "password",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "token", resp.GetToken())
+	assert.Equal(t, "refresh", resp.GetRefreshToken())
+	assert.Equal(t, int64(900), resp.GetExpiresIn())
+}
+
+func TestAuth_InvalidCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Auth(gomock.Any(), gomock.Any()).Return(nil, application.ErrInvalidCredentials)
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	_, err := client.Auth(context.Background(), &shoppb.AuthRequest{
+		Username: "testuser",
+		Password: "wrongpass",
+	})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.Unauthenticated, st.Code())
+	assert.Equal(t, application.ErrInvalidCredentials.Error(), st.Message())
+}
+
+func TestAuth_TooManyAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Auth(gomock.Any(), gomock.Any()).
+		Return(nil, &application.TooManyAttemptsError{RetryAfter: 1500 * time.Millisecond})
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	_, err := client.Auth(context.Background(), &shoppb.AuthRequest{
+		Username: "testuser",
+		Password: "wrongpass",
+	})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, retryInfo.GetRetryDelay().AsDuration())
+}
+
+func TestRequireAuth_MissingToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	for _, ctx := range []context.Context{
+		context.Background(),
+		withToken(""),
+		metadata.AppendToOutgoingContext(context.Background(), "authorization", "token"),
+	} {
+		_, err := client.GetInfo(ctx, &shoppb.GetInfoRequest{})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+}
+
+func TestRequireAuth_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Authenticate(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("%w: token is expired", application.ErrInvalidToken))
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	_, err := client.GetInfo(withToken("token"), &shoppb.GetInfoRequest{})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestGetInfo_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetInfo(gomock.Any(), &application.GetInfoRequest{
+		Principal: principal,
+	}).Return(&application.GetInfoResponse{
+		Coins: 100,
+		Inventory: []*application.ProductStock{
+			{Type: "t-shirt", Quantity: 2},
+		},
+		CoinHistory: &application.CoinHistory{
+			Received: []*application.Transaction{{FromUser: "alice", Amount: 50, Memo: "thanks"}},
+			Sent:     []*application.Transaction{{ToUser: "bob", Amount: 20}},
+		},
+	}, nil)
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	resp, err := client.GetInfo(withToken("token"), &shoppb.GetInfoRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), resp.GetCoins())
+	require.Len(t, resp.GetInventory(), 1)
+	assert.Equal(t, "t-shirt", resp.GetInventory()[0].GetType())
+	assert.Equal(t, int64(2), resp.GetInventory()[0].GetQuantity())
+	require.Len(t, resp.GetCoinHistory().GetReceived(), 1)
+	assert.Equal(t, "alice", resp.GetCoinHistory().GetReceived()[0].GetFromUser())
+	assert.Equal(t, int64(50), resp.GetCoinHistory().GetReceived()[0].GetAmount())
+	assert.Equal(t, "thanks", resp.GetCoinHistory().GetReceived()[0].GetMemo())
+	require.Len(t, resp.GetCoinHistory().GetSent(), 1)
+	assert.Equal(t, "bob", resp.GetCoinHistory().GetSent()[0].GetToUser())
+	assert.Equal(t, int64(20), resp.GetCoinHistory().GetSent()[0].GetAmount())
+}
+
+func TestGetInfo_InternalError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetInfo(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("connection refused"))
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	_, err := client.GetInfo(withToken("token"), &shoppb.GetInfoRequest{})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal error", st.Message())
+}
+
+func TestGetInfo_AdditionalErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetInfo(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("connection refused"))
+
+	api := grpc.NewAPI(nil, &grpc.Config{IsAdditionalErrorsEnabled: true}, mockApp)
+	client := newClient(t, api)
+	_, err := client.GetInfo(withToken("token"), &shoppb.GetInfoRequest{})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal error: connection refused", st.Message())
+}
+
+func TestSendCoin_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SendCoin(gomock.Any(), &application.SendCoinRequest{
+		Principal:      principal,
+		Amount:         50,
+		ToUser:         "recipient",
+		Memo:           "for lunch",
+		IdempotencyKey: "key-1",
+	}).Return(&application.SendCoinResponse{Replayed: true}, nil)
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	resp, err := client.SendCoin(withToken("token"), &shoppb.SendCoinRequest{
+		ToUser:         "recipient",
+		Amount:         50,
+		Memo:           "for lunch",
+		IdempotencyKey: "key-1",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.GetReplayed())
+}
+
+func TestSendCoin_RecipientNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SendCoin(gomock.Any(), gomock.Any()).Return(nil, application.ErrRecipientNotFound)
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	_, err := client.SendCoin(withToken("token"), &shoppb.SendCoinRequest{ToUser: "nobody", Amount: 10})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestSendCoin_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SendCoin(gomock.Any(), gomock.Any()).Return(nil, &application.ValidationError{
+		Fields: []application.FieldError{
+			{Field: "amount", Rule: application.RuleMin, Message: "must be positive"},
+			{Field: "toUser", Rule: application.RuleNotSelf, Message: "must not be the sender", Err: application.ErrSelfTransfer},
+		},
+	})
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	_, err := client.SendCoin(withToken("token"), &shoppb.SendCoinRequest{ToUser: "testuser", Amount: -1})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.GetFieldViolations(), 2)
+	assert.Equal(t, "amount", badRequest.GetFieldViolations()[0].GetField())
+	assert.Equal(t, "MIN", badRequest.GetFieldViolations()[0].GetReason())
+	assert.Equal(t, "must be positive", badRequest.GetFieldViolations()[0].GetDescription())
+	assert.Equal(t, "toUser", badRequest.GetFieldViolations()[1].GetField())
+	assert.Equal(t, "NOT_SELF", badRequest.GetFieldViolations()[1].GetReason())
+}
+
+func TestBuyItem_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().BuyItem(gomock.Any(), &application.BuyItemRequest{
+		Principal:      principal,
+		Item:           "t-shirt",
+		Quantity:       2,
+		IdempotencyKey: "key-1",
+	}).Return(&application.BuyItemResponse{}, nil)
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	resp, err := client.BuyItem(withToken("token"), &shoppb.BuyItemRequest{
+		Item:           "t-shirt",
+		Quantity:       2,
+		IdempotencyKey: "key-1",
+	})
+
+	require.NoError(t, err)
+	assert.False(t, resp.GetReplayed())
+}
+
+func TestBuyItem_InsufficientFunds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().BuyItem(gomock.Any(), gomock.Any()).Return(nil, application.ErrInsufficientFunds)
+
+	client := newClient(t, grpc.NewAPI(nil, nil, mockApp))
+	_, err := client.BuyItem(withToken("token"), &shoppb.BuyItemRequest{Item: "pink-hoody", Quantity: 1})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Equal(t, application.ErrInsufficientFunds.Error(), st.Message())
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (api *Service) CreditUser(ctx *fiber.Ctx) error {
+	var req schema.CreditRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	if req.Amount <= 0 {
+		return api.invalidParam(ctx, "amount", application.RuleMin, "must be positive")
+	}
+	return api.adjustBalance(ctx, req.Amount, req.Reason, false)
+}
+
+func (api *Service) DebitUser(ctx *fiber.Ctx) error {
+	var req schema.DebitRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	if req.Amount <= 0 {
+		return api.invalidParam(ctx, "amount", application.RuleMin, "must be positive")
+	}
+	return api.adjustBalance(ctx, -req.Amount, req.Reason, value(req.Force))
+}
+
+// adjustBalance runs a credit when amount is positive and a debit when it is
+// negative.
+func (api *Service) adjustBalance(ctx *fiber.Ctx, amount int, reason string, force bool) error {
+	res, err := api.app.AdjustBalance(ctx.UserContext(), &application.AdjustBalanceRequest{
+		Principal:      principal(ctx),
+		UserName:       ctx.Params("username"),
+		Amount:         amount,
+		Reason:         reason,
+		Force:          force,
+		IdempotencyKey: ctx.Get(idempotencyKeyHeader),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	if res.Replayed {
+		ctx.Set(idempotentReplayedHeader, "true")
+	}
+	return ctx.JSON(schema.BalanceResponse{
+		Balance: res.Balance,
+	})
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (api *Service) AuditEvents(ctx *fiber.Ctx) error {
+	limit, err := queryInt(ctx, "limit")
+	if err != nil {
+		return api.invalidParam(ctx, "limit", application.RuleType, "must be an integer")
+	}
+	from, err := queryTime(ctx, "from")
+	if err != nil {
+		return api.invalidParam(ctx, "from", application.RuleType, "must be an RFC 3339 time or a date")
+	}
+	to, err := queryTime(ctx, "to")
+	if err != nil {
+		return api.invalidParam(ctx, "to", application.RuleType, "must be an RFC 3339 time or a date")
+	}
+	res, err := api.app.GetAuditEvents(ctx.UserContext(), &application.GetAuditEventsRequest{
+		Principal: principal(ctx),
+		Actor:     ctx.Query("actor"),
+		Action:    ctx.Query("action"),
+		Outcome:   ctx.Query("outcome"),
+		IP:        ctx.Query("ip"),
+		From:      from,
+		To:        to,
+		Limit:     limit,
+		Cursor:    ctx.Query("cursor"),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+
+	events := make([]schema.AuditEvent, 0, len(res.Events))
+	for _, event := range res.Events {
+		events = append(events, schema.AuditEvent{
+			Id:        event.Id,
+			Actor:     optionalString(event.Actor),
+			Action:    event.Action,
+			Target:    optionalString(event.Target),
+			Ip:        optionalString(event.IP),
+			Outcome:   schema.AuditEventOutcome(event.Outcome),
+			Details:   event.Details,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return ctx.JSON(schema.AuditEventsResponse{
+		Events:     events,
+		NextCursor: optionalString(res.NextCursor),
+	})
+}
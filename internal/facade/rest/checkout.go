@@ -0,0 +1,31 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (api *Service) Checkout(ctx *fiber.Ctx) error {
+	var req schema.CheckoutRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	items := make([]*application.CartItem, 0, len(req.Items))
+	for _, cartItem := range req.Items {
+		items = append(items, &application.CartItem{
+			Item:     cartItem.Item,
+			Quantity: cartItem.Quantity,
+		})
+	}
+	res, err := api.app.Checkout(ctx.UserContext(), &application.CheckoutRequest{
+		Principal: principal(ctx),
+		Items:     items,
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return ctx.JSON(schema.CheckoutResponse{
+		Total: res.Total,
+	})
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"errors"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+	"math"
+	"strconv"
+)
+
+// internalErrorMessage is all a client learns about an unexpected failure.
+const internalErrorMessage = "internal error"
+
+// errorStatuses maps the application errors to status codes, an error that
+// matches none of them is a 500.
+var errorStatuses = []struct {
+	err    error
+	status int
+}{
+	{application.ErrUnauthenticated, fiber.StatusUnauthorized},
+	{application.ErrInvalidToken, fiber.StatusUnauthorized},
+	{application.ErrInvalidCredentials, fiber.StatusUnauthorized},
+	{application.ErrInvalidRefreshToken, fiber.StatusUnauthorized},
+	{application.ErrForbidden, fiber.StatusForbidden},
+	{application.ErrUserNotFound, fiber.StatusNotFound},
+	{application.ErrRecipientNotFound, fiber.StatusNotFound},
+	{application.ErrItemNotFound, fiber.StatusNotFound},
+	{application.ErrOrderNotFound, fiber.StatusNotFound},
+	{application.ErrUserExists, fiber.StatusConflict},
+	{application.ErrItemExists, fiber.StatusConflict},
+	{application.ErrItemSoldOut, fiber.StatusConflict},
+	{application.ErrItemUnlimited, fiber.StatusConflict},
+	{application.ErrInsufficientFunds, fiber.StatusConflict},
+	{application.ErrReturnExpired, fiber.StatusConflict},
+	{application.ErrNothingToReturn, fiber.StatusConflict},
+	{application.ErrInvalidRequest, fiber.StatusUnprocessableEntity},
+	{application.ErrInvalidUsername, fiber.StatusUnprocessableEntity},
+	{application.ErrInvalidPassword, fiber.StatusUnprocessableEntity},
+	{application.ErrMemoTooLong, fiber.StatusUnprocessableEntity},
+	{application.ErrReasonRequired, fiber.StatusUnprocessableEntity},
+	{application.ErrReasonTooLong, fiber.StatusUnprocessableEntity},
+	{application.ErrUnknownRole, fiber.StatusUnprocessableEntity},
+	{application.ErrInvalidCursor, fiber.StatusUnprocessableEntity},
+	{application.ErrIdempotencyKeyTooLong, fiber.StatusUnprocessableEntity},
+	{application.ErrIdempotencyKeyReused, fiber.StatusUnprocessableEntity},
+	{application.ErrTooManyAttempts, fiber.StatusTooManyRequests},
+}
+
+// sendError responds with the status of the application error. A client sees
+// the message of the matched error rather than the whole chain, which may
+// carry the internal reason, except for a rejected request, whose message
+// tells what to fix.
+func (api *Service) sendError(ctx *fiber.Ctx, err error) error {
+	var tooManyAttempts *application.TooManyAttemptsError
+	if errors.As(err, &tooManyAttempts) {
+		retryAfter := int(math.Ceil(tooManyAttempts.RetryAfter.Seconds()))
+		ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	}
+	for _, known := range errorStatuses {
+		if !errors.Is(err, known.err) {
+			continue
+		}
+		message := known.err.Error()
+		if known.status == fiber.StatusUnprocessableEntity {
+			message = err.Error()
+		}
+		return api.errorResponse(ctx, known.status, message, err)
+	}
+	return api.errorResponse(ctx, fiber.StatusInternalServerError, internalErrorMessage, err)
+}
+
+// badRequest responds to a request that could not be parsed.
+func (api *Service) badRequest(ctx *fiber.Ctx, message string) error {
+	return api.errorResponse(ctx, fiber.StatusBadRequest, message, nil)
+}
+
+func (api *Service) errorResponse(ctx *fiber.Ctx, status int, message string, err error) error {
+	response := schema.ErrorResponse{Errors: message}
+	var validationErr *application.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, field := range validationErr.Fields {
+			response.Fields = append(response.Fields, schema.FieldError{
+				Field:   field.Field,
+				Rule:    schema.FieldErrorRule(field.Rule),
+				Message: field.Message,
+			})
+		}
+	}
+	// Only an unexpected failure carries its cause, the chain of a client
+	// error may tell more than its message, like which half of the
+	// credentials was wrong.
+	if err != nil && status >= fiber.StatusInternalServerError && api.config != nil && api.config.IsAdditionalErrorsEnabled {
+		response.Details = optionalString(err.Error())
+	}
+	return ctx.Status(status).JSON(response)
+}
+
+// handleError is the fiber error handler, it keeps the body of errors raised
+// by fiber itself, such as an unknown route, the same as of the handlers.
+func (api *Service) handleError(ctx *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return api.errorResponse(ctx, fiberErr.Code, fiberErr.Message, nil)
+	}
+	return api.errorResponse(ctx, fiber.StatusInternalServerError, internalErrorMessage, err)
+}
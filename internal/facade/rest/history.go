@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"errors"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+	"time"
+)
+
+func (api *Service) History(ctx *fiber.Ctx) error {
+	limit, err := queryInt(ctx, "limit")
+	if err != nil {
+		return api.invalidParam(ctx, "limit", application.RuleType, "must be an integer")
+	}
+	from, err := queryTime(ctx, "from")
+	if err != nil {
+		return api.invalidParam(ctx, "from", application.RuleType, "must be an RFC 3339 time or a date")
+	}
+	to, err := queryTime(ctx, "to")
+	if err != nil {
+		return api.invalidParam(ctx, "to", application.RuleType, "must be an RFC 3339 time or a date")
+	}
+	res, err := api.app.GetHistory(ctx.UserContext(), &application.GetHistoryRequest{
+		Principal:    principal(ctx),
+		UserName:     ctx.Params("username"),
+		Direction:    ctx.Query("direction"),
+		Counterparty: ctx.Query("counterparty"),
+		From:         from,
+		To:           to,
+		Limit:        limit,
+		Cursor:       ctx.Query("cursor"),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+
+	transactions := make([]schema.HistoryEntry, 0, len(res.Transactions))
+	for _, entry := range res.Transactions {
+		transactions = append(transactions, schema.HistoryEntry{
+			Id:        entry.Id,
+			Direction: schema.HistoryEntryDirection(entry.Direction),
+			FromUser:  entry.FromUser,
+			ToUser:    entry.ToUser,
+			Amount:    entry.Amount,
+			Memo:      optionalString(entry.Memo),
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	return ctx.JSON(schema.HistoryResponse{
+		Transactions: transactions,
+		NextCursor:   optionalString(res.NextCursor),
+	})
+}
+
+// queryTime reads an optional time query parameter in RFC 3339 or as a plain
+// date, a missing one is the zero time.
+func queryTime(ctx *fiber.Ctx, key string) (time.Time, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("invalid time")
+}
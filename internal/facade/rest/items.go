@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (api *Service) Items(ctx *fiber.Ctx) error {
+	res, err := api.app.GetItems(ctx.UserContext(), &application.GetItemsRequest{})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+
+	items := make([]schema.Item, 0, len(res.Items))
+	for _, item := range res.Items {
+		items = append(items, schema.Item{
+			Name:  item.Name,
+			Price: item.Price,
+			Stock: item.Stock,
+		})
+	}
+	return ctx.JSON(schema.ItemsResponse{
+		Items: items,
+	})
+}
+
+func (api *Service) Item(ctx *fiber.Ctx) error {
+	res, err := api.app.GetItem(ctx.UserContext(), &application.GetItemRequest{
+		Name: ctx.Params("name"),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return ctx.JSON(schema.Item{
+		Name:  res.Item.Name,
+		Price: res.Item.Price,
+		Stock: res.Item.Stock,
+	})
+}
+
+func (api *Service) CreateItem(ctx *fiber.Ctx) error {
+	var req schema.CreateItemRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	_, err := api.app.CreateItem(ctx.UserContext(), &application.CreateItemRequest{
+		Principal: principal(ctx),
+		Name:      req.Name,
+		Price:     req.Price,
+		Stock:     req.Stock,
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	ctx.Status(fiber.StatusCreated)
+	return nil
+}
+
+func (api *Service) UpdateItemPrice(ctx *fiber.Ctx) error {
+	var req schema.UpdateItemPriceRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	_, err := api.app.UpdateItemPrice(ctx.UserContext(), &application.UpdateItemPriceRequest{
+		Principal: principal(ctx),
+		Name:      ctx.Params("name"),
+		Price:     req.Price,
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return nil
+}
+
+func (api *Service) RenameItem(ctx *fiber.Ctx) error {
+	var req schema.RenameItemRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	_, err := api.app.RenameItem(ctx.UserContext(), &application.RenameItemRequest{
+		Principal: principal(ctx),
+		Name:      ctx.Params("name"),
+		NewName:   req.Name,
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return nil
+}
+
+func (api *Service) RetireItem(ctx *fiber.Ctx) error {
+	_, err := api.app.RetireItem(ctx.UserContext(), &application.RetireItemRequest{
+		Principal: principal(ctx),
+		Name:      ctx.Params("name"),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return nil
+}
+
+func (api *Service) RestockItem(ctx *fiber.Ctx) error {
+	var req schema.RestockItemRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	res, err := api.app.RestockItem(ctx.UserContext(), &application.RestockItemRequest{
+		Principal: principal(ctx),
+		Name:      ctx.Params("name"),
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return ctx.JSON(schema.RestockItemResponse{
+		Stock: res.Stock,
+	})
+}
+
+func (api *Service) ItemPrices(ctx *fiber.Ctx) error {
+	res, err := api.app.GetItemPrices(ctx.UserContext(), &application.GetItemPricesRequest{
+		Principal: principal(ctx),
+		Name:      ctx.Params("name"),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+
+	prices := make([]schema.ItemPrice, 0, len(res.Prices))
+	for _, price := range res.Prices {
+		prices = append(prices, schema.ItemPrice{
+			Price:     price.Price,
+			ValidFrom: price.ValidFrom,
+		})
+	}
+	return ctx.JSON(schema.ItemPricesResponse{
+		Prices: prices,
+	})
+}
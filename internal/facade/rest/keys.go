@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+// jwksCacheControl lets verifiers cache the key set, a new key should be
+// published at least this long before it starts signing.
+const jwksCacheControl = "public, max-age=300"
+
+func (api *Service) JWKS(ctx *fiber.Ctx) error {
+	res, err := api.app.GetJWKS(ctx.UserContext(), &application.GetJWKSRequest{})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+
+	keys := make([]schema.JSONWebKey, 0, len(res.Keys))
+	for _, key := range res.Keys {
+		keys = append(keys, schema.JSONWebKey{
+			Kty: key.Kty,
+			Kid: optionalString(key.Kid),
+			Alg: optionalString(key.Alg),
+			Use: optionalString(key.Use),
+			N:   optionalString(key.N),
+			E:   optionalString(key.E),
+			Crv: optionalString(key.Crv),
+			X:   optionalString(key.X),
+			Y:   optionalString(key.Y),
+		})
+	}
+	ctx.Set(fiber.HeaderCacheControl, jwksCacheControl)
+	return ctx.JSON(schema.JSONWebKeySet{
+		Keys: keys,
+	})
+}
@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (api *Service) CheckLedger(ctx *fiber.Ctx) error {
+	res, err := api.app.CheckLedger(ctx.UserContext(), &application.CheckLedgerRequest{
+		Principal: principal(ctx),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+
+	mismatches := make([]schema.BalanceMismatch, 0, len(res.Mismatches))
+	for _, mismatch := range res.Mismatches {
+		mismatches = append(mismatches, schema.BalanceMismatch{
+			Username:      mismatch.UserName,
+			Balance:       mismatch.Balance,
+			LedgerBalance: mismatch.LedgerBalance,
+		})
+	}
+	return ctx.JSON(schema.LedgerCheckResponse{
+		Consistent: len(mismatches) == 0,
+		Mismatches: mismatches,
+	})
+}
@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"errors"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/gofiber/fiber/v2"
+	"strings"
+)
+
+// principalKey is the ctx.Locals key RequireAuth stores the principal under.
+const principalKey = "principal"
+
+// ClientIP passes the address of the client on to the application, which
+// records it in the audit log. Handlers must use ctx.UserContext() for it.
+func (api *Service) ClientIP(ctx *fiber.Ctx) error {
+	ctx.SetUserContext(application.WithClientIP(ctx.UserContext(), ctx.IP()))
+	return ctx.Next()
+}
+
+// RequireAuth authenticates the bearer token once per request and passes the
+// principal on to the handlers, requests without a valid token get 401.
+func (api *Service) RequireAuth(ctx *fiber.Ctx) error {
+	token, ok := strings.CutPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || token == "" {
+		ctx.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return api.errorResponse(ctx, fiber.StatusUnauthorized, "missing token", nil)
+	}
+	res, err := api.app.Authenticate(ctx.UserContext(), &application.AuthenticateRequest{
+		Token: token,
+	})
+	if err != nil {
+		if errors.Is(err, application.ErrInvalidToken) {
+			ctx.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		}
+		return api.sendError(ctx, err)
+	}
+	ctx.Locals(principalKey, res.Principal)
+	return ctx.Next()
+}
+
+// principal returns what RequireAuth stored, nil on unprotected routes.
+func principal(ctx *fiber.Ctx) *application.Principal {
+	p, _ := ctx.Locals(principalKey).(*application.Principal)
+	return p
+}
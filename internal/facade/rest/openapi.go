@@ -0,0 +1,12 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+// OpenAPI serves the contract of the API, the handlers are tested against it.
+func (api *Service) OpenAPI(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return ctx.Send(schema.OpenAPI)
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+)
+
+func (api *Service) Orders(ctx *fiber.Ctx) error {
+	limit, err := queryInt(ctx, "limit")
+	if err != nil {
+		return api.invalidParam(ctx, "limit", application.RuleType, "must be an integer")
+	}
+	offset, err := queryInt(ctx, "offset")
+	if err != nil {
+		return api.invalidParam(ctx, "offset", application.RuleType, "must be an integer")
+	}
+	res, err := api.app.GetOrders(ctx.UserContext(), &application.GetOrdersRequest{
+		Principal: principal(ctx),
+		UserName:  ctx.Params("username"),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+
+	orders := make([]schema.Order, 0, len(res.Orders))
+	for _, order := range res.Orders {
+		refunds := make([]schema.Refund, 0, len(order.Refunds))
+		for _, refund := range order.Refunds {
+			refunds = append(refunds, schema.Refund{
+				Quantity:  refund.Quantity,
+				Amount:    refund.Amount,
+				CreatedAt: refund.CreatedAt,
+			})
+		}
+		orders = append(orders, schema.Order{
+			Id:               order.Id,
+			Item:             order.Item,
+			Price:            order.Price,
+			Quantity:         order.Quantity,
+			ReturnedQuantity: order.ReturnedQuantity,
+			Refunds:          refunds,
+			CreatedAt:        order.CreatedAt,
+		})
+	}
+	return ctx.JSON(schema.OrdersResponse{
+		Orders: orders,
+		Total:  res.Total,
+	})
+}
+
+func (api *Service) ReturnOrder(ctx *fiber.Ctx) error {
+	orderId, err := strconv.ParseUint(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return api.invalidParam(ctx, "id", application.RuleType, "must be a positive integer")
+	}
+	var req schema.ReturnOrderRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return api.bodyError(ctx, err)
+		}
+	}
+	res, err := api.app.ReturnOrder(ctx.UserContext(), &application.ReturnOrderRequest{
+		Principal: principal(ctx),
+		OrderId:   orderId,
+		Quantity:  value(req.Quantity),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return ctx.JSON(schema.ReturnOrderResponse{
+		Amount: res.Amount,
+	})
+}
+
+// queryInt reads an optional integer query parameter, a missing one is zero.
+func queryInt(ctx *fiber.Ctx, key string) (int, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(raw)
+}
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"errors"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (api *Service) ChangePassword(ctx *fiber.Ctx) error {
+	var req schema.ChangePasswordRequest
+
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	res, err := api.app.ChangePassword(ctx.UserContext(), &application.ChangePasswordRequest{
+		Principal:       principal(ctx),
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+	// A wrong current password is not a 401, the caller is authenticated and
+	// must not be led to drop the session.
+	if errors.Is(err, application.ErrInvalidCredentials) {
+		return api.errorResponse(ctx, fiber.StatusForbidden, "invalid current password", err)
+	}
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+
+	return ctx.JSON(schema.TokenResponse{
+		Token:        res.Token,
+		RefreshToken: res.RefreshToken,
+		ExpiresIn:    int(res.ExpiresIn.Seconds()),
+	})
+}
@@ -0,0 +1,23 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (api *Service) SetUserRole(ctx *fiber.Ctx) error {
+	var req schema.SetUserRoleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	_, err := api.app.SetUserRole(ctx.UserContext(), &application.SetUserRoleRequest{
+		Principal: principal(ctx),
+		UserName:  ctx.Params("username"),
+		Role:      application.Role(req.Role),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return nil
+}
@@ -1,202 +1,185 @@
 package rest
 
 import (
-	"encoding/json"
 	"github.com/gofiber/fiber/v2"
 	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
 	"strconv"
-	"strings"
+)
+
+const (
+	// idempotencyKeyHeader lets clients retry sendCoin and buy without running
+	// the operation twice.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader marks a response replayed for a repeated key.
+	idempotentReplayedHeader = "Idempotent-Replayed"
 )
 
 func (api *Service) Auth(ctx *fiber.Ctx) error {
-	var req struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
+	var req schema.AuthRequest
+
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	res, err := api.app.Auth(ctx.UserContext(), &application.AuthRequest{
+		Username: req.Username,
+		Password: req.Password,
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+
+	return ctx.JSON(schema.TokenResponse{
+		Token:        res.Token,
+		RefreshToken: res.RefreshToken,
+		ExpiresIn:    int(res.ExpiresIn.Seconds()),
+	})
+}
+
+func (api *Service) Refresh(ctx *fiber.Ctx) error {
+	var req schema.RefreshRequest
+
+	if err := ctx.BodyParser(&req); err != nil {
+		return api.bodyError(ctx, err)
+	}
+	res, err := api.app.Refresh(ctx.UserContext(), &application.RefreshRequest{
+		RefreshToken: req.RefreshToken,
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
 	}
 
+	return ctx.JSON(schema.TokenResponse{
+		Token:        res.Token,
+		RefreshToken: res.RefreshToken,
+		ExpiresIn:    int(res.ExpiresIn.Seconds()),
+	})
+}
+
+func (api *Service) Register(ctx *fiber.Ctx) error {
+	var req schema.AuthRequest
+
 	if err := ctx.BodyParser(&req); err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid json",
-		})
+		return api.bodyError(ctx, err)
 	}
-	res, err := api.app.Auth(ctx.Context(), &application.AuthRequest{
+	res, err := api.app.Register(ctx.UserContext(), &application.RegisterRequest{
 		Username: req.Username,
 		Password: req.Password,
 	})
 	if err != nil {
-		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Invalid credentials",
-		})
+		return api.sendError(ctx, err)
 	}
 
-	return ctx.SendString(res.Token)
+	return ctx.Status(fiber.StatusCreated).JSON(schema.TokenResponse{
+		Token:        res.Token,
+		RefreshToken: res.RefreshToken,
+		ExpiresIn:    int(res.ExpiresIn.Seconds()),
+	})
 }
 
 func (api *Service) BuyItem(ctx *fiber.Ctx) error {
-	_, err := api.app.BuyItem(ctx.Context(), &application.BuyItemRequest{
-		Token: api.getToken(ctx),
-		Item:  ctx.Params("item"),
+	quantity := 1
+	if rawQuantity := ctx.Query("quantity"); rawQuantity != "" {
+		var err error
+		quantity, err = strconv.Atoi(rawQuantity)
+		if err != nil {
+			return api.invalidParam(ctx, "quantity", application.RuleType, "must be an integer")
+		}
+	}
+	res, err := api.app.BuyItem(ctx.UserContext(), &application.BuyItemRequest{
+		Principal:      principal(ctx),
+		Item:           ctx.Params("item"),
+		Quantity:       quantity,
+		IdempotencyKey: ctx.Get(idempotencyKeyHeader),
 	})
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return api.sendError(ctx, err)
+	}
+	if res.Replayed {
+		ctx.Set(idempotentReplayedHeader, "true")
 	}
 	return nil
 }
 
 func (api *Service) Info(ctx *fiber.Ctx) error {
-	res, err := api.app.GetInfo(ctx.Context(), &application.GetInfoRequest{
-		Token: api.getToken(ctx),
+	res, err := api.app.GetInfo(ctx.UserContext(), &application.GetInfoRequest{
+		Principal: principal(ctx),
+		UserName:  ctx.Params("username"),
 	})
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return api.sendError(ctx, err)
 	}
 
-	resInventory := make([]struct {
-		// Quantity Количество предметов.
-		Quantity *int `json:"quantity,omitempty"`
-
-		// Type Тип предмета.
-		Type *string `json:"type,omitempty"`
-	}, 0, len(res.Inventory))
+	inventory := make([]schema.InventoryItem, 0, len(res.Inventory))
 	for _, productStock := range res.Inventory {
-		resInventory = append(resInventory, struct {
-			// Quantity Количество предметов.
-			Quantity *int `json:"quantity,omitempty"`
-
-			// Type Тип предмета.
-			Type *string `json:"type,omitempty"`
-		}{
-			Quantity: &productStock.Quantity,
-			Type:     &productStock.Type,
+		inventory = append(inventory, schema.InventoryItem{
+			Quantity: productStock.Quantity,
+			Type:     productStock.Type,
 		})
 	}
-
-	sent := make([]struct {
-		// Amount Количество отправленных монет.
-		Amount *int `json:"amount,omitempty"`
-
-		// ToUser Имя пользователя, которому отправлены монеты.
-		ToUser *string `json:"toUser,omitempty"`
-	}, 0, len(res.CoinHistory.Sent))
+	sent := make([]schema.SentTransfer, 0, len(res.CoinHistory.Sent))
 	for _, tr := range res.CoinHistory.Sent {
-		sent = append(sent, struct {
-			// Amount Количество отправленных монет.
-			Amount *int `json:"amount,omitempty"`
-
-			// ToUser Имя пользователя, которому отправлены монеты.
-			ToUser *string `json:"toUser,omitempty"`
-		}{
-			Amount: &tr.Amount,
-			ToUser: &tr.ToUser,
+		sent = append(sent, schema.SentTransfer{
+			Amount: tr.Amount,
+			ToUser: tr.ToUser,
+			Memo:   optionalString(tr.Memo),
 		})
 	}
-	received := make([]struct {
-		// Amount Количество полученных монет.
-		Amount *int `json:"amount,omitempty"`
-
-		// FromUser Имя пользователя, который отправил монеты.
-		FromUser *string `json:"fromUser,omitempty"`
-	}, 0, len(res.CoinHistory.Received))
+	received := make([]schema.ReceivedTransfer, 0, len(res.CoinHistory.Received))
 	for _, tr := range res.CoinHistory.Received {
-		received = append(received, struct {
-			// Amount Количество полученных монет.
-			Amount *int `json:"amount,omitempty"`
-
-			// FromUser Имя пользователя, который отправил монеты.
-			FromUser *string `json:"fromUser,omitempty"`
-		}{
-			Amount:   &tr.Amount,
-			FromUser: &tr.FromUser,
+		received = append(received, schema.ReceivedTransfer{
+			Amount:   tr.Amount,
+			FromUser: tr.FromUser,
+			Memo:     optionalString(tr.Memo),
 		})
 	}
 
-	response := struct {
-		CoinHistory *struct {
-			Received *[]struct {
-				// Amount Количество полученных монет.
-				Amount *int `json:"amount,omitempty"`
-
-				// FromUser Имя пользователя, который отправил монеты.
-				FromUser *string `json:"fromUser,omitempty"`
-			} `json:"received,omitempty"`
-			Sent *[]struct {
-				// Amount Количество отправленных монет.
-				Amount *int `json:"amount,omitempty"`
-
-				// ToUser Имя пользователя, которому отправлены монеты.
-				ToUser *string `json:"toUser,omitempty"`
-			} `json:"sent,omitempty"`
-		} `json:"coinHistory,omitempty"`
-
-		// Coins Количество доступных монет.
-		Coins     *int `json:"coins,omitempty"`
-		Inventory *[]struct {
-			// Quantity Количество предметов.
-			Quantity *int `json:"quantity,omitempty"`
-
-			// Type Тип предмета.
-			Type *string `json:"type,omitempty"`
-		} `json:"inventory,omitempty"`
-	}{
-		CoinHistory: &struct {
-			Received *[]struct {
-				// Amount Количество полученных монет.
-				Amount *int `json:"amount,omitempty"`
-
-				// FromUser Имя пользователя, который отправил монеты.
-				FromUser *string `json:"fromUser,omitempty"`
-			} `json:"received,omitempty"`
-			Sent *[]struct {
-				// Amount Количество отправленных монет.
-				Amount *int `json:"amount,omitempty"`
-
-				// ToUser Имя пользователя, которому отправлены монеты.
-				ToUser *string `json:"toUser,omitempty"`
-			} `json:"sent,omitempty"`
-		}{Received: &received, Sent: &sent},
-		Coins:     &res.Coins,
-		Inventory: &resInventory,
-	}
-	u, err := json.Marshal(response)
-	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).SendString("response marshal error")
-	}
-
-	return ctx.SendString(string(u))
+	return ctx.JSON(schema.InfoResponse{
+		CoinHistory: schema.CoinHistory{Received: received, Sent: sent},
+		Coins:       res.Coins,
+		Inventory:   inventory,
+	})
 }
 
 func (api *Service) SendCoin(ctx *fiber.Ctx) error {
-	var req struct {
-		Amount string `json:"amount"`
-		ToUser string `json:"toUser"`
-	}
+	var req schema.SendCoinRequest
 	if err := ctx.BodyParser(&req); err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid jsn",
-		})
+		return api.bodyError(ctx, err)
 	}
-	amount, err := strconv.Atoi(req.Amount)
-	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid amount",
-		})
+	if req.Amount.Invalid {
+		return api.invalidParam(ctx, "amount", application.RuleType, "must be an integer")
 	}
-	_, err = api.app.SendCoin(ctx.Context(), &application.SendCoinRequest{
-		Token:  api.getToken(ctx),
-		Amount: amount,
-		ToUser: req.ToUser,
+	res, err := api.app.SendCoin(ctx.UserContext(), &application.SendCoinRequest{
+		Principal:      principal(ctx),
+		Amount:         req.Amount.Value,
+		ToUser:         req.ToUser,
+		Memo:           value(req.Memo),
+		IdempotencyKey: ctx.Get(idempotencyKeyHeader),
 	})
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).SendString("error send coins")
+		return api.sendError(ctx, err)
+	}
+	if res.Replayed {
+		ctx.Set(idempotentReplayedHeader, "true")
 	}
 
 	return nil
 }
 
-func (api *Service) getToken(ctx *fiber.Ctx) string {
-	return strings.TrimPrefix(ctx.Get("Authorization"), "Bearer ")
+// optionalString maps an empty string to nil so that it is omitted from JSON.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// value returns what an optional field of a request points to, the zero value
+// if the field is missing.
+func value[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
 }
@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// FlexibleInt is an integer JSON field that also accepts the number as a
+// string, which is how the amount of a transfer used to be sent.
+type FlexibleInt struct {
+	Value int
+	// Invalid is set when the field holds neither an integer nor a string
+	// with one. The decoder does not tell which field an error of a custom
+	// type comes from, so the handler reports it instead.
+	Invalid bool
+}
+
+func (n *FlexibleInt) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &n.Value); err == nil {
+		return nil
+	}
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		if value, err := strconv.Atoi(strings.TrimSpace(raw)); err == nil {
+			n.Value = value
+			return nil
+		}
+	}
+	n.Invalid = true
+	return nil
+}
+
+func (n FlexibleInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
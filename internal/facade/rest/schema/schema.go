@@ -0,0 +1,13 @@
+// Package schema is the OpenAPI contract of the REST API and the request and
+// response types generated from it. Change openapi.json and run go generate
+// rather than editing types.gen.go.
+package schema
+
+import _ "embed"
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.8.0 -config oapi-codegen.yaml openapi.json
+
+// OpenAPI is the document served at /api/openapi.json.
+//
+//go:embed openapi.json
+var OpenAPI []byte
@@ -0,0 +1,806 @@
+// Package schema provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.8.0 DO NOT EDIT.
+package schema
+
+import (
+	"time"
+)
+
+// Defines values for AuditEventOutcome.
+const (
+	AuditEventOutcomeFailure AuditEventOutcome = "failure"
+	AuditEventOutcomeSuccess AuditEventOutcome = "success"
+)
+
+// Valid indicates whether the value is a known member of the AuditEventOutcome enum.
+func (e AuditEventOutcome) Valid() bool {
+	switch e {
+	case AuditEventOutcomeFailure:
+		return true
+	case AuditEventOutcomeSuccess:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for FieldErrorRule.
+const (
+	FieldErrorRuleAfter      FieldErrorRule = "after"
+	FieldErrorRuleFormat     FieldErrorRule = "format"
+	FieldErrorRuleMax        FieldErrorRule = "max"
+	FieldErrorRuleMin        FieldErrorRule = "min"
+	FieldErrorRuleNotAllowed FieldErrorRule = "not_allowed"
+	FieldErrorRuleNotSelf    FieldErrorRule = "not_self"
+	FieldErrorRuleOneof      FieldErrorRule = "oneof"
+	FieldErrorRuleRequired   FieldErrorRule = "required"
+	FieldErrorRuleType       FieldErrorRule = "type"
+)
+
+// Valid indicates whether the value is a known member of the FieldErrorRule enum.
+func (e FieldErrorRule) Valid() bool {
+	switch e {
+	case FieldErrorRuleAfter:
+		return true
+	case FieldErrorRuleFormat:
+		return true
+	case FieldErrorRuleMax:
+		return true
+	case FieldErrorRuleMin:
+		return true
+	case FieldErrorRuleNotAllowed:
+		return true
+	case FieldErrorRuleNotSelf:
+		return true
+	case FieldErrorRuleOneof:
+		return true
+	case FieldErrorRuleRequired:
+		return true
+	case FieldErrorRuleType:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for HistoryEntryDirection.
+const (
+	HistoryEntryDirectionReceived HistoryEntryDirection = "received"
+	HistoryEntryDirectionSent     HistoryEntryDirection = "sent"
+)
+
+// Valid indicates whether the value is a known member of the HistoryEntryDirection enum.
+func (e HistoryEntryDirection) Valid() bool {
+	switch e {
+	case HistoryEntryDirectionReceived:
+		return true
+	case HistoryEntryDirectionSent:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for SetUserRoleRequestRole.
+const (
+	SetUserRoleRequestRoleAdmin   SetUserRoleRequestRole = "admin"
+	SetUserRoleRequestRoleAuditor SetUserRoleRequestRole = "auditor"
+	SetUserRoleRequestRoleUser    SetUserRoleRequestRole = "user"
+)
+
+// Valid indicates whether the value is a known member of the SetUserRoleRequestRole enum.
+func (e SetUserRoleRequestRole) Valid() bool {
+	switch e {
+	case SetUserRoleRequestRoleAdmin:
+		return true
+	case SetUserRoleRequestRoleAuditor:
+		return true
+	case SetUserRoleRequestRoleUser:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for GetApiAdminAuditParamsOutcome.
+const (
+	GetApiAdminAuditParamsOutcomeFailure GetApiAdminAuditParamsOutcome = "failure"
+	GetApiAdminAuditParamsOutcomeSuccess GetApiAdminAuditParamsOutcome = "success"
+)
+
+// Valid indicates whether the value is a known member of the GetApiAdminAuditParamsOutcome enum.
+func (e GetApiAdminAuditParamsOutcome) Valid() bool {
+	switch e {
+	case GetApiAdminAuditParamsOutcomeFailure:
+		return true
+	case GetApiAdminAuditParamsOutcomeSuccess:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for GetApiAdminUsersUsernameHistoryParamsDirection.
+const (
+	GetApiAdminUsersUsernameHistoryParamsDirectionReceived GetApiAdminUsersUsernameHistoryParamsDirection = "received"
+	GetApiAdminUsersUsernameHistoryParamsDirectionSent     GetApiAdminUsersUsernameHistoryParamsDirection = "sent"
+)
+
+// Valid indicates whether the value is a known member of the GetApiAdminUsersUsernameHistoryParamsDirection enum.
+func (e GetApiAdminUsersUsernameHistoryParamsDirection) Valid() bool {
+	switch e {
+	case GetApiAdminUsersUsernameHistoryParamsDirectionReceived:
+		return true
+	case GetApiAdminUsersUsernameHistoryParamsDirectionSent:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for GetApiHistoryParamsDirection.
+const (
+	GetApiHistoryParamsDirectionReceived GetApiHistoryParamsDirection = "received"
+	GetApiHistoryParamsDirectionSent     GetApiHistoryParamsDirection = "sent"
+)
+
+// Valid indicates whether the value is a known member of the GetApiHistoryParamsDirection enum.
+func (e GetApiHistoryParamsDirection) Valid() bool {
+	switch e {
+	case GetApiHistoryParamsDirectionReceived:
+		return true
+	case GetApiHistoryParamsDirectionSent:
+		return true
+	default:
+		return false
+	}
+}
+
+// AuditEvent defines model for AuditEvent.
+type AuditEvent struct {
+	// Action Операция, например login, send_coin или set_user_role.
+	Action string `json:"action"`
+
+	// Actor Имя пользователя, выполнившего операцию, пустое для анонимных запросов.
+	Actor *string `json:"actor,omitempty"`
+
+	// CreatedAt Время события.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Details Параметры операции или текст ошибки.
+	Details map[string]interface{} `json:"details,omitempty"`
+
+	// Id Номер события.
+	Id uint64 `json:"id"`
+
+	// Ip Адрес клиента.
+	Ip *string `json:"ip,omitempty"`
+
+	// Outcome Результат: success или failure.
+	Outcome AuditEventOutcome `json:"outcome"`
+
+	// Target Объект операции: пользователь, предмет или заказ.
+	Target *string `json:"target,omitempty"`
+}
+
+// AuditEventOutcome Результат: success или failure.
+type AuditEventOutcome string
+
+// AuditEventsResponse defines model for AuditEventsResponse.
+type AuditEventsResponse struct {
+	Events []AuditEvent `json:"events"`
+
+	// NextCursor Курсор следующей страницы, отсутствует на последней странице.
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// AuthRequest defines model for AuthRequest.
+type AuthRequest struct {
+	// Password Пароль для аутентификации.
+	Password string `json:"password"`
+
+	// Username Имя пользователя для аутентификации.
+	Username string `json:"username"`
+}
+
+// BalanceMismatch defines model for BalanceMismatch.
+type BalanceMismatch struct {
+	// Balance Баланс в таблице users.
+	Balance int64 `json:"balance"`
+
+	// LedgerBalance Баланс по записям журнала.
+	LedgerBalance int64 `json:"ledgerBalance"`
+
+	// Username Имя пользователя.
+	Username string `json:"username"`
+}
+
+// BalanceResponse defines model for BalanceResponse.
+type BalanceResponse struct {
+	// Balance Баланс пользователя после операции.
+	Balance int `json:"balance"`
+}
+
+// CartItem defines model for CartItem.
+type CartItem struct {
+	// Item Название предмета.
+	Item string `json:"item"`
+
+	// Quantity Количество единиц.
+	Quantity int `json:"quantity"`
+}
+
+// ChangePasswordRequest defines model for ChangePasswordRequest.
+type ChangePasswordRequest struct {
+	// CurrentPassword Текущий пароль.
+	CurrentPassword string `json:"currentPassword"`
+
+	// NewPassword Новый пароль.
+	NewPassword string `json:"newPassword"`
+}
+
+// CheckoutRequest defines model for CheckoutRequest.
+type CheckoutRequest struct {
+	// Items Позиции корзины.
+	Items []CartItem `json:"items"`
+}
+
+// CheckoutResponse defines model for CheckoutResponse.
+type CheckoutResponse struct {
+	// Total Количество списанных монет.
+	Total int `json:"total"`
+}
+
+// CoinHistory defines model for CoinHistory.
+type CoinHistory struct {
+	Received []ReceivedTransfer `json:"received"`
+	Sent     []SentTransfer     `json:"sent"`
+}
+
+// CreateItemRequest defines model for CreateItemRequest.
+type CreateItemRequest struct {
+	// Name Название предмета.
+	Name string `json:"name"`
+
+	// Price Цена предмета в монетах.
+	Price int `json:"price"`
+
+	// Stock Остаток на складе, null или отсутствие — неограниченный запас.
+	Stock *int `json:"stock,omitempty"`
+}
+
+// CreditRequest defines model for CreditRequest.
+type CreditRequest struct {
+	// Amount Количество монет.
+	Amount int `json:"amount"`
+
+	// Reason Причина, видна пользователю как сообщение к переводу.
+	Reason string `json:"reason"`
+}
+
+// DebitRequest defines model for DebitRequest.
+type DebitRequest struct {
+	// Amount Количество монет.
+	Amount int `json:"amount"`
+
+	// Force Разрешить уход баланса в минус.
+	Force *bool `json:"force,omitempty"`
+
+	// Reason Причина, видна пользователю как сообщение к переводу.
+	Reason string `json:"reason"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	// Details Полный текст внутренней ошибки для отладки, только в ответах 5xx при включённом REST_IS_ADDITIONAL_ERRORS_ENABLED.
+	Details *string `json:"details,omitempty"`
+
+	// Errors Сообщение об ошибке, описывающее проблему.
+	Errors string `json:"errors"`
+
+	// Fields Поля запроса, нарушающие правила, только для 422.
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	// Field Имя поля или параметра запроса.
+	Field string `json:"field"`
+
+	// Message Описание нарушения.
+	Message string `json:"message"`
+
+	// Rule Нарушенное правило.
+	Rule FieldErrorRule `json:"rule"`
+}
+
+// FieldErrorRule Нарушенное правило.
+type FieldErrorRule string
+
+// HistoryEntry defines model for HistoryEntry.
+type HistoryEntry struct {
+	// Amount Количество монет.
+	Amount int `json:"amount"`
+
+	// CreatedAt Время перевода.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Direction Направление перевода: sent или received.
+	Direction HistoryEntryDirection `json:"direction"`
+
+	// FromUser Имя пользователя, который отправил монеты.
+	FromUser string `json:"fromUser"`
+
+	// Id Номер перевода.
+	Id uint64 `json:"id"`
+
+	// Memo Сообщение к переводу.
+	Memo *string `json:"memo,omitempty"`
+
+	// ToUser Имя пользователя, которому отправлены монеты.
+	ToUser string `json:"toUser"`
+}
+
+// HistoryEntryDirection Направление перевода: sent или received.
+type HistoryEntryDirection string
+
+// HistoryResponse defines model for HistoryResponse.
+type HistoryResponse struct {
+	// NextCursor Курсор следующей страницы, отсутствует на последней странице.
+	NextCursor   *string        `json:"nextCursor,omitempty"`
+	Transactions []HistoryEntry `json:"transactions"`
+}
+
+// InfoResponse defines model for InfoResponse.
+type InfoResponse struct {
+	CoinHistory CoinHistory `json:"coinHistory"`
+
+	// Coins Количество доступных монет.
+	Coins     int             `json:"coins"`
+	Inventory []InventoryItem `json:"inventory"`
+}
+
+// InventoryItem defines model for InventoryItem.
+type InventoryItem struct {
+	// Quantity Количество предметов.
+	Quantity int `json:"quantity"`
+
+	// Type Тип предмета.
+	Type string `json:"type"`
+}
+
+// Item defines model for Item.
+type Item struct {
+	// Name Название предмета.
+	Name string `json:"name"`
+
+	// Price Цена предмета в монетах.
+	Price int `json:"price"`
+
+	// Stock Остаток на складе, null — неограниченный запас.
+	Stock *int `json:"stock"`
+}
+
+// ItemPrice defines model for ItemPrice.
+type ItemPrice struct {
+	// Price Цена предмета в монетах.
+	Price int `json:"price"`
+
+	// ValidFrom Время, с которого действует цена.
+	ValidFrom time.Time `json:"validFrom"`
+}
+
+// ItemPricesResponse defines model for ItemPricesResponse.
+type ItemPricesResponse struct {
+	Prices []ItemPrice `json:"prices"`
+}
+
+// ItemsResponse defines model for ItemsResponse.
+type ItemsResponse struct {
+	Items []Item `json:"items"`
+}
+
+// JSONWebKey defines model for JSONWebKey.
+type JSONWebKey struct {
+	// Alg Алгоритм подписи.
+	Alg *string `json:"alg,omitempty"`
+
+	// Crv Кривая EC- или OKP-ключа.
+	Crv *string `json:"crv,omitempty"`
+
+	// E Экспонента RSA-ключа.
+	E *string `json:"e,omitempty"`
+
+	// Kid Идентификатор ключа из заголовка токена.
+	Kid *string `json:"kid,omitempty"`
+
+	// Kty Тип ключа: RSA, EC или OKP.
+	Kty string `json:"kty"`
+
+	// N Модуль RSA-ключа.
+	N *string `json:"n,omitempty"`
+
+	// Use Назначение ключа, всегда sig.
+	Use *string `json:"use,omitempty"`
+
+	// X Координата x EC-ключа или открытый ключ OKP.
+	X *string `json:"x,omitempty"`
+
+	// Y Координата y EC-ключа.
+	Y *string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet defines model for JSONWebKeySet.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// LedgerCheckResponse defines model for LedgerCheckResponse.
+type LedgerCheckResponse struct {
+	// Consistent Балансы всех пользователей совпадают с журналом.
+	Consistent bool              `json:"consistent"`
+	Mismatches []BalanceMismatch `json:"mismatches"`
+}
+
+// LogoutRequest defines model for LogoutRequest.
+type LogoutRequest struct {
+	// RefreshToken Refresh-токен, который нужно отозвать вместе с access-токеном.
+	RefreshToken *string `json:"refreshToken,omitempty"`
+}
+
+// Order defines model for Order.
+type Order struct {
+	// CreatedAt Время покупки.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Id Номер заказа.
+	Id uint64 `json:"id"`
+
+	// Item Название купленного предмета.
+	Item string `json:"item"`
+
+	// Price Цена за единицу на момент покупки.
+	Price int `json:"price"`
+
+	// Quantity Количество купленных единиц.
+	Quantity int `json:"quantity"`
+
+	// Refunds Возвраты по заказу.
+	Refunds []Refund `json:"refunds"`
+
+	// ReturnedQuantity Количество возвращённых единиц.
+	ReturnedQuantity int `json:"returnedQuantity"`
+}
+
+// OrdersResponse defines model for OrdersResponse.
+type OrdersResponse struct {
+	Orders []Order `json:"orders"`
+
+	// Total Общее количество заказов пользователя.
+	Total int `json:"total"`
+}
+
+// ReceivedTransfer defines model for ReceivedTransfer.
+type ReceivedTransfer struct {
+	// Amount Количество полученных монет.
+	Amount int `json:"amount"`
+
+	// FromUser Имя пользователя, который отправил монеты.
+	FromUser string `json:"fromUser"`
+
+	// Memo Сообщение к переводу.
+	Memo *string `json:"memo,omitempty"`
+}
+
+// RefreshRequest defines model for RefreshRequest.
+type RefreshRequest struct {
+	// RefreshToken Refresh-токен, полученный при аутентификации.
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Refund defines model for Refund.
+type Refund struct {
+	// Amount Количество возвращённых монет.
+	Amount int `json:"amount"`
+
+	// CreatedAt Время возврата.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Quantity Количество возвращённых единиц.
+	Quantity int `json:"quantity"`
+}
+
+// RenameItemRequest defines model for RenameItemRequest.
+type RenameItemRequest struct {
+	// Name Новое название предмета.
+	Name string `json:"name"`
+}
+
+// RestockItemRequest defines model for RestockItemRequest.
+type RestockItemRequest struct {
+	// Amount Количество добавляемых единиц.
+	Amount int `json:"amount"`
+}
+
+// RestockItemResponse defines model for RestockItemResponse.
+type RestockItemResponse struct {
+	// Stock Остаток после пополнения.
+	Stock int `json:"stock"`
+}
+
+// ReturnOrderRequest defines model for ReturnOrderRequest.
+type ReturnOrderRequest struct {
+	// Quantity Количество возвращаемых единиц, по умолчанию все невозвращённые.
+	Quantity *int `json:"quantity,omitempty"`
+}
+
+// ReturnOrderResponse defines model for ReturnOrderResponse.
+type ReturnOrderResponse struct {
+	// Amount Количество возвращённых монет.
+	Amount int `json:"amount"`
+}
+
+// SendCoinRequest defines model for SendCoinRequest.
+type SendCoinRequest struct {
+	// Amount Количество монет, для совместимости принимается и строкой.
+	Amount FlexibleInt `json:"amount"`
+
+	// Memo Сообщение к переводу, не длиннее 200 символов.
+	Memo *string `json:"memo,omitempty"`
+
+	// ToUser Имя пользователя, которому нужно отправить монеты.
+	ToUser string `json:"toUser"`
+}
+
+// SentTransfer defines model for SentTransfer.
+type SentTransfer struct {
+	// Amount Количество отправленных монет.
+	Amount int `json:"amount"`
+
+	// Memo Сообщение к переводу.
+	Memo *string `json:"memo,omitempty"`
+
+	// ToUser Имя пользователя, которому отправлены монеты.
+	ToUser string `json:"toUser"`
+}
+
+// SetUserRoleRequest defines model for SetUserRoleRequest.
+type SetUserRoleRequest struct {
+	// Role Роль пользователя.
+	Role SetUserRoleRequestRole `json:"role"`
+}
+
+// SetUserRoleRequestRole Роль пользователя.
+type SetUserRoleRequestRole string
+
+// TokenResponse defines model for TokenResponse.
+type TokenResponse struct {
+	// ExpiresIn Время жизни token в секундах.
+	ExpiresIn int `json:"expiresIn"`
+
+	// RefreshToken Одноразовый токен для получения новой пары токенов.
+	RefreshToken string `json:"refreshToken"`
+
+	// Token JWT-токен для доступа к защищенным ресурсам.
+	Token string `json:"token"`
+}
+
+// UpdateItemPriceRequest defines model for UpdateItemPriceRequest.
+type UpdateItemPriceRequest struct {
+	// Price Новая цена предмета в монетах.
+	Price int `json:"price"`
+}
+
+// Cursor defines model for Cursor.
+type Cursor = string
+
+// From defines model for From.
+type From = string
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// ItemName defines model for ItemName.
+type ItemName = string
+
+// Limit defines model for Limit.
+type Limit = int
+
+// To defines model for To.
+type To = string
+
+// Username defines model for Username.
+type Username = string
+
+// BadRequest defines model for BadRequest.
+type BadRequest = ErrorResponse
+
+// Conflict defines model for Conflict.
+type Conflict = ErrorResponse
+
+// Forbidden defines model for Forbidden.
+type Forbidden = ErrorResponse
+
+// InternalError defines model for InternalError.
+type InternalError = ErrorResponse
+
+// NotFound defines model for NotFound.
+type NotFound = ErrorResponse
+
+// TooManyRequests defines model for TooManyRequests.
+type TooManyRequests = ErrorResponse
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = ErrorResponse
+
+// UnprocessableEntity defines model for UnprocessableEntity.
+type UnprocessableEntity = ErrorResponse
+
+// GetApiAdminAuditParams defines parameters for GetApiAdminAudit.
+type GetApiAdminAuditParams struct {
+	// Actor Имя пользователя, выполнившего операцию.
+	Actor *string `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Action Операция, например login или send_coin.
+	Action *string `form:"action,omitempty" json:"action,omitempty"`
+
+	// Outcome Результат операции.
+	Outcome *GetApiAdminAuditParamsOutcome `form:"outcome,omitempty" json:"outcome,omitempty"`
+
+	// Ip Адрес клиента.
+	Ip *string `form:"ip,omitempty" json:"ip,omitempty"`
+
+	// From Начало периода в RFC 3339 или дата, включительно.
+	From *From `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец периода в RFC 3339 или дата, не включительно.
+	To *To `form:"to,omitempty" json:"to,omitempty"`
+
+	// Limit Размер страницы, по умолчанию 50.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор страницы из nextCursor предыдущего ответа.
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetApiAdminAuditParamsOutcome defines parameters for GetApiAdminAudit.
+type GetApiAdminAuditParamsOutcome string
+
+// PostApiAdminUsersUsernameCreditParams defines parameters for PostApiAdminUsersUsernameCredit.
+type PostApiAdminUsersUsernameCreditParams struct {
+	// IdempotencyKey Ключ, с которым повтор запроса не выполнит операцию второй раз.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostApiAdminUsersUsernameDebitParams defines parameters for PostApiAdminUsersUsernameDebit.
+type PostApiAdminUsersUsernameDebitParams struct {
+	// IdempotencyKey Ключ, с которым повтор запроса не выполнит операцию второй раз.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetApiAdminUsersUsernameHistoryParams defines parameters for GetApiAdminUsersUsernameHistory.
+type GetApiAdminUsersUsernameHistoryParams struct {
+	// Direction Только отправленные (sent) или полученные (received) переводы.
+	Direction *GetApiAdminUsersUsernameHistoryParamsDirection `form:"direction,omitempty" json:"direction,omitempty"`
+
+	// Counterparty Имя второго участника перевода.
+	Counterparty *string `form:"counterparty,omitempty" json:"counterparty,omitempty"`
+
+	// From Начало периода в RFC 3339 или дата, включительно.
+	From *From `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец периода в RFC 3339 или дата, не включительно.
+	To *To `form:"to,omitempty" json:"to,omitempty"`
+
+	// Limit Размер страницы, по умолчанию 20.
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор страницы из nextCursor предыдущего ответа.
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetApiAdminUsersUsernameHistoryParamsDirection defines parameters for GetApiAdminUsersUsernameHistory.
+type GetApiAdminUsersUsernameHistoryParamsDirection string
+
+// GetApiAdminUsersUsernameOrdersParams defines parameters for GetApiAdminUsersUsernameOrders.
+type GetApiAdminUsersUsernameOrdersParams struct {
+	// Limit Размер страницы, по умолчанию 20.
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Количество пропускаемых заказов.
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// GetApiBuyItemParams defines parameters for GetApiBuyItem.
+type GetApiBuyItemParams struct {
+	// Quantity Количество единиц, по умолчанию одна.
+	Quantity *int `form:"quantity,omitempty" json:"quantity,omitempty"`
+
+	// IdempotencyKey Ключ, с которым повтор запроса не выполнит операцию второй раз.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetApiHistoryParams defines parameters for GetApiHistory.
+type GetApiHistoryParams struct {
+	// Direction Только отправленные (sent) или полученные (received) переводы.
+	Direction *GetApiHistoryParamsDirection `form:"direction,omitempty" json:"direction,omitempty"`
+
+	// Counterparty Имя второго участника перевода.
+	Counterparty *string `form:"counterparty,omitempty" json:"counterparty,omitempty"`
+
+	// From Начало периода в RFC 3339 или дата, включительно.
+	From *From `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец периода в RFC 3339 или дата, не включительно.
+	To *To `form:"to,omitempty" json:"to,omitempty"`
+
+	// Limit Размер страницы, по умолчанию 20.
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор страницы из nextCursor предыдущего ответа.
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetApiHistoryParamsDirection defines parameters for GetApiHistory.
+type GetApiHistoryParamsDirection string
+
+// GetApiOrdersParams defines parameters for GetApiOrders.
+type GetApiOrdersParams struct {
+	// Limit Размер страницы, по умолчанию 20.
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Количество пропускаемых заказов.
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// PostApiSendCoinParams defines parameters for PostApiSendCoin.
+type PostApiSendCoinParams struct {
+	// IdempotencyKey Ключ, с которым повтор запроса не выполнит операцию второй раз.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PostApiAdminItemsJSONRequestBody defines body for PostApiAdminItems for application/json ContentType.
+type PostApiAdminItemsJSONRequestBody = CreateItemRequest
+
+// PostApiAdminItemsNamePriceJSONRequestBody defines body for PostApiAdminItemsNamePrice for application/json ContentType.
+type PostApiAdminItemsNamePriceJSONRequestBody = UpdateItemPriceRequest
+
+// PostApiAdminItemsNameRenameJSONRequestBody defines body for PostApiAdminItemsNameRename for application/json ContentType.
+type PostApiAdminItemsNameRenameJSONRequestBody = RenameItemRequest
+
+// PostApiAdminItemsNameRestockJSONRequestBody defines body for PostApiAdminItemsNameRestock for application/json ContentType.
+type PostApiAdminItemsNameRestockJSONRequestBody = RestockItemRequest
+
+// PostApiAdminUsersUsernameCreditJSONRequestBody defines body for PostApiAdminUsersUsernameCredit for application/json ContentType.
+type PostApiAdminUsersUsernameCreditJSONRequestBody = CreditRequest
+
+// PostApiAdminUsersUsernameDebitJSONRequestBody defines body for PostApiAdminUsersUsernameDebit for application/json ContentType.
+type PostApiAdminUsersUsernameDebitJSONRequestBody = DebitRequest
+
+// PutApiAdminUsersUsernameRoleJSONRequestBody defines body for PutApiAdminUsersUsernameRole for application/json ContentType.
+type PutApiAdminUsersUsernameRoleJSONRequestBody = SetUserRoleRequest
+
+// PostApiAuthJSONRequestBody defines body for PostApiAuth for application/json ContentType.
+type PostApiAuthJSONRequestBody = AuthRequest
+
+// PostApiAuthLogoutJSONRequestBody defines body for PostApiAuthLogout for application/json ContentType.
+type PostApiAuthLogoutJSONRequestBody = LogoutRequest
+
+// PostApiAuthPasswordJSONRequestBody defines body for PostApiAuthPassword for application/json ContentType.
+type PostApiAuthPasswordJSONRequestBody = ChangePasswordRequest
+
+// PostApiAuthRefreshJSONRequestBody defines body for PostApiAuthRefresh for application/json ContentType.
+type PostApiAuthRefreshJSONRequestBody = RefreshRequest
+
+// PostApiCheckoutJSONRequestBody defines body for PostApiCheckout for application/json ContentType.
+type PostApiCheckoutJSONRequestBody = CheckoutRequest
+
+// PostApiOrdersIdReturnJSONRequestBody defines body for PostApiOrdersIdReturn for application/json ContentType.
+type PostApiOrdersIdReturnJSONRequestBody = ReturnOrderRequest
+
+// PostApiRegisterJSONRequestBody defines body for PostApiRegister for application/json ContentType.
+type PostApiRegisterJSONRequestBody = AuthRequest
+
+// PostApiSendCoinJSONRequestBody defines body for PostApiSendCoin for application/json ContentType.
+type PostApiSendCoinJSONRequestBody = SendCoinRequest
@@ -39,22 +39,51 @@ func (api *Service) Init() error {
 		CaseSensitive:         api.config.FiberCaseSensitive,
 		DisableStartupMessage: api.config.FiberDisableStartupMessage,
 		DisableKeepalive:      api.config.FiberDisableKeepalive,
+		ErrorHandler:          api.handleError,
 	})
 
-	api.fiber.Add("POST", "/api/auth", api.Auth)
-	api.fiber.Add("GET", "/api/buy/:item", api.BuyItem)
-	api.fiber.Add("GET", "/api/info", api.Info)
-	api.fiber.Add("POST", "/api/sendCoin", api.SendCoin)
-
-	addr := fmt.Sprintf(":%d", api.config.Port)
-	err := api.fiber.Listen(addr)
-	if err != nil {
-		return err
-	}
+	api.Mount(api.fiber)
 
 	return nil
 }
 
+// Mount registers the handlers of the API on app. Every route under /api must
+// be described in schema/openapi.json.
+func (api *Service) Mount(app *fiber.App) {
+	app.Use(api.ClientIP)
+	app.Add("GET", "/.well-known/jwks.json", api.JWKS)
+	app.Add("GET", "/api/openapi.json", api.OpenAPI)
+	app.Add("POST", "/api/register", api.Register)
+	app.Add("POST", "/api/auth", api.Auth)
+	app.Add("POST", "/api/auth/refresh", api.Refresh)
+	app.Add("POST", "/api/auth/logout", api.RequireAuth, api.Logout)
+	app.Add("POST", "/api/auth/password", api.RequireAuth, api.ChangePassword)
+	app.Add("GET", "/api/buy/:item", api.RequireAuth, api.BuyItem)
+	app.Add("GET", "/api/info", api.RequireAuth, api.Info)
+	app.Add("GET", "/api/history", api.RequireAuth, api.History)
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
+	app.Add("POST", "/api/checkout", api.RequireAuth, api.Checkout)
+	app.Add("GET", "/api/orders", api.RequireAuth, api.Orders)
+	app.Add("POST", "/api/orders/:id/return", api.RequireAuth, api.ReturnOrder)
+	app.Add("GET", "/api/items", api.Items)
+	app.Add("GET", "/api/items/:name", api.Item)
+	app.Add("POST", "/api/admin/items", api.RequireAuth, api.CreateItem)
+	app.Add("POST", "/api/admin/items/:name/price", api.RequireAuth, api.UpdateItemPrice)
+	app.Add("POST", "/api/admin/items/:name/rename", api.RequireAuth, api.RenameItem)
+	app.Add("DELETE", "/api/admin/items/:name", api.RequireAuth, api.RetireItem)
+	app.Add("POST", "/api/admin/items/:name/restock", api.RequireAuth, api.RestockItem)
+	app.Add("GET", "/api/admin/items/:name/prices", api.RequireAuth, api.ItemPrices)
+	app.Add("GET", "/api/admin/ledger/check", api.RequireAuth, api.CheckLedger)
+	app.Add("POST", "/api/admin/users/:username/revoke-sessions", api.RequireAuth, api.RevokeUserSessions)
+	app.Add("PUT", "/api/admin/users/:username/role", api.RequireAuth, api.SetUserRole)
+	app.Add("POST", "/api/admin/users/:username/credit", api.RequireAuth, api.CreditUser)
+	app.Add("POST", "/api/admin/users/:username/debit", api.RequireAuth, api.DebitUser)
+	app.Add("GET", "/api/admin/audit", api.RequireAuth, api.AuditEvents)
+	app.Add("GET", "/api/admin/users/:username/info", api.RequireAuth, api.Info)
+	app.Add("GET", "/api/admin/users/:username/history", api.RequireAuth, api.History)
+	app.Add("GET", "/api/admin/users/:username/orders", api.RequireAuth, api.Orders)
+}
+
 func (api *Service) Run(ctx context.Context) {
 	addr := fmt.Sprintf(":%d", api.config.Port)
 	api.log.Info("start rest server", "addr", addr)
@@ -65,4 +94,10 @@ func (api *Service) Run(ctx context.Context) {
 }
 
 func (api *Service) Stop() {
+	if api.fiber == nil {
+		return
+	}
+	if err := api.fiber.Shutdown(); err != nil {
+		api.log.Error("stop rest server", "err", err)
+	}
 }
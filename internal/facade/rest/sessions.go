@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (api *Service) Logout(ctx *fiber.Ctx) error {
+	var req schema.LogoutRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return api.bodyError(ctx, err)
+		}
+	}
+	_, err := api.app.Logout(ctx.UserContext(), &application.LogoutRequest{
+		Principal:    principal(ctx),
+		RefreshToken: value(req.RefreshToken),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return nil
+}
+
+func (api *Service) RevokeUserSessions(ctx *fiber.Ctx) error {
+	_, err := api.app.RevokeUserSessions(ctx.UserContext(), &application.RevokeUserSessionsRequest{
+		Principal: principal(ctx),
+		UserName:  ctx.Params("username"),
+	})
+	if err != nil {
+		return api.sendError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreditUser_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().AdjustBalance(gomock.Any(), &application.AdjustBalanceRequest{
+		Principal: principal,
+		UserName:  "user2",
+		Amount:    100,
+		Reason:    "bonus",
+	}).Return(&application.AdjustBalanceResponse{Balance: 1100}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/users/:username/credit", api.RequireAuth, api.CreditUser)
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/user2/credit", bytes.NewReader([]byte(`{"amount":100,"reason":"bonus"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"balance":1100}`, string(body))
+}
+
+func TestDebitUser_Forced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().AdjustBalance(gomock.Any(), &application.AdjustBalanceRequest{
+		Principal: principal,
+		UserName:  "user2",
+		Amount:    -2000,
+		Reason:    "duplicate grant",
+		Force:     true,
+	}).Return(&application.AdjustBalanceResponse{Balance: -1000}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/users/:username/debit", api.RequireAuth, api.DebitUser)
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/user2/debit",
+		bytes.NewReader([]byte(`{"amount":2000,"reason":"duplicate grant","force":true}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"balance":-1000}`, string(body))
+}
+
+func TestDebitUser_InsufficientFunds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().AdjustBalance(gomock.Any(), gomock.Any()).Return(nil, application.ErrInsufficientFunds)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/users/:username/debit", api.RequireAuth, api.DebitUser)
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/user2/debit", bytes.NewReader([]byte(`{"amount":2000,"reason":"mistake"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func TestDebitUser_NegativeAmount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/users/:username/debit", api.RequireAuth, api.DebitUser)
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/user2/debit", bytes.NewReader([]byte(`{"amount":-100,"reason":"bonus"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestCreditUser_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().AdjustBalance(gomock.Any(), gomock.Any()).Return(nil, application.ErrForbidden)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/users/:username/credit", api.RequireAuth, api.CreditUser)
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/user2/credit", bytes.NewReader([]byte(`{"amount":100,"reason":"bonus"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
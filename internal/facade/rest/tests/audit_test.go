@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuditEvents_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetAuditEvents(gomock.Any(), &application.GetAuditEventsRequest{
+		Principal: principal,
+		Actor:     "admin",
+		Action:    "set_user_role",
+		Outcome:   "success",
+		IP:        "10.0.0.1",
+		From:      time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Limit:     10,
+		Cursor:    "next",
+	}).Return(&application.GetAuditEventsResponse{
+		Events: []*application.AuditEvent{{
+			Id:        7,
+			Actor:     "admin",
+			Action:    "set_user_role",
+			Target:    "user2",
+			IP:        "10.0.0.1",
+			Outcome:   "success",
+			Details:   map[string]any{"role": "auditor"},
+			CreatedAt: time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+		}},
+		NextCursor: "after",
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/admin/audit", api.RequireAuth, api.AuditEvents)
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/admin/audit?actor=admin&action=set_user_role&outcome=success&ip=10.0.0.1&from=2025-02-01&limit=10&cursor=next", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"events":[{"id":7,"actor":"admin","action":"set_user_role","target":"user2","ip":"10.0.0.1",
+		"outcome":"success","details":{"role":"auditor"},"createdAt":"2025-02-01T12:00:00Z"}],"nextCursor":"after"}`, string(body))
+}
+
+func TestAuditEvents_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetAuditEvents(gomock.Any(), gomock.Any()).Return(nil, application.ErrForbidden)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/admin/audit", api.RequireAuth, api.AuditEvents)
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/audit", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestAuditEvents_InvalidFrom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/admin/audit", api.RequireAuth, api.AuditEvents)
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/audit?from=yesterday", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestClientIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Register(gomock.Cond(func(ctx context.Context) bool {
+		return storage.ActorFromContext(ctx).IP == "0.0.0.0"
+	}), gomock.Any()).Return(&application.RegisterResponse{}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Use(api.ClientIP)
+	app.Add("POST", "/api/register", api.Register)
+	resp, _ := app.Test(newRegisterRequest("user_1", "password123"))
+
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckout_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().Checkout(gomock.Any(), &application.CheckoutRequest{
+		Principal: principal,
+		Items: []*application.CartItem{
+			{Item: "pen", Quantity: 2},
+			{Item: "cup", Quantity: 1},
+		},
+	}).Return(&application.CheckoutResponse{Total: 40}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/checkout", api.RequireAuth, api.Checkout)
+	requestBody := []byte(`{"items":[{"item":"pen","quantity":2},{"item":"cup","quantity":1}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/checkout", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Total int `json:"total"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 40, body.Total)
+}
+
+func TestCheckout_SoldOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().Checkout(gomock.Any(), gomock.Any()).Return(nil, application.ErrItemSoldOut)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/checkout", api.RequireAuth, api.Checkout)
+	requestBody := []byte(`{"items":[{"item":"pink-hoody","quantity":1}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/checkout", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func TestCheckout_InvalidJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/checkout", api.RequireAuth, api.Checkout)
+	req := httptest.NewRequest(http.MethodPost, "/api/checkout", bytes.NewReader([]byte("{")))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,696 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/azaliaz/avito-shop/internal/facade/rest/schema"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// contract is the OpenAPI document the API serves, requests are matched to
+// its operations and responses are validated by kin-openapi.
+type contract struct {
+	doc    *openapi3.T
+	router routers.Router
+}
+
+func loadContract(t *testing.T) *contract {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(schema.OpenAPI)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	closeObjects(doc)
+	// The requests of the tests are not sent to the documented server.
+	doc.Servers = nil
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	return &contract{doc: doc, router: router}
+}
+
+// closeObjects makes the objects that don't mention additionalProperties
+// reject properties they don't declare, which OpenAPI allows by default, so
+// that a field a handler adds without documenting it is caught too.
+func closeObjects(doc *openapi3.T) {
+	seen := make(map[*openapi3.Schema]bool)
+	var visit func(ref *openapi3.SchemaRef)
+	visit = func(ref *openapi3.SchemaRef) {
+		if ref == nil || ref.Value == nil || seen[ref.Value] {
+			return
+		}
+		schema := ref.Value
+		seen[schema] = true
+		if schema.Type.Is(openapi3.TypeObject) && len(schema.Properties) > 0 &&
+			schema.AdditionalProperties.Has == nil && schema.AdditionalProperties.Schema == nil {
+			schema.AdditionalProperties.Has = openapi3.Ptr(false)
+		}
+		for _, property := range schema.Properties {
+			visit(property)
+		}
+		visit(schema.Items)
+	}
+	for _, ref := range doc.Components.Schemas {
+		visit(ref)
+	}
+	for _, item := range doc.Paths.Map() {
+		for _, operation := range item.Operations() {
+			for _, response := range operation.Responses.Map() {
+				for _, mediaType := range response.Value.Content {
+					visit(mediaType.Schema)
+				}
+			}
+		}
+	}
+}
+
+// operations lists the documented operations as "METHOD /path".
+func (c *contract) operations() []string {
+	var operations []string
+	for path, item := range c.doc.Paths.Map() {
+		for method := range item.Operations() {
+			operations = append(operations, method+" "+path)
+		}
+	}
+	slices.Sort(operations)
+	return operations
+}
+
+// assertResponse checks that the request matches the operation documented
+// under path, that the status is documented for it and that the body matches
+// the schema of the response.
+func (c *contract) assertResponse(t *testing.T, req *http.Request, path string, resp *http.Response) {
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	route, pathParams, err := c.router.FindRoute(req)
+	require.NoError(t, err, "%s %s", req.Method, req.URL)
+	require.Equal(t, path, route.Path)
+
+	response := route.Operation.Responses.Status(resp.StatusCode)
+	require.NotNil(t, response, "status %d of %s %s is not documented", resp.StatusCode, req.Method, path)
+	if len(response.Value.Content) == 0 {
+		assert.Empty(t, string(body), "%s %s documents no body for %d", req.Method, path, resp.StatusCode)
+		return
+	}
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+		},
+	}
+	input.SetBodyBytes(body)
+	assert.NoError(t, openapi3filter.ValidateResponse(req.Context(), input), string(body))
+}
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// fiberPath turns an OpenAPI path template into a fiber route.
+func fiberPath(path string) string {
+	return pathParam.ReplaceAllString(path, ":$1")
+}
+
+func TestContract_RoutesDocumented(t *testing.T) {
+	contract := loadContract(t)
+	api := rest.NewAPI(nil, nil, mocks.NewMockShopService(gomock.NewController(t)))
+	app := fiber.New()
+	api.Mount(app)
+
+	var routes []string
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		routes = append(routes, route.Method+" "+route.Path)
+	}
+	slices.Sort(routes)
+
+	var documented []string
+	for _, operation := range contract.operations() {
+		method, path, _ := strings.Cut(operation, " ")
+		documented = append(documented, method+" "+fiberPath(path))
+	}
+	assert.Equal(t, documented, routes)
+}
+
+func TestContract_ServedDocument(t *testing.T) {
+	api := rest.NewAPI(nil, nil, mocks.NewMockShopService(gomock.NewController(t)))
+	app := fiber.New()
+	api.Mount(app)
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, string(schema.OpenAPI), string(body))
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	loadContract(t).assertResponse(t, req, "/api/openapi.json", resp)
+}
+
+func TestContract_Responses(t *testing.T) {
+	stock := 5
+	createdAt := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	info := &application.GetInfoResponse{
+		Coins: 900,
+		Inventory: []*application.ProductStock{
+			{Type: "cup", Quantity: 1},
+		},
+		CoinHistory: &application.CoinHistory{
+			Received: []*application.Transaction{
+				{Amount: 100, FromUser: "user2", ToUser: "user1", Memo: "thanks", CreatedAt: createdAt},
+			},
+			Sent: []*application.Transaction{
+				{Amount: 200, FromUser: "user1", ToUser: "user3", CreatedAt: createdAt},
+			},
+		},
+	}
+	history := &application.GetHistoryResponse{
+		Transactions: []*application.HistoryEntry{
+			{Id: 1, Direction: "sent", FromUser: "user1", ToUser: "user2", Amount: 10, Memo: "lunch", CreatedAt: createdAt},
+			{Id: 2, Direction: "received", FromUser: "user2", ToUser: "user1", Amount: 5, CreatedAt: createdAt},
+		},
+		NextCursor: "cursor",
+	}
+	orders := &application.GetOrdersResponse{
+		Orders: []*application.Order{
+			{
+				Id: 1, Item: "cup", Price: 20, Quantity: 2, ReturnedQuantity: 1, CreatedAt: createdAt,
+				Refunds: []*application.Refund{{Quantity: 1, Amount: 20, CreatedAt: createdAt}},
+			},
+			{Id: 2, Item: "pen", Price: 10, Quantity: 1, CreatedAt: createdAt},
+		},
+		Total: 2,
+	}
+	tokens := &application.AuthResponse{Token: "token", RefreshToken: "refresh", ExpiresIn: 15 * time.Minute}
+
+	tests := []struct {
+		name   string
+		method string
+		// path is the path template the operation is documented under.
+		path   string
+		target string
+		body   string
+		// anonymous requests are sent without a token.
+		anonymous bool
+		want      func(mockApp *mocks.MockShopService)
+		status    int
+	}{
+		{
+			name:   "jwks",
+			method: http.MethodGet, path: "/.well-known/jwks.json", target: "/.well-known/jwks.json",
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetJWKS(gomock.Any(), gomock.Any()).Return(&application.GetJWKSResponse{
+					Keys: []*application.JSONWebKey{
+						{Kty: "RSA", Kid: "rsa", Alg: "RS256", Use: "sig", N: "n", E: "AQAB"},
+						{Kty: "EC", Kid: "ec", Alg: "ES256", Use: "sig", Crv: "P-256", X: "x", Y: "y"},
+					},
+				}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "register",
+			method: http.MethodPost, path: "/api/register", target: "/api/register",
+			body:      `{"username":"user1","password":"password"}`,
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().Register(gomock.Any(), gomock.Any()).Return((*application.RegisterResponse)(tokens), nil)
+			},
+			status: fiber.StatusCreated,
+		},
+		{
+			name:   "register existing user",
+			method: http.MethodPost, path: "/api/register", target: "/api/register",
+			body:      `{"username":"user1","password":"password"}`,
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().Register(gomock.Any(), gomock.Any()).Return(nil, application.ErrUserExists)
+			},
+			status: fiber.StatusConflict,
+		},
+		{
+			name:   "auth",
+			method: http.MethodPost, path: "/api/auth", target: "/api/auth",
+			body:      `{"username":"user1","password":"password"}`,
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().Auth(gomock.Any(), gomock.Any()).Return(tokens, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "auth invalid json",
+			method: http.MethodPost, path: "/api/auth", target: "/api/auth",
+			body:      `{`,
+			anonymous: true,
+			status:    fiber.StatusBadRequest,
+		},
+		{
+			name:   "auth wrong type",
+			method: http.MethodPost, path: "/api/auth", target: "/api/auth",
+			body:      `{"username":1,"password":"password"}`,
+			anonymous: true,
+			status:    fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:   "auth invalid credentials",
+			method: http.MethodPost, path: "/api/auth", target: "/api/auth",
+			body:      `{"username":"user1","password":"wrong"}`,
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().Auth(gomock.Any(), gomock.Any()).Return(nil, application.ErrInvalidCredentials)
+			},
+			status: fiber.StatusUnauthorized,
+		},
+		{
+			name:   "auth throttled",
+			method: http.MethodPost, path: "/api/auth", target: "/api/auth",
+			body:      `{"username":"user1","password":"wrong"}`,
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().Auth(gomock.Any(), gomock.Any()).
+					Return(nil, &application.TooManyAttemptsError{RetryAfter: time.Minute})
+			},
+			status: fiber.StatusTooManyRequests,
+		},
+		{
+			name:   "refresh",
+			method: http.MethodPost, path: "/api/auth/refresh", target: "/api/auth/refresh",
+			body:      `{"refreshToken":"refresh"}`,
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().Refresh(gomock.Any(), gomock.Any()).Return((*application.RefreshResponse)(tokens), nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "logout",
+			method: http.MethodPost, path: "/api/auth/logout", target: "/api/auth/logout",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().Logout(gomock.Any(), gomock.Any()).Return(&application.LogoutResponse{}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "change password",
+			method: http.MethodPost, path: "/api/auth/password", target: "/api/auth/password",
+			body: `{"currentPassword":"password","newPassword":"new password"}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).
+					Return((*application.ChangePasswordResponse)(tokens), nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "change password wrong current password",
+			method: http.MethodPost, path: "/api/auth/password", target: "/api/auth/password",
+			body: `{"currentPassword":"wrong","newPassword":"new password"}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).Return(nil, application.ErrInvalidCredentials)
+			},
+			status: fiber.StatusForbidden,
+		},
+		{
+			name:   "buy",
+			method: http.MethodGet, path: "/api/buy/{item}", target: "/api/buy/cup?quantity=2",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().BuyItem(gomock.Any(), gomock.Any()).Return(&application.BuyItemResponse{}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "buy without coins",
+			method: http.MethodGet, path: "/api/buy/{item}", target: "/api/buy/cup",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().BuyItem(gomock.Any(), gomock.Any()).Return(nil, application.ErrInsufficientFunds)
+			},
+			status: fiber.StatusConflict,
+		},
+		{
+			name:   "buy invalid quantity",
+			method: http.MethodGet, path: "/api/buy/{item}", target: "/api/buy/cup?quantity=-1",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().BuyItem(gomock.Any(), gomock.Any()).Return(nil, &application.ValidationError{
+					Fields: []application.FieldError{
+						{Field: "quantity", Rule: application.RuleMin, Message: "must be positive"},
+					},
+				})
+			},
+			status: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:   "info",
+			method: http.MethodGet, path: "/api/info", target: "/api/info",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetInfo(gomock.Any(), gomock.Any()).Return(info, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "info without token",
+			method: http.MethodGet, path: "/api/info", target: "/api/info",
+			anonymous: true,
+			status:    fiber.StatusUnauthorized,
+		},
+		{
+			name:   "info internal error",
+			method: http.MethodGet, path: "/api/info", target: "/api/info",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetInfo(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("connection refused"))
+			},
+			status: fiber.StatusInternalServerError,
+		},
+		{
+			name:   "history",
+			method: http.MethodGet, path: "/api/history", target: "/api/history?direction=sent&limit=2",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetHistory(gomock.Any(), gomock.Any()).Return(history, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "history invalid limit",
+			method: http.MethodGet, path: "/api/history", target: "/api/history?limit=many",
+			status: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:   "send coin",
+			method: http.MethodPost, path: "/api/sendCoin", target: "/api/sendCoin",
+			body: `{"toUser":"user2","amount":10,"memo":"lunch"}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().SendCoin(gomock.Any(), gomock.Any()).Return(&application.SendCoinResponse{}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "send coin to unknown user",
+			method: http.MethodPost, path: "/api/sendCoin", target: "/api/sendCoin",
+			body: `{"toUser":"nobody","amount":10}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().SendCoin(gomock.Any(), gomock.Any()).Return(nil, application.ErrRecipientNotFound)
+			},
+			status: fiber.StatusNotFound,
+		},
+		{
+			name:   "send coin invalid request",
+			method: http.MethodPost, path: "/api/sendCoin", target: "/api/sendCoin",
+			body: `{"toUser":"user 2","amount":-10}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().SendCoin(gomock.Any(), gomock.Any()).Return(nil, &application.ValidationError{
+					Fields: []application.FieldError{
+						{Field: "amount", Rule: application.RuleMin, Message: "must be positive"},
+						{Field: "toUser", Rule: application.RuleFormat, Message: "must not contain spaces or control characters"},
+					},
+				})
+			},
+			status: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:   "checkout",
+			method: http.MethodPost, path: "/api/checkout", target: "/api/checkout",
+			body: `{"items":[{"item":"cup","quantity":2}]}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().Checkout(gomock.Any(), gomock.Any()).Return(&application.CheckoutResponse{Total: 40}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "orders",
+			method: http.MethodGet, path: "/api/orders", target: "/api/orders?limit=10&offset=0",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetOrders(gomock.Any(), gomock.Any()).Return(orders, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "return order",
+			method: http.MethodPost, path: "/api/orders/{id}/return", target: "/api/orders/1/return",
+			body: `{"quantity":1}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().ReturnOrder(gomock.Any(), gomock.Any()).Return(&application.ReturnOrderResponse{Amount: 20}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "return expired order",
+			method: http.MethodPost, path: "/api/orders/{id}/return", target: "/api/orders/1/return",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().ReturnOrder(gomock.Any(), gomock.Any()).Return(nil, application.ErrReturnExpired)
+			},
+			status: fiber.StatusConflict,
+		},
+		{
+			name:   "items",
+			method: http.MethodGet, path: "/api/items", target: "/api/items",
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetItems(gomock.Any(), gomock.Any()).Return(&application.GetItemsResponse{
+					Items: []*application.Item{
+						{Name: "cup", Price: 20},
+						{Name: "hoody", Price: 300, Stock: &stock},
+					},
+				}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "item",
+			method: http.MethodGet, path: "/api/items/{name}", target: "/api/items/cup",
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(&application.GetItemResponse{
+					Item: &application.Item{Name: "cup", Price: 20},
+				}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "unknown item",
+			method: http.MethodGet, path: "/api/items/{name}", target: "/api/items/car",
+			anonymous: true,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetItem(gomock.Any(), gomock.Any()).Return(nil, application.ErrItemNotFound)
+			},
+			status: fiber.StatusNotFound,
+		},
+		{
+			name:   "create item",
+			method: http.MethodPost, path: "/api/admin/items", target: "/api/admin/items",
+			body: `{"name":"mug","price":30,"stock":null}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().CreateItem(gomock.Any(), gomock.Any()).Return(&application.CreateItemResponse{}, nil)
+			},
+			status: fiber.StatusCreated,
+		},
+		{
+			name:   "create item forbidden",
+			method: http.MethodPost, path: "/api/admin/items", target: "/api/admin/items",
+			body: `{"name":"mug","price":30}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().CreateItem(gomock.Any(), gomock.Any()).Return(nil, application.ErrForbidden)
+			},
+			status: fiber.StatusForbidden,
+		},
+		{
+			name:   "update item price",
+			method: http.MethodPost, path: "/api/admin/items/{name}/price", target: "/api/admin/items/cup/price",
+			body: `{"price":25}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().UpdateItemPrice(gomock.Any(), gomock.Any()).Return(&application.UpdateItemPriceResponse{}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "rename item",
+			method: http.MethodPost, path: "/api/admin/items/{name}/rename", target: "/api/admin/items/cup/rename",
+			body: `{"name":"mug"}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().RenameItem(gomock.Any(), gomock.Any()).Return(&application.RenameItemResponse{}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "retire item",
+			method: http.MethodDelete, path: "/api/admin/items/{name}", target: "/api/admin/items/cup",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().RetireItem(gomock.Any(), gomock.Any()).Return(&application.RetireItemResponse{}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "restock item",
+			method: http.MethodPost, path: "/api/admin/items/{name}/restock", target: "/api/admin/items/hoody/restock",
+			body: `{"amount":5}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().RestockItem(gomock.Any(), gomock.Any()).Return(&application.RestockItemResponse{Stock: 10}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "item prices",
+			method: http.MethodGet, path: "/api/admin/items/{name}/prices", target: "/api/admin/items/cup/prices",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetItemPrices(gomock.Any(), gomock.Any()).Return(&application.GetItemPricesResponse{
+					Prices: []*application.ItemPrice{{Price: 20, ValidFrom: createdAt}},
+				}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "check ledger",
+			method: http.MethodGet, path: "/api/admin/ledger/check", target: "/api/admin/ledger/check",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().CheckLedger(gomock.Any(), gomock.Any()).Return(&application.CheckLedgerResponse{
+					Mismatches: []*application.BalanceMismatch{{UserName: "user1", Balance: 100, LedgerBalance: 90}},
+				}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "revoke sessions",
+			method: http.MethodPost, path: "/api/admin/users/{username}/revoke-sessions",
+			target: "/api/admin/users/user1/revoke-sessions",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().RevokeUserSessions(gomock.Any(), gomock.Any()).
+					Return(&application.RevokeUserSessionsResponse{}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "set role",
+			method: http.MethodPut, path: "/api/admin/users/{username}/role", target: "/api/admin/users/user1/role",
+			body: `{"role":"auditor"}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().SetUserRole(gomock.Any(), gomock.Any()).Return(&application.SetUserRoleResponse{}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "credit",
+			method: http.MethodPost, path: "/api/admin/users/{username}/credit", target: "/api/admin/users/user1/credit",
+			body: `{"amount":100,"reason":"bonus"}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().AdjustBalance(gomock.Any(), gomock.Any()).Return(&application.AdjustBalanceResponse{Balance: 1100}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "debit",
+			method: http.MethodPost, path: "/api/admin/users/{username}/debit", target: "/api/admin/users/user1/debit",
+			body: `{"amount":100,"reason":"fine","force":true}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().AdjustBalance(gomock.Any(), gomock.Any()).Return(&application.AdjustBalanceResponse{Balance: -50}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "debit without coins",
+			method: http.MethodPost, path: "/api/admin/users/{username}/debit", target: "/api/admin/users/user1/debit",
+			body: `{"amount":100,"reason":"fine"}`,
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().AdjustBalance(gomock.Any(), gomock.Any()).Return(nil, application.ErrInsufficientFunds)
+			},
+			status: fiber.StatusConflict,
+		},
+		{
+			name:   "audit",
+			method: http.MethodGet, path: "/api/admin/audit", target: "/api/admin/audit?outcome=failure",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetAuditEvents(gomock.Any(), gomock.Any()).Return(&application.GetAuditEventsResponse{
+					Events: []*application.AuditEvent{
+						{
+							Id: 2, Actor: "admin", Action: "set_user_role", Target: "user1", IP: "10.0.0.1",
+							Outcome: "success", Details: map[string]any{"role": "auditor"}, CreatedAt: createdAt,
+						},
+						{Id: 1, Action: "login", Outcome: "failure", CreatedAt: createdAt},
+					},
+					NextCursor: "cursor",
+				}, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "user info",
+			method: http.MethodGet, path: "/api/admin/users/{username}/info", target: "/api/admin/users/user1/info",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetInfo(gomock.Any(), gomock.Any()).Return(info, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "user info forbidden",
+			method: http.MethodGet, path: "/api/admin/users/{username}/info", target: "/api/admin/users/user1/info",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetInfo(gomock.Any(), gomock.Any()).Return(nil, application.ErrForbidden)
+			},
+			status: fiber.StatusForbidden,
+		},
+		{
+			name:   "user history",
+			method: http.MethodGet, path: "/api/admin/users/{username}/history", target: "/api/admin/users/user1/history",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetHistory(gomock.Any(), gomock.Any()).Return(history, nil)
+			},
+			status: fiber.StatusOK,
+		},
+		{
+			name:   "unknown user orders",
+			method: http.MethodGet, path: "/api/admin/users/{username}/orders", target: "/api/admin/users/nobody/orders",
+			want: func(mockApp *mocks.MockShopService) {
+				mockApp.EXPECT().GetOrders(gomock.Any(), gomock.Any()).Return(nil, application.ErrUserNotFound)
+			},
+			status: fiber.StatusNotFound,
+		},
+	}
+	contract := loadContract(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockApp := mocks.NewMockShopService(ctrl)
+			if !tt.anonymous {
+				expectAuthenticated(mockApp)
+			}
+			if tt.want != nil {
+				tt.want(mockApp)
+			}
+
+			api := rest.NewAPI(nil, &rest.Config{IsAdditionalErrorsEnabled: true}, mockApp)
+			app := fiber.New()
+			api.Mount(app)
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if !tt.anonymous {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+			resp, _ := app.Test(req)
+
+			require.Equal(t, tt.status, resp.StatusCode)
+			contract.assertResponse(t, req, tt.path, resp)
+		})
+	}
+}
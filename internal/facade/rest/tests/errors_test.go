@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   string
+	}{
+		{
+			name:   "forbidden",
+			err:    application.ErrForbidden,
+			status: fiber.StatusForbidden,
+			body:   `{"errors":"forbidden"}`,
+		},
+		{
+			name:   "user not found",
+			err:    fmt.Errorf("error get info: %w", application.ErrUserNotFound),
+			status: fiber.StatusNotFound,
+			body:   `{"errors":"user not found"}`,
+		},
+		{
+			name:   "insufficient funds",
+			err:    application.ErrInsufficientFunds,
+			status: fiber.StatusConflict,
+			body:   `{"errors":"not enough coins"}`,
+		},
+		{
+			name:   "invalid request",
+			err:    fmt.Errorf("%w: limit must be between 1 and 100", application.ErrInvalidRequest),
+			status: fiber.StatusUnprocessableEntity,
+			body:   `{"errors":"invalid request: limit must be between 1 and 100"}`,
+		},
+		{
+			name:   "unauthenticated",
+			err:    application.ErrUnauthenticated,
+			status: fiber.StatusUnauthorized,
+			body:   `{"errors":"request is not authenticated"}`,
+		},
+		{
+			name:   "unexpected",
+			err:    fmt.Errorf("error get info from db: %w", fmt.Errorf("connection refused")),
+			status: fiber.StatusInternalServerError,
+			body:   `{"errors":"internal error"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockApp := mocks.NewMockShopService(ctrl)
+			expectAuthenticated(mockApp)
+			mockApp.EXPECT().GetInfo(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			api := rest.NewAPI(nil, nil, mockApp)
+			app := fiber.New()
+			app.Add("GET", "/api/info", api.RequireAuth, api.Info)
+			req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			resp, _ := app.Test(req)
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			body, _ := io.ReadAll(resp.Body)
+			assert.JSONEq(t, tt.body, string(body))
+		})
+	}
+}
+
+func TestSendError_AdditionalErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetInfo(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("error get info from db: %w", fmt.Errorf("connection refused")))
+
+	api := rest.NewAPI(nil, &rest.Config{IsAdditionalErrorsEnabled: true}, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/info", api.RequireAuth, api.Info)
+	req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"errors":"internal error","details":"error get info from db: connection refused"}`, string(body))
+}
+
+func TestSendError_AdditionalErrorsHideClientErrorCause(t *testing.T) {
+	for _, cause := range []error{application.ErrUnknownUser, application.ErrBadPassword} {
+		ctrl := gomock.NewController(t)
+		mockApp := mocks.NewMockShopService(ctrl)
+		mockApp.EXPECT().Auth(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("%w: %w", application.ErrInvalidCredentials, cause))
+
+		api := rest.NewAPI(nil, &rest.Config{IsAdditionalErrorsEnabled: true}, mockApp)
+		app := fiber.New()
+		app.Add("POST", "/api/auth", api.Auth)
+		req := httptest.NewRequest(http.MethodPost, "/api/auth", strings.NewReader(`{"username":"user","password":"pass"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		assert.JSONEq(t, `{"errors":"invalid credentials"}`, string(body), cause.Error())
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHistory_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetHistory(gomock.Any(), &application.GetHistoryRequest{
+		Principal:    principal,
+		Direction:    "received",
+		Counterparty: "user2",
+		From:         time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:           time.Date(2025, 2, 14, 12, 0, 0, 0, time.UTC),
+		Limit:        10,
+		Cursor:       "next",
+	}).Return(&application.GetHistoryResponse{
+		Transactions: []*application.HistoryEntry{
+			{Id: 3, Direction: "received", FromUser: "user2", ToUser: "user1", Amount: 10},
+		},
+		NextCursor: "after",
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/history", api.RequireAuth, api.History)
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/history?direction=received&counterparty=user2&from=2025-02-01&to=2025-02-14T12:00:00Z&limit=10&cursor=next", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Transactions []struct {
+			FromUser string `json:"fromUser"`
+		} `json:"transactions"`
+		NextCursor string `json:"nextCursor"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Transactions, 1)
+	assert.Equal(t, "after", body.NextCursor)
+}
+
+func TestHistory_InvalidDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/history", api.RequireAuth, api.History)
+	req := httptest.NewRequest(http.MethodGet, "/api/history?from=yesterday", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestHistory_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetHistory(gomock.Any(), gomock.Any()).Return(nil, application.ErrInvalidCursor)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/history", api.RequireAuth, api.History)
+	req := httptest.NewRequest(http.MethodGet, "/api/history?cursor=abc", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateItem_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().CreateItem(gomock.Any(), &application.CreateItemRequest{
+		Principal: principal,
+		Name:      "sticker",
+		Price:     5,
+	}).Return(&application.CreateItemResponse{}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/items", api.RequireAuth, api.CreateItem)
+	requestBody, _ := json.Marshal(map[string]any{
+		"name":  "sticker",
+		"price": 5,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/items", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+}
+
+func TestCreateItem_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().CreateItem(gomock.Any(), &application.CreateItemRequest{
+		Principal: principal,
+		Name:      "sticker",
+		Price:     5,
+	}).Return(nil, application.ErrForbidden)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/items", api.RequireAuth, api.CreateItem)
+	requestBody, _ := json.Marshal(map[string]any{
+		"name":  "sticker",
+		"price": 5,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/items", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestCreateItem_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().CreateItem(gomock.Any(), &application.CreateItemRequest{
+		Principal: principal,
+		Name:      "cup",
+		Price:     20,
+	}).Return(nil, application.ErrItemExists)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/items", api.RequireAuth, api.CreateItem)
+	requestBody, _ := json.Marshal(map[string]any{
+		"name":  "cup",
+		"price": 20,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/items", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func TestUpdateItemPrice_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().UpdateItemPrice(gomock.Any(), &application.UpdateItemPriceRequest{
+		Principal: principal,
+		Name:      "cup",
+		Price:     25,
+	}).Return(&application.UpdateItemPriceResponse{}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/items/:name/price", api.RequireAuth, api.UpdateItemPrice)
+	requestBody, _ := json.Marshal(map[string]any{
+		"price": 25,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/items/cup/price", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestRenameItem_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().RenameItem(gomock.Any(), &application.RenameItemRequest{
+		Principal: principal,
+		Name:      "cup",
+		NewName:   "mug",
+	}).Return(&application.RenameItemResponse{}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/items/:name/rename", api.RequireAuth, api.RenameItem)
+	requestBody, _ := json.Marshal(map[string]any{
+		"name": "mug",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/items/cup/rename", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestRetireItem_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().RetireItem(gomock.Any(), &application.RetireItemRequest{
+		Principal: principal,
+		Name:      "car",
+	}).Return(nil, application.ErrItemNotFound)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("DELETE", "/api/admin/items/:name", api.RequireAuth, api.RetireItem)
+	req := httptest.NewRequest(http.MethodDelete, "/api/admin/items/car", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func TestItemPrices_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetItemPrices(gomock.Any(), &application.GetItemPricesRequest{
+		Principal: principal,
+		Name:      "cup",
+	}).Return(&application.GetItemPricesResponse{
+		Prices: []*application.ItemPrice{
+			{Price: 20},
+			{Price: 25},
+		},
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/admin/items/:name/prices", api.RequireAuth, api.ItemPrices)
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/items/cup/prices", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Prices []struct {
+			Price int `json:"price"`
+		} `json:"prices"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Prices, 2)
+}
+
+func TestRestockItem_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().RestockItem(gomock.Any(), &application.RestockItemRequest{
+		Principal: principal,
+		Name:      "pink-hoody",
+		Amount:    40,
+	}).Return(&application.RestockItemResponse{Stock: 40}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/items/:name/restock", api.RequireAuth, api.RestockItem)
+	requestBody, _ := json.Marshal(map[string]any{
+		"amount": 40,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/items/pink-hoody/restock", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Stock int `json:"stock"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 40, body.Stock)
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJWKS_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().GetJWKS(gomock.Any(), &application.GetJWKSRequest{}).Return(&application.GetJWKSResponse{
+		Keys: []*application.JSONWebKey{
+			{Kty: "RSA", Kid: "key-1", Alg: "RS256", Use: "sig", N: "n", E: "AQAB"},
+			{Kty: "OKP", Kid: "key-2", Alg: "EdDSA", Use: "sig", Crv: "Ed25519", X: "x"},
+		},
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/.well-known/jwks.json", api.JWKS)
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, max-age=300", resp.Header.Get("Cache-Control"))
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"keys":[
+		{"kty":"RSA","kid":"key-1","alg":"RS256","use":"sig","n":"n","e":"AQAB"},
+		{"kty":"OKP","kid":"key-2","alg":"EdDSA","use":"sig","crv":"Ed25519","x":"x"}
+	]}`, string(body))
+}
+
+func TestJWKS_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().GetJWKS(gomock.Any(), gomock.Any()).Return(&application.GetJWKSResponse{}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/.well-known/jwks.json", api.JWKS)
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"keys":[]}`, string(body))
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckLedger_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().CheckLedger(gomock.Any(), &application.CheckLedgerRequest{
+		Principal: principal,
+	}).Return(&application.CheckLedgerResponse{
+		Mismatches: []*application.BalanceMismatch{},
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/admin/ledger/check", api.RequireAuth, api.CheckLedger)
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/ledger/check", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Consistent bool `json:"consistent"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.True(t, body.Consistent)
+}
+
+func TestCheckLedger_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().CheckLedger(gomock.Any(), gomock.Any()).Return(nil, application.ErrForbidden)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/admin/ledger/check", api.RequireAuth, api.CheckLedger)
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/ledger/check", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
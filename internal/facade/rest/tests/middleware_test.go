@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// principal is what "Bearer token" authenticates as in the handler tests.
+var principal = &application.Principal{UserId: 1, TokenId: "jti"}
+
+func expectAuthenticated(mockApp *mocks.MockShopService) {
+	mockApp.EXPECT().Authenticate(gomock.Any(), &application.AuthenticateRequest{
+		Token: "token",
+	}).Return(&application.AuthenticateResponse{Principal: principal}, nil)
+}
+
+func TestRequireAuth_MissingToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/info", api.RequireAuth, api.Info)
+	for _, header := range []string{"", "token", "Bearer "} {
+		req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+		req.Header.Set("Authorization", header)
+		resp, _ := app.Test(req)
+
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode, header)
+		assert.Equal(t, "Bearer", resp.Header.Get("WWW-Authenticate"))
+	}
+}
+
+func TestRequireAuth_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("%w: token is expired", application.ErrInvalidToken))
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/info", api.RequireAuth, api.Info)
+	req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRequireAuth_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("some error"))
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/info", api.RequireAuth, api.Info)
+	req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
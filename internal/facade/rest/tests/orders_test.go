@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOrders_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetOrders(gomock.Any(), &application.GetOrdersRequest{
+		Principal: principal,
+		Limit:     10,
+		Offset:    20,
+	}).Return(&application.GetOrdersResponse{
+		Orders: []*application.Order{
+			{Id: 1, Item: "cup", Price: 20, Quantity: 2, CreatedAt: time.Time{}},
+		},
+		Total: 21,
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/orders", api.RequireAuth, api.Orders)
+	req := httptest.NewRequest(http.MethodGet, "/api/orders?limit=10&offset=20", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Orders []struct {
+			Item     string `json:"item"`
+			Quantity int    `json:"quantity"`
+		} `json:"orders"`
+		Total int `json:"total"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 21, body.Total)
+	assert.Len(t, body.Orders, 1)
+	assert.Equal(t, "cup", body.Orders[0].Item)
+}
+
+func TestOrders_InvalidLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/orders", api.RequireAuth, api.Orders)
+	req := httptest.NewRequest(http.MethodGet, "/api/orders?limit=ten", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestReturnOrder_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().ReturnOrder(gomock.Any(), &application.ReturnOrderRequest{
+		Principal: principal,
+		OrderId:   5,
+		Quantity:  1,
+	}).Return(&application.ReturnOrderResponse{Amount: 20}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/orders/:id/return", api.RequireAuth, api.ReturnOrder)
+	req := httptest.NewRequest(http.MethodPost, "/api/orders/5/return", bytes.NewReader([]byte(`{"quantity":1}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Amount int `json:"amount"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 20, body.Amount)
+}
+
+func TestReturnOrder_WholeOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().ReturnOrder(gomock.Any(), &application.ReturnOrderRequest{
+		Principal: principal,
+		OrderId:   5,
+	}).Return(&application.ReturnOrderResponse{Amount: 40}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/orders/:id/return", api.RequireAuth, api.ReturnOrder)
+	req := httptest.NewRequest(http.MethodPost, "/api/orders/5/return", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestReturnOrder_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().ReturnOrder(gomock.Any(), gomock.Any()).Return(nil, application.ErrReturnExpired)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/orders/:id/return", api.RequireAuth, api.ReturnOrder)
+	req := httptest.NewRequest(http.MethodPost, "/api/orders/5/return", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func TestReturnOrder_InvalidId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/orders/:id/return", api.RequireAuth, api.ReturnOrder)
+	req := httptest.NewRequest(http.MethodPost, "/api/orders/abc/return", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
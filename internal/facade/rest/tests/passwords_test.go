@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newChangePasswordRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/password",
+		bytes.NewReader([]byte(`{"currentPassword":"pass","newPassword":"secret42"}`)))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestChangePassword_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().ChangePassword(gomock.Any(), &application.ChangePasswordRequest{
+		Principal:       principal,
+		CurrentPassword: "pass",
+		NewPassword:     "secret42",
+	}).Return(&application.ChangePasswordResponse{
+		Token:        "abc",
+		RefreshToken: "def",
+		ExpiresIn:    15 * time.Minute,
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/auth/password", api.RequireAuth, api.ChangePassword)
+	resp, _ := app.Test(newChangePasswordRequest())
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"token":"abc","refreshToken":"def","expiresIn":900}`, string(body))
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("%w: %w", application.ErrInvalidCredentials, application.ErrBadPassword))
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/auth/password", api.RequireAuth, api.ChangePassword)
+	resp, _ := app.Test(newChangePasswordRequest())
+
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestChangePassword_InvalidPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("%w: must contain a letter and a digit", application.ErrInvalidPassword))
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/auth/password", api.RequireAuth, api.ChangePassword)
+	resp, _ := app.Test(newChangePasswordRequest())
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	assert.JSONEq(t, `{"errors":"invalid password: must contain a letter and a digit"}`, string(body))
+}
+
+func TestChangePassword_TooManyAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).
+		Return(nil, &application.TooManyAttemptsError{RetryAfter: time.Minute})
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/auth/password", api.RequireAuth, api.ChangePassword)
+	resp, _ := app.Test(newChangePasswordRequest())
+
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func TestChangePassword_Unauthenticated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/auth/password", api.RequireAuth, api.ChangePassword)
+	req := newChangePasswordRequest()
+	req.Header.Del("Authorization")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetUserRole_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SetUserRole(gomock.Any(), &application.SetUserRoleRequest{
+		Principal: principal,
+		UserName:  "user2",
+		Role:      application.RoleAuditor,
+	}).Return(&application.SetUserRoleResponse{}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("PUT", "/api/admin/users/:username/role", api.RequireAuth, api.SetUserRole)
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/users/user2/role", bytes.NewReader([]byte(`{"role":"auditor"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestSetUserRole_UnknownRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SetUserRole(gomock.Any(), gomock.Any()).Return(nil, application.ErrUnknownRole)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("PUT", "/api/admin/users/:username/role", api.RequireAuth, api.SetUserRole)
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/users/user2/role", bytes.NewReader([]byte(`{"role":"root"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestSetUserRole_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SetUserRole(gomock.Any(), gomock.Any()).Return(nil, application.ErrForbidden)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("PUT", "/api/admin/users/:username/role", api.RequireAuth, api.SetUserRole)
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/users/user2/role", bytes.NewReader([]byte(`{"role":"admin"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestUserInfo_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetInfo(gomock.Any(), &application.GetInfoRequest{
+		Principal: principal,
+		UserName:  "user2",
+	}).Return(nil, application.ErrForbidden)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/admin/users/:username/info", api.RequireAuth, api.Info)
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users/user2/info", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestUserHistory_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetHistory(gomock.Any(), gomock.Any()).Return(nil, application.ErrUserNotFound)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/admin/users/:username/history", api.RequireAuth, api.History)
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users/nobody/history", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
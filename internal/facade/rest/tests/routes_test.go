@@ -3,7 +3,6 @@ package tests
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/azaliaz/avito-shop/internal/application"
@@ -11,6 +10,7 @@ import (
 	"github.com/azaliaz/avito-shop/internal/facade/rest"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -24,7 +24,9 @@ func TestAuth_Success(t *testing.T) {
 		Password: "testpass",
 		Username: "testuser",
 	}).Return(&application.AuthResponse{
-		Token: "abc",
+		Token:        "abc",
+		RefreshToken: "def",
+		ExpiresIn:    15 * time.Minute,
 	}, nil)
 
 	api := rest.NewAPI(nil, nil, mockApp)
@@ -39,6 +41,15 @@ func TestAuth_Success(t *testing.T) {
 	resp, _ := app.Test(req)
 
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken"`
+		ExpiresIn    int    `json:"expiresIn"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "abc", body.Token)
+	assert.Equal(t, "def", body.RefreshToken)
+	assert.Equal(t, 900, body.ExpiresIn)
 }
 
 func TestAuth_InvalidJSON(t *testing.T) {
@@ -61,7 +72,7 @@ func TestAuth_InvalidCredentials(t *testing.T) {
 	mockApp.EXPECT().Auth(gomock.Any(), &application.AuthRequest{
 		Password: "wrongpass",
 		Username: "wronguser",
-	}).Return(nil, errors.New("invalid credentials"))
+	}).Return(nil, fmt.Errorf("%w: %w", application.ErrInvalidCredentials, application.ErrBadPassword))
 
 	api := rest.NewAPI(nil, nil, mockApp)
 	app := fiber.New()
@@ -78,17 +89,43 @@ func TestAuth_InvalidCredentials(t *testing.T) {
 	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
 }
 
+func TestAuth_TooManyAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Auth(gomock.Any(), &application.AuthRequest{
+		Password: "wrongpass",
+		Username: "testuser",
+	}).Return(nil, &application.TooManyAttemptsError{RetryAfter: 1500 * time.Millisecond})
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/auth", api.Auth)
+
+	requestBody, _ := json.Marshal(map[string]string{
+		"username": "testuser",
+		"password": "wrongpass",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
 func TestBuyItem_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
 	mockApp.EXPECT().BuyItem(gomock.Any(), &application.BuyItemRequest{
-		Token: "token",
-		Item:  "cup",
+		Principal: principal,
+		Item:      "cup",
+		Quantity:  1,
 	}).Return(&application.BuyItemResponse{}, nil)
 
 	api := rest.NewAPI(nil, nil, mockApp)
 	app := fiber.New()
-	app.Add("GET", "/api/buy/:item", api.BuyItem)
+	app.Add("GET", "/api/buy/:item", api.RequireAuth, api.BuyItem)
 	req := httptest.NewRequest(http.MethodGet, "/api/buy/cup", nil)
 	req.Header.Set("Authorization", "Bearer token")
 	resp, _ := app.Test(req)
@@ -96,29 +133,135 @@ func TestBuyItem_Success(t *testing.T) {
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 }
 
-func TestBuyItem_BadRequest(t *testing.T) {
+func TestBuyItem_InsufficientFunds(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
 	mockApp.EXPECT().BuyItem(gomock.Any(), &application.BuyItemRequest{
-		Token: "token",
-		Item:  "cup",
-	}).Return(nil, fmt.Errorf("error"))
+		Principal: principal,
+		Item:      "cup",
+		Quantity:  1,
+	}).Return(nil, application.ErrInsufficientFunds)
 
 	api := rest.NewAPI(nil, nil, mockApp)
 	app := fiber.New()
-	app.Add("GET", "/api/buy/:item", api.BuyItem)
+	app.Add("GET", "/api/buy/:item", api.RequireAuth, api.BuyItem)
 	req := httptest.NewRequest(http.MethodGet, "/api/buy/cup", nil)
 	req.Header.Set("Authorization", "Bearer token")
 	resp, _ := app.Test(req)
 
-	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func TestBuyItem_WithQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().BuyItem(gomock.Any(), &application.BuyItemRequest{
+		Principal: principal,
+		Item:      "pen",
+		Quantity:  20,
+	}).Return(&application.BuyItemResponse{}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/buy/:item", api.RequireAuth, api.BuyItem)
+	req := httptest.NewRequest(http.MethodGet, "/api/buy/pen?quantity=20", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestBuyItem_InvalidQuantity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().BuyItem(gomock.Any(), &application.BuyItemRequest{
+		Principal: principal,
+		Item:      "pen",
+		Quantity:  -1,
+	}).Return(nil, &application.ValidationError{Fields: []application.FieldError{
+		{Field: "quantity", Rule: application.RuleMin, Message: "must be positive"},
+	}})
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/buy/:item", api.RequireAuth, api.BuyItem)
+	req := httptest.NewRequest(http.MethodGet, "/api/buy/pen?quantity=-1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestBuyItem_QuantityNotAnInteger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/buy/:item", api.RequireAuth, api.BuyItem)
+	req := httptest.NewRequest(http.MethodGet, "/api/buy/pen?quantity=two", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"errors":"invalid request: quantity must be an integer",
+		"fields":[{"field":"quantity","rule":"type","message":"must be an integer"}]}`, string(body))
+}
+
+func TestBuyItem_SoldOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().BuyItem(gomock.Any(), &application.BuyItemRequest{
+		Principal: principal,
+		Item:      "pink-hoody",
+		Quantity:  1,
+	}).Return(nil, application.ErrItemSoldOut)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/buy/:item", api.RequireAuth, api.BuyItem)
+	req := httptest.NewRequest(http.MethodGet, "/api/buy/pink-hoody", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func TestBuyItem_IdempotencyKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().BuyItem(gomock.Any(), &application.BuyItemRequest{
+		Principal:      principal,
+		Item:           "cup",
+		Quantity:       1,
+		IdempotencyKey: "key-1",
+	}).Return(&application.BuyItemResponse{Replayed: true}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/buy/:item", api.RequireAuth, api.BuyItem)
+	req := httptest.NewRequest(http.MethodGet, "/api/buy/cup", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Idempotency-Key", "key-1")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
 }
 
 func TestInfo_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
 	mockApp.EXPECT().GetInfo(gomock.Any(), &application.GetInfoRequest{
-		Token: "token",
+		Principal: principal,
 	}).Return(&application.GetInfoResponse{
 		CoinHistory: &application.CoinHistory{
 			Received: []*application.Transaction{
@@ -149,7 +292,7 @@ func TestInfo_Success(t *testing.T) {
 
 	api := rest.NewAPI(nil, nil, mockApp)
 	app := fiber.New()
-	app.Add("GET", "/api/info", api.Info)
+	app.Add("GET", "/api/info", api.RequireAuth, api.Info)
 	req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
 	req.Header.Set("Authorization", "Bearer token")
 	resp, _ := app.Test(req)
@@ -157,35 +300,39 @@ func TestInfo_Success(t *testing.T) {
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 }
 
-func TestInfo_BadRequest(t *testing.T) {
+func TestInfo_InternalError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
 	mockApp.EXPECT().GetInfo(gomock.Any(), &application.GetInfoRequest{
-		Token: "token",
+		Principal: principal,
 	}).Return(nil, fmt.Errorf("error"))
 
 	api := rest.NewAPI(nil, nil, mockApp)
 	app := fiber.New()
-	app.Add("GET", "/api/info", api.Info)
+	app.Add("GET", "/api/info", api.RequireAuth, api.Info)
 	req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
 	req.Header.Set("Authorization", "Bearer token")
 	resp, _ := app.Test(req)
 
-	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"errors":"internal error"}`, string(body))
 }
 
 func TestSendCoin_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
 	mockApp.EXPECT().SendCoin(gomock.Any(), &application.SendCoinRequest{
-		Token:  "token",
-		Amount: 100,
-		ToUser: "username1",
+		Principal: principal,
+		Amount:    100,
+		ToUser:    "username1",
 	}).Return(&application.SendCoinResponse{}, nil)
 
 	api := rest.NewAPI(nil, nil, mockApp)
 	app := fiber.New()
-	app.Add("POST", "/api/sendCoin", api.SendCoin)
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
 	requestBody, _ := json.Marshal(map[string]string{
 		"amount": "100",
 		"toUser": "username1",
@@ -198,18 +345,19 @@ func TestSendCoin_Success(t *testing.T) {
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 }
 
-func TestSendCoin_BadRequest(t *testing.T) {
+func TestSendCoin_RecipientNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
 	mockApp.EXPECT().SendCoin(gomock.Any(), &application.SendCoinRequest{
-		Token:  "token",
-		Amount: 100,
-		ToUser: "username2",
-	}).Return(nil, fmt.Errorf("error"))
+		Principal: principal,
+		Amount:    100,
+		ToUser:    "username2",
+	}).Return(nil, application.ErrRecipientNotFound)
 
 	api := rest.NewAPI(nil, nil, mockApp)
 	app := fiber.New()
-	app.Add("POST", "/api/sendCoin", api.SendCoin)
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
 	requestBody, _ := json.Marshal(map[string]string{
 		"amount": "100",
 		"toUser": "username2",
@@ -219,16 +367,17 @@ func TestSendCoin_BadRequest(t *testing.T) {
 	req.Header.Set("Authorization", "Bearer token")
 	resp, _ := app.Test(req)
 
-	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
 }
 
 func TestSendCoin_InvalidAmount(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
 
 	api := rest.NewAPI(nil, nil, mockApp)
 	app := fiber.New()
-	app.Add("POST", "/api/sendCoin", api.SendCoin)
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
 	requestBody, _ := json.Marshal(map[string]string{
 		"amount": "s",
 		"toUser": "1",
@@ -238,16 +387,20 @@ func TestSendCoin_InvalidAmount(t *testing.T) {
 	req.Header.Set("Authorization", "Bearer token")
 	resp, _ := app.Test(req)
 
-	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"errors":"invalid request: amount must be an integer",
+		"fields":[{"field":"amount","rule":"type","message":"must be an integer"}]}`, string(body))
 }
 
 func TestSendCoin_InvalidJSON(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
 
 	api := rest.NewAPI(nil, nil, mockApp)
 	app := fiber.New()
-	app.Add("POST", "/api/sendCoin", api.SendCoin)
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
 	req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", bytes.NewReader([]byte("{")))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer token")
@@ -255,3 +408,171 @@ func TestSendCoin_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 }
+
+func TestItems_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().GetItems(gomock.Any(), &application.GetItemsRequest{}).Return(&application.GetItemsResponse{
+		Items: []*application.Item{
+			{Name: "cup", Price: 20},
+			{Name: "pen", Price: 10},
+		},
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/items", api.Items)
+	req := httptest.NewRequest(http.MethodGet, "/api/items", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Items []struct {
+			Name  string `json:"name"`
+			Price int    `json:"price"`
+		} `json:"items"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Items, 2)
+	assert.Equal(t, "cup", body.Items[0].Name)
+	assert.Equal(t, 20, body.Items[0].Price)
+}
+
+func TestItems_InternalError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().GetItems(gomock.Any(), &application.GetItemsRequest{}).Return(nil, fmt.Errorf("error"))
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/items", api.Items)
+	req := httptest.NewRequest(http.MethodGet, "/api/items", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestItem_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().GetItem(gomock.Any(), &application.GetItemRequest{
+		Name: "cup",
+	}).Return(&application.GetItemResponse{
+		Item: &application.Item{Name: "cup", Price: 20},
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/items/:name", api.Item)
+	req := httptest.NewRequest(http.MethodGet, "/api/items/cup", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestItem_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().GetItem(gomock.Any(), &application.GetItemRequest{
+		Name: "car",
+	}).Return(nil, application.ErrItemNotFound)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/items/:name", api.Item)
+	req := httptest.NewRequest(http.MethodGet, "/api/items/car", nil)
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func TestSendCoin_IdempotencyKeyReused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SendCoin(gomock.Any(), &application.SendCoinRequest{
+		Principal:      principal,
+		Amount:         100,
+		ToUser:         "username1",
+		IdempotencyKey: "key-1",
+	}).Return(nil, application.ErrIdempotencyKeyReused)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
+	requestBody, _ := json.Marshal(map[string]string{
+		"amount": "100",
+		"toUser": "username1",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Idempotency-Key", "key-1")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestSendCoin_WithMemo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SendCoin(gomock.Any(), &application.SendCoinRequest{
+		Principal: principal,
+		Amount:    100,
+		ToUser:    "username1",
+		Memo:      "thanks for the review",
+	}).Return(&application.SendCoinResponse{}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
+	requestBody, _ := json.Marshal(map[string]string{
+		"amount": "100",
+		"toUser": "username1",
+		"memo":   "thanks for the review",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestInfo_WithMemo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().GetInfo(gomock.Any(), &application.GetInfoRequest{
+		Principal: principal,
+	}).Return(&application.GetInfoResponse{
+		CoinHistory: &application.CoinHistory{
+			Received: []*application.Transaction{
+				{Amount: 100, FromUser: "user2", ToUser: "user1", Memo: "thanks for the review"},
+			},
+			Sent: []*application.Transaction{
+				{Amount: 50, FromUser: "user1", ToUser: "user2"},
+			},
+		},
+		Coins: 1050,
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("GET", "/api/info", api.RequireAuth, api.Info)
+	req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		CoinHistory struct {
+			Received []map[string]any `json:"received"`
+			Sent     []map[string]any `json:"sent"`
+		} `json:"coinHistory"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "thanks for the review", body.CoinHistory.Received[0]["memo"])
+	assert.NotContains(t, body.CoinHistory.Sent[0], "memo")
+}
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newRegisterRequest(username, password string) *http.Request {
+	requestBody, _ := json.Marshal(map[string]string{
+		"username": username,
+		"password": password,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/register", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestRegister_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Register(gomock.Any(), &application.RegisterRequest{
+		Username: "user_1",
+		Password: "password123",
+	}).Return(&application.RegisterResponse{
+		Token:        "abc",
+		RefreshToken: "def",
+		ExpiresIn:    15 * time.Minute,
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/register", api.Register)
+	resp, _ := app.Test(newRegisterRequest("user_1", "password123"))
+
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"token":"abc","refreshToken":"def","expiresIn":900}`, string(body))
+}
+
+func TestRegister_InvalidJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/register", api.Register)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/register", bytes.NewReader([]byte("{invalid_json}")))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestRegister_PolicyViolation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Register(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("%w: must be at least 8 characters long", application.ErrInvalidPassword))
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/register", api.Register)
+	resp, _ := app.Test(newRegisterRequest("user_1", "short"))
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	var body struct {
+		Errors string `json:"errors"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "invalid password: must be at least 8 characters long", body.Errors)
+}
+
+func TestRegister_UserExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Register(gomock.Any(), gomock.Any()).Return(nil, application.ErrUserExists)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/register", api.Register)
+	resp, _ := app.Test(newRegisterRequest("user_1", "password123"))
+
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+func TestAuth_UnknownUserIsGeneric(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Auth(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("%w: %w", application.ErrInvalidCredentials, application.ErrUnknownUser))
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/auth", api.Auth)
+	requestBody, _ := json.Marshal(map[string]string{
+		"username": "nobody",
+		"password": "password123",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	var body struct {
+		Errors string `json:"errors"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "invalid credentials", body.Errors)
+}
+
+func newRefreshRequest(refreshToken string) *http.Request {
+	requestBody, _ := json.Marshal(map[string]string{
+		"refreshToken": refreshToken,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestRefresh_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Refresh(gomock.Any(), &application.RefreshRequest{
+		RefreshToken: "old",
+	}).Return(&application.RefreshResponse{
+		Token:        "abc",
+		RefreshToken: "new",
+		ExpiresIn:    15 * time.Minute,
+	}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/auth/refresh", api.Refresh)
+	resp, _ := app.Test(newRefreshRequest("old"))
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"token":"abc","refreshToken":"new","expiresIn":900}`, string(body))
+}
+
+func TestRefresh_Reused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	mockApp.EXPECT().Refresh(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("%w: %w", application.ErrInvalidRefreshToken, application.ErrRefreshTokenReused))
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/auth/refresh", api.Refresh)
+	resp, _ := app.Test(newRefreshRequest("old"))
+
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	var body struct {
+		Errors string `json:"errors"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "invalid refresh token", body.Errors)
+}
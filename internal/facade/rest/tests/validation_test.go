@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/azaliaz/avito-shop/internal/application/mocks"
+	"github.com/azaliaz/avito-shop/internal/facade/rest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newSendCoinRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	return req
+}
+
+func TestSendCoin_NumericAmount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SendCoin(gomock.Any(), &application.SendCoinRequest{
+		Principal: principal,
+		Amount:    100,
+		ToUser:    "username1",
+	}).Return(&application.SendCoinResponse{}, nil)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
+	resp, _ := app.Test(newSendCoinRequest(`{"amount":100,"toUser":"username1"}`))
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestSendCoin_FractionalAmount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
+	resp, _ := app.Test(newSendCoinRequest(`{"amount":1.5,"toUser":"username1"}`))
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestSendCoin_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+	mockApp.EXPECT().SendCoin(gomock.Any(), gomock.Any()).Return(nil, &application.ValidationError{
+		Fields: []application.FieldError{
+			{Field: "amount", Rule: application.RuleMin, Message: "must be positive"},
+			{Field: "toUser", Rule: application.RuleNotSelf, Message: "must not be the sender", Err: application.ErrSelfTransfer},
+		},
+	})
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/sendCoin", api.RequireAuth, api.SendCoin)
+	resp, _ := app.Test(newSendCoinRequest(`{"amount":-1,"toUser":"user1"}`))
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{
+		"errors": "invalid request: amount must be positive; toUser must not be the sender",
+		"fields": [
+			{"field":"amount","rule":"min","message":"must be positive"},
+			{"field":"toUser","rule":"not_self","message":"must not be the sender"}
+		]
+	}`, string(body))
+}
+
+func TestCreditUser_WrongType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApp := mocks.NewMockShopService(ctrl)
+	expectAuthenticated(mockApp)
+
+	api := rest.NewAPI(nil, nil, mockApp)
+	app := fiber.New()
+	app.Add("POST", "/api/admin/users/:username/credit", api.RequireAuth, api.CreditUser)
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/user2/credit", bytes.NewReader([]byte(`{"amount":"lots","reason":"bonus"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"errors":"invalid request: amount must be an integer",
+		"fields":[{"field":"amount","rule":"type","message":"must be an integer"}]}`, string(body))
+}
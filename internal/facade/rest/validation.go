@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/azaliaz/avito-shop/internal/application"
+	"github.com/gofiber/fiber/v2"
+	"reflect"
+)
+
+// bodyError responds to a request body that could not be decoded. A value of
+// the wrong type is reported like any other broken rule, anything else is not
+// JSON at all.
+func (api *Service) bodyError(ctx *fiber.Ctx, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return api.badRequest(ctx, "invalid json")
+	}
+	message := "has the wrong type"
+	switch typeErr.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		message = "must be an integer"
+	case reflect.String:
+		message = "must be a string"
+	case reflect.Bool:
+		message = "must be a boolean"
+	}
+	return api.invalidParam(ctx, typeErr.Field, application.RuleType, message)
+}
+
+// invalidParam reports a single request parameter that breaks a rule before
+// it reaches the application, usually because it cannot be parsed.
+func (api *Service) invalidParam(ctx *fiber.Ctx, field, rule, message string) error {
+	return api.sendError(ctx, &application.ValidationError{Fields: []application.FieldError{
+		{Field: field, Rule: rule, Message: message},
+	}})
+}
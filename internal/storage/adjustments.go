@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+)
+
+// AdjustBalance credits or debits the user's balance on behalf of an admin.
+// The adjustment is recorded as a transfer from or to the system user with the
+// reason as its memo, so it shows up in the history like any other transfer.
+func (r *Service) AdjustBalance(ctx context.Context, request *AdjustBalanceRequest) (*AdjustBalanceResponse, error) {
+	if request.Amount == 0 {
+		return nil, errors.New("amount must not be zero")
+	}
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	if request.IdempotencyKey != "" {
+		stored, err := r.claimIdempotencyKey(ctx, tx, request.AdminId, request.IdempotencyKey, request.IdempotencyKeyTTL,
+			"adjust_balance", fmt.Sprintf("%s:%d:%t:%s", request.UserName, request.Amount, request.Force, request.Reason))
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil {
+			var response AdjustBalanceResponse
+			if err := json.Unmarshal(stored, &response); err != nil {
+				return nil, fmt.Errorf("error unmarshal idempotent response: %w", err)
+			}
+			response.Replayed = true
+			return &response, nil
+		}
+	}
+
+	var userId, systemUserId uint64
+	err = tx.QueryRow(ctx,
+		`SELECT id
+				FROM users
+				WHERE username = @username AND NOT is_system`,
+		pgx.NamedArgs{
+			"username": request.UserName,
+		},
+	).Scan(&userId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error get user: %w", err)
+	}
+	// The system user is the counterparty of adjustments in the transfer
+	// history, it is created by the migrations.
+	err = tx.QueryRow(ctx,
+		`SELECT id FROM users WHERE is_system`,
+	).Scan(&systemUserId)
+	if err != nil {
+		return nil, fmt.Errorf("error get system user: %w", err)
+	}
+
+	from, to := systemUserId, userId
+	debit, credit := userAccount(userId), issuanceAccount
+	amount := request.Amount
+	if amount < 0 {
+		from, to = to, from
+		debit, credit = credit, debit
+		amount = -amount
+	}
+	var transactionId uint64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO transactions (from_user_id, to_user_id, amount, memo)
+				VALUES (@from_user_id, @to_user_id, @amount, @memo)
+				RETURNING id`,
+		pgx.NamedArgs{
+			"from_user_id": from,
+			"to_user_id":   to,
+			"amount":       amount,
+			"memo":         request.Reason,
+		},
+	).Scan(&transactionId)
+	if err != nil {
+		return nil, fmt.Errorf("error insert transaction: %w", err)
+	}
+	err = r.postEntry(ctx, tx, &ledgerEntry{
+		Kind:      entryAdjustment,
+		Debit:     debit,
+		Credit:    credit,
+		Amount:    int64(amount),
+		Reference: fmt.Sprintf("transaction:%d", transactionId),
+		Overdraft: request.Force,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		ActorId: request.AdminId,
+		Action:  AuditAdjustBalance,
+		Target:  request.UserName,
+		Details: map[string]any{
+			"amount":        request.Amount,
+			"reason":        request.Reason,
+			"force":         request.Force,
+			"transactionId": transactionId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AdjustBalanceResponse{}
+	err = tx.QueryRow(ctx,
+		`SELECT balance FROM users WHERE id = @user_id`,
+		pgx.NamedArgs{
+			"user_id": userId,
+		},
+	).Scan(&response.Balance)
+	if err != nil {
+		return nil, fmt.Errorf("error get balance: %w", err)
+	}
+	if request.IdempotencyKey != "" {
+		err = r.saveIdempotentResponse(ctx, tx, request.AdminId, request.IdempotencyKey, response)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, tx.Commit(ctx)
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Outcomes of an audited operation.
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// Audited operations.
+const (
+	AuditRegister        = "register"
+	AuditLogin           = "login"
+	AuditRefresh         = "refresh"
+	AuditLogout          = "logout"
+	AuditChangePassword  = "change_password"
+	AuditRevokeSessions  = "revoke_sessions"
+	AuditSendCoin        = "send_coin"
+	AuditBuyItem         = "buy_item"
+	AuditCheckout        = "checkout"
+	AuditReturnOrder     = "return_order"
+	AuditCreateItem      = "create_item"
+	AuditUpdateItemPrice = "update_item_price"
+	AuditRenameItem      = "rename_item"
+	AuditRetireItem      = "retire_item"
+	AuditRestockItem     = "restock_item"
+	AuditSetUserRole     = "set_user_role"
+	AuditAdjustBalance   = "adjust_balance"
+)
+
+// Actor is who a call is made on behalf of. It travels in the context, so
+// that every mutation can record it without each request carrying it.
+type Actor struct {
+	// UserId is zero for anonymous requests such as a login.
+	UserId uint64
+	IP     string
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// audit records an operation inside tx, so the event is stored if and only if
+// the change itself is committed.
+func (r *Service) audit(ctx context.Context, tx pgx.Tx, event *AuditEvent) error {
+	if err := insertAuditEvent(ctx, tx, event); err != nil {
+		return fmt.Errorf("error insert audit event: %w", err)
+	}
+	return nil
+}
+
+// RecordAuditEvent stores an event on its own. It is meant for failed
+// operations, whose changes have been rolled back together with the
+// transaction they would have been recorded in.
+func (r *Service) RecordAuditEvent(ctx context.Context, event *AuditEvent) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	if err := insertAuditEvent(ctx, conn, event); err != nil {
+		return fmt.Errorf("error insert audit event: %w", err)
+	}
+	return nil
+}
+
+// execer runs a statement on either a connection or a transaction.
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func insertAuditEvent(ctx context.Context, db execer, event *AuditEvent) error {
+	actor := ActorFromContext(ctx)
+	if event.ActorId != 0 {
+		actor.UserId = event.ActorId
+	}
+	outcome := event.Outcome
+	if outcome == "" {
+		outcome = AuditSuccess
+	}
+	_, err := db.Exec(ctx,
+		`INSERT INTO audit_events(actor_id, action, target, ip, outcome, details)
+				VALUES (NULLIF(@actor_id, 0), @action, NULLIF(@target, ''), NULLIF(@ip, ''), @outcome, @details)`,
+		pgx.NamedArgs{
+			"actor_id": actor.UserId,
+			"action":   event.Action,
+			"target":   event.Target,
+			"ip":       actor.IP,
+			"outcome":  outcome,
+			"details":  event.Details,
+		},
+	)
+	return err
+}
+
+func (r *Service) GetAuditEvents(ctx context.Context, request *GetAuditEventsRequest) ([]*AuditEvent, error) {
+	if request.Limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	args := pgx.NamedArgs{
+		"actor":       request.Actor,
+		"action":      request.Action,
+		"outcome":     request.Outcome,
+		"ip":          request.IP,
+		"from":        request.From,
+		"to":          request.To,
+		"limit":       request.Limit,
+		"cursor_time": nil,
+		"cursor_id":   nil,
+	}
+	if request.After != nil {
+		args["cursor_time"] = request.After.CreatedAt
+		args["cursor_id"] = request.After.Id
+	}
+	rows, err := conn.Query(ctx,
+		`SELECT audit_events.id, COALESCE(audit_events.actor_id, 0), COALESCE(users.username, ''),
+					audit_events.action, COALESCE(audit_events.target, ''), COALESCE(audit_events.ip, ''),
+					audit_events.outcome, audit_events.details, audit_events.created_at
+				FROM audit_events
+				LEFT JOIN users ON audit_events.actor_id = users.id
+				WHERE (@actor = '' OR users.username = @actor)
+					AND (@action = '' OR audit_events.action = @action)
+					AND (@outcome = '' OR audit_events.outcome = @outcome)
+					AND (@ip = '' OR audit_events.ip = @ip)
+					AND (@from::TIMESTAMP IS NULL OR audit_events.created_at >= @from)
+					AND (@to::TIMESTAMP IS NULL OR audit_events.created_at < @to)
+					AND (@cursor_time::TIMESTAMP IS NULL
+						OR (audit_events.created_at, audit_events.id) < (@cursor_time, @cursor_id))
+				ORDER BY audit_events.created_at DESC, audit_events.id DESC
+				LIMIT @limit`,
+		args,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*AuditEvent, 0, request.Limit)
+	for rows.Next() {
+		var event AuditEvent
+		err := rows.Scan(&event.Id, &event.ActorId, &event.ActorName, &event.Action, &event.Target, &event.IP,
+			&event.Outcome, &event.Details, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
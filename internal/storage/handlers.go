@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
@@ -27,30 +28,52 @@ func (r *Service) Auth(ctx context.Context, request *AuthRequest) (*AuthResponse
 			r.logger.Error("rollback error", slog.String("err", err.Error()))
 		}
 	}()
-	_, err = tx.Exec(ctx,
+	var newUserId uint64
+	err = tx.QueryRow(ctx,
 		`INSERT INTO users(username, password_hash, balance)
-					SELECT @username, @password_hash, 1000
-					WHERE NOT EXISTS(SELECT 1 FROM users WHERE username = @username)`,
+					SELECT @username, @password_hash, 0
+					WHERE NOT EXISTS(SELECT 1 FROM users WHERE username = @username)
+					RETURNING id`,
 		pgx.NamedArgs{
 			"username":      request.UserName,
 			"password_hash": request.PassHash,
 		},
-	)
-	if err != nil {
+	).Scan(&newUserId)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		return nil, errors.New("error creating user")
 	}
+	if err == nil {
+		err = r.postEntry(ctx, tx, &ledgerEntry{
+			Kind:   entryGrant,
+			Debit:  userAccount(newUserId),
+			Credit: issuanceAccount,
+			Amount: initialGrant,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error grant initial coins: %w", err)
+		}
+		err = r.audit(ctx, tx, &AuditEvent{
+			ActorId: newUserId,
+			Action:  AuditRegister,
+			Target:  request.UserName,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	var userId uint64
 	var userName string
 	var passwordHash string
+	var role string
 	err = tx.QueryRow(ctx,
-		`SELECT id, username, password_hash
+		`SELECT id, username, password_hash, role
 				FROM users
 				WHERE username = @username`,
 		pgx.NamedArgs{
 			"username": request.UserName,
 		},
-	).Scan(&userId, &userName, &passwordHash)
+	).Scan(&userId, &userName, &passwordHash, &role)
 	if err != nil {
 		return nil, errors.New("error get user")
 	}
@@ -59,9 +82,34 @@ func (r *Service) Auth(ctx context.Context, request *AuthRequest) (*AuthResponse
 		UserId:   userId,
 		UserName: userName,
 		PassHash: passwordHash,
+		Role:     role,
 	}, tx.Commit(ctx)
 }
 
+func (r *Service) GetUser(ctx context.Context, userId uint64) (*User, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	var user User
+	err = conn.QueryRow(ctx,
+		`SELECT id, username, role
+				FROM users
+				WHERE id = @user_id`,
+		pgx.NamedArgs{
+			"user_id": userId,
+		},
+	).Scan(&user.UserId, &user.UserName, &user.Role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *Service) GetInventory(ctx context.Context, userId uint64) ([]*ProductStock, error) {
 	conn, err := r.Pool().Acquire(ctx)
 	if err != nil {
@@ -128,6 +176,7 @@ func (r *Service) GetCoinHistory(ctx context.Context, userId uint64) (*CoinHisto
     			users1.username as from_user, 
        			users2.username as to_user, 
        			transactions.amount, 
+       			COALESCE(transactions.memo, ''),
        			transactions.created_at
 			FROM transactions
 			LEFT JOIN users as users1 ON transactions.from_user_id = users1.id
@@ -145,7 +194,7 @@ func (r *Service) GetCoinHistory(ctx context.Context, userId uint64) (*CoinHisto
 	var sent []*Transaction
 	for rows.Next() {
 		var transaction Transaction
-		err := rows.Scan(&transaction.FromUser, &transaction.ToUser, &transaction.Amount, &transaction.CreatedAt)
+		err := rows.Scan(&transaction.FromUser, &transaction.ToUser, &transaction.Amount, &transaction.Memo, &transaction.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -160,6 +209,7 @@ func (r *Service) GetCoinHistory(ctx context.Context, userId uint64) (*CoinHisto
     			users1.username as from_user, 
        			users2.username as to_user, 
        			transactions.amount, 
+       			COALESCE(transactions.memo, ''),
        			transactions.created_at
 			FROM transactions
 			LEFT JOIN users as users1 ON transactions.from_user_id = users1.id
@@ -175,7 +225,7 @@ func (r *Service) GetCoinHistory(ctx context.Context, userId uint64) (*CoinHisto
 	var received []*Transaction
 	for rows.Next() {
 		var transaction Transaction
-		err := rows.Scan(&transaction.FromUser, &transaction.ToUser, &transaction.Amount, &transaction.CreatedAt)
+		err := rows.Scan(&transaction.FromUser, &transaction.ToUser, &transaction.Amount, &transaction.Memo, &transaction.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -207,66 +257,151 @@ func (r *Service) SendCoin(ctx context.Context, request *SendCoinRequest) (*Send
 			r.logger.Error("rollback error", slog.String("err", err.Error()))
 		}
 	}()
+	if request.IdempotencyKey != "" {
+		stored, err := r.claimIdempotencyKey(ctx, tx, request.UserId, request.IdempotencyKey, request.IdempotencyKeyTTL,
+			"send_coin", fmt.Sprintf("%s:%d:%s", request.ToUser, request.Amount, request.Memo))
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil {
+			var response SendCoinResponse
+			if err := json.Unmarshal(stored, &response); err != nil {
+				return nil, fmt.Errorf("error unmarshal idempotent response: %w", err)
+			}
+			response.Replayed = true
+			return &response, nil
+		}
+	}
 
 	var targetUserId uint64
 	err = tx.QueryRow(ctx,
 		`SELECT id
 					FROM users
-					WHERE username = @username`,
+					WHERE username = @username AND NOT is_system`,
 		&pgx.NamedArgs{
 			"username": request.ToUser,
 		},
 	).Scan(&targetUserId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRecipientNotFound
+	}
 	if err != nil {
-		return nil, errors.New("target user not found")
+		return nil, err
+	}
+	if targetUserId == request.UserId {
+		return nil, ErrSelfTransfer
 	}
 
-	res, err := tx.Exec(ctx,
-		`UPDATE users
-				SET balance = balance - @amount
-				WHERE id = @user_id`,
+	var transactionId uint64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO transactions (from_user_id, to_user_id, amount, memo)
+					VALUES (@from_user_id, @to_user_id, @amount, NULLIF(@memo, ''))
+					RETURNING id`,
 		pgx.NamedArgs{
-			"amount":  request.Amount,
-			"user_id": request.UserId,
+			"from_user_id": request.UserId,
+			"to_user_id":   targetUserId,
+			"amount":       request.Amount,
+			"memo":         request.Memo,
 		},
-	)
+	).Scan(&transactionId)
 	if err != nil {
 		return nil, err
 	}
-	if res.RowsAffected() == 0 {
-		return nil, errors.New("current user not found")
+	err = r.postEntry(ctx, tx, &ledgerEntry{
+		Kind:      entryTransfer,
+		Debit:     userAccount(targetUserId),
+		Credit:    userAccount(request.UserId),
+		Amount:    int64(request.Amount),
+		Reference: fmt.Sprintf("transaction:%d", transactionId),
+	})
+	if err != nil {
+		return nil, err
 	}
-	res, err = tx.Exec(ctx,
-		`UPDATE users
-					SET balance = balance + @amount
-					WHERE id = @user_id`,
-		pgx.NamedArgs{
-			"amount":  request.Amount,
-			"user_id": targetUserId,
-		},
-	)
+	err = r.audit(ctx, tx, &AuditEvent{
+		ActorId: request.UserId,
+		Action:  AuditSendCoin,
+		Target:  request.ToUser,
+		Details: map[string]any{"amount": request.Amount, "transactionId": transactionId},
+	})
 	if err != nil {
 		return nil, err
 	}
-	if res.RowsAffected() == 0 {
-		return nil, errors.New("target user not found")
+	response := &SendCoinResponse{}
+	if request.IdempotencyKey != "" {
+		err = r.saveIdempotentResponse(ctx, tx, request.UserId, request.IdempotencyKey, response)
+		if err != nil {
+			return nil, err
+		}
 	}
-	_, err = tx.Exec(ctx,
-		`INSERT INTO transactions (from_user_id, to_user_id, amount)
-					VALUES (@from_user_id, @to_user_id, @amount)`,
-		pgx.NamedArgs{
-			"from_user_id": request.UserId,
-			"to_user_id":   targetUserId,
-			"amount":       request.Amount,
-		},
-	)
+	return response, tx.Commit(ctx)
+}
+
+func (r *Service) BuyItem(ctx context.Context, request *BuyItemRequest) (*BuyItemResponse, error) {
+	if request.Quantity <= 0 {
+		return nil, errors.New("quantity must be positive")
+	}
+	conn, err := r.Pool().Acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return nil, tx.Commit(ctx)
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	if request.IdempotencyKey != "" {
+		stored, err := r.claimIdempotencyKey(ctx, tx, request.UserId, request.IdempotencyKey, request.IdempotencyKeyTTL,
+			"buy_item", fmt.Sprintf("%s:%d", request.Item, request.Quantity))
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil {
+			var response BuyItemResponse
+			if err := json.Unmarshal(stored, &response); err != nil {
+				return nil, fmt.Errorf("error unmarshal idempotent response: %w", err)
+			}
+			response.Replayed = true
+			return &response, nil
+		}
+	}
+	amount, err := r.buyItem(ctx, tx, request.UserId, request.Item, request.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		ActorId: request.UserId,
+		Action:  AuditBuyItem,
+		Target:  request.Item,
+		Details: map[string]any{"quantity": request.Quantity, "amount": amount},
+	})
+	if err != nil {
+		return nil, err
+	}
+	response := &BuyItemResponse{}
+	if request.IdempotencyKey != "" {
+		err = r.saveIdempotentResponse(ctx, tx, request.UserId, request.IdempotencyKey, response)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, tx.Commit(ctx)
 }
 
-func (r *Service) BuyItem(ctx context.Context, request *BuyItemRequest) (*BuyItemResponse, error) {
+func (r *Service) Checkout(ctx context.Context, request *CheckoutRequest) (*CheckoutResponse, error) {
+	if len(request.Items) == 0 {
+		return nil, errors.New("cart is empty")
+	}
+	for _, cartItem := range request.Items {
+		if cartItem.Quantity <= 0 {
+			return nil, errors.New("quantity must be positive")
+		}
+	}
 	conn, err := r.Pool().Acquire(ctx)
 	if err != nil {
 		return nil, err
@@ -282,50 +417,108 @@ func (r *Service) BuyItem(ctx context.Context, request *BuyItemRequest) (*BuyIte
 			r.logger.Error("rollback error", slog.String("err", err.Error()))
 		}
 	}()
+	var total int64
+	for _, cartItem := range request.Items {
+		amount, err := r.buyItem(ctx, tx, request.UserId, cartItem.Item, cartItem.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		total += amount
+	}
+	items := make(map[string]any, len(request.Items))
+	for _, cartItem := range request.Items {
+		items[cartItem.Item] = cartItem.Quantity
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		ActorId: request.UserId,
+		Action:  AuditCheckout,
+		Details: map[string]any{"items": items, "total": total},
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &CheckoutResponse{
+		Total: int(total),
+	}, nil
+}
+
+// buyItem charges the user for quantity units of the item and puts them into
+// the inventory inside tx. It returns the amount debited from the balance.
+func (r *Service) buyItem(ctx context.Context, tx pgx.Tx, userId uint64, item string, quantity int) (int64, error) {
 	var price int
-	err = tx.QueryRow(ctx,
-		`SELECT price
+	var stock *int
+	err := tx.QueryRow(ctx,
+		`SELECT price, stock
 				FROM items
-				WHERE name = @item`,
+				WHERE name = @item AND retired_at IS NULL`,
 		pgx.NamedArgs{
-			"item": request.Item,
+			"item": item,
 		},
-	).Scan(&price)
-	if err != nil {
-		return nil, fmt.Errorf("error get item price from db: %w", err)
+	).Scan(&price, &stock)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrItemNotFound
 	}
-	_, err = tx.Exec(ctx,
-		`UPDATE users SET balance = balance - @amount WHERE id = @user_id`,
-		pgx.NamedArgs{
-			"amount":  price,
-			"user_id": request.UserId,
-		},
-	)
 	if err != nil {
-		return nil, fmt.Errorf("error update user balance: %w", err)
+		return 0, fmt.Errorf("error get item price from db: %w", err)
+	}
+	if stock != nil {
+		res, err := tx.Exec(ctx,
+			`UPDATE items SET stock = stock - @quantity
+					WHERE name = @item AND stock >= @quantity`,
+			pgx.NamedArgs{
+				"item":     item,
+				"quantity": quantity,
+			},
+		)
+		if err != nil {
+			return 0, fmt.Errorf("error update item stock: %w", err)
+		}
+		if res.RowsAffected() == 0 {
+			return 0, ErrItemSoldOut
+		}
 	}
 	_, err = tx.Exec(ctx,
-		`UPDATE inventory SET quantity = quantity + 1
-					WHERE user_id = @user_id AND item = @item`,
+		`INSERT INTO inventory(user_id, item, quantity)
+				VALUES (@user_id, @item, @quantity)
+				ON CONFLICT (user_id, item) DO UPDATE SET quantity = inventory.quantity + EXCLUDED.quantity`,
 		pgx.NamedArgs{
-			"user_id": request.UserId,
-			"item":    request.Item,
+			"user_id":  userId,
+			"item":     item,
+			"quantity": quantity,
 		},
 	)
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("error update user inventory: %w", err)
+	if err != nil {
+		return 0, fmt.Errorf("error update user inventory: %w", err)
 	}
-	_, err = tx.Exec(ctx,
-		`INSERT INTO inventory(user_id, item, quantity)
-				SELECT @user_id, @item, 1
-				WHERE NOT EXISTS (SELECT 1 FROM inventory WHERE user_id = @user_id AND item = @item)`,
+	var orderId uint64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO orders(user_id, item, price, quantity)
+				VALUES (@user_id, @item, @price, @quantity)
+				RETURNING id`,
 		pgx.NamedArgs{
-			"user_id": request.UserId,
-			"item":    request.Item,
+			"user_id":  userId,
+			"item":     item,
+			"price":    price,
+			"quantity": quantity,
 		},
-	)
+	).Scan(&orderId)
+	if err != nil {
+		return 0, fmt.Errorf("error insert order: %w", err)
+	}
+	amount := int64(price) * int64(quantity)
+	err = r.postEntry(ctx, tx, &ledgerEntry{
+		Kind:      entryPurchase,
+		Debit:     shopAccount,
+		Credit:    userAccount(userId),
+		Amount:    amount,
+		Reference: fmt.Sprintf("order:%d", orderId),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error create user inventory: %w", err)
+		return 0, err
 	}
-	return nil, tx.Commit(ctx)
+	return amount, nil
 }
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+)
+
+// Directions of a transfer relative to the user whose history is read.
+const (
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+)
+
+// GetHistory reads one page of the user's transfers, newest first. Sent and
+// received transfers are selected separately so that each side uses its own
+// (user_id, created_at) index.
+func (r *Service) GetHistory(ctx context.Context, request *GetHistoryRequest) ([]*HistoryEntry, error) {
+	if request.Limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	args := pgx.NamedArgs{
+		"user_id":      request.UserId,
+		"sent":         request.Direction != DirectionReceived,
+		"received":     request.Direction != DirectionSent,
+		"counterparty": request.Counterparty,
+		"from":         request.From,
+		"to":           request.To,
+		"limit":        request.Limit,
+		"cursor_time":  nil,
+		"cursor_id":    nil,
+	}
+	if request.After != nil {
+		args["cursor_time"] = request.After.CreatedAt
+		args["cursor_id"] = request.After.Id
+	}
+	rows, err := conn.Query(ctx,
+		`SELECT id, direction, from_user, to_user, amount, memo, created_at
+				FROM (
+					(SELECT transactions.id, 'sent' AS direction,
+							COALESCE(users1.username, '') AS from_user,
+							COALESCE(users2.username, '') AS to_user,
+							transactions.amount, COALESCE(transactions.memo, '') AS memo,
+							transactions.created_at
+						FROM transactions
+						LEFT JOIN users AS users1 ON transactions.from_user_id = users1.id
+						LEFT JOIN users AS users2 ON transactions.to_user_id = users2.id
+						WHERE @sent::BOOLEAN AND transactions.from_user_id = @user_id
+							AND (@counterparty = '' OR users2.username = @counterparty)
+							AND (@from::TIMESTAMP IS NULL OR transactions.created_at >= @from)
+							AND (@to::TIMESTAMP IS NULL OR transactions.created_at < @to)
+							AND (@cursor_time::TIMESTAMP IS NULL
+								OR (transactions.created_at, transactions.id) < (@cursor_time, @cursor_id))
+						ORDER BY transactions.created_at DESC, transactions.id DESC
+						LIMIT @limit)
+					UNION ALL
+					(SELECT transactions.id, 'received' AS direction,
+							COALESCE(users1.username, '') AS from_user,
+							COALESCE(users2.username, '') AS to_user,
+							transactions.amount, COALESCE(transactions.memo, '') AS memo,
+							transactions.created_at
+						FROM transactions
+						LEFT JOIN users AS users1 ON transactions.from_user_id = users1.id
+						LEFT JOIN users AS users2 ON transactions.to_user_id = users2.id
+						WHERE @received::BOOLEAN AND transactions.to_user_id = @user_id
+							AND (@counterparty = '' OR users1.username = @counterparty)
+							AND (@from::TIMESTAMP IS NULL OR transactions.created_at >= @from)
+							AND (@to::TIMESTAMP IS NULL OR transactions.created_at < @to)
+							AND (@cursor_time::TIMESTAMP IS NULL
+								OR (transactions.created_at, transactions.id) < (@cursor_time, @cursor_id))
+						ORDER BY transactions.created_at DESC, transactions.id DESC
+						LIMIT @limit)
+				) AS history
+				ORDER BY created_at DESC, id DESC
+				LIMIT @limit`,
+		args,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]*HistoryEntry, 0, request.Limit)
+	for rows.Next() {
+		var entry HistoryEntry
+		err := rows.Scan(&entry.Id, &entry.Direction, &entry.FromUser, &entry.ToUser, &entry.Amount, &entry.Memo, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
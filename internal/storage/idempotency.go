@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// claimIdempotencyKey reserves key for the user inside tx for ttl. If the key
+// already belongs to a committed request that has not expired, the stored
+// response of that request is returned and the caller must replay it instead
+// of running the operation again. An expired key is claimed as a new one. A
+// concurrent request with the same key blocks on the insert until the first
+// one commits or rolls back.
+func (r *Service) claimIdempotencyKey(ctx context.Context, tx pgx.Tx, userId uint64, key string, ttl time.Duration, operation, fingerprint string) ([]byte, error) {
+	res, err := tx.Exec(ctx,
+		`INSERT INTO idempotency_keys(user_id, key, operation, fingerprint, expires_at)
+				VALUES (@user_id, @key, @operation, @fingerprint, CURRENT_TIMESTAMP + @ttl * INTERVAL '1 second')
+				ON CONFLICT (user_id, key) DO UPDATE
+				SET operation = EXCLUDED.operation,
+					fingerprint = EXCLUDED.fingerprint,
+					response = NULL,
+					created_at = CURRENT_TIMESTAMP,
+					expires_at = EXCLUDED.expires_at
+				WHERE idempotency_keys.expires_at <= CURRENT_TIMESTAMP`,
+		pgx.NamedArgs{
+			"user_id":     userId,
+			"key":         key,
+			"operation":   operation,
+			"fingerprint": fingerprint,
+			"ttl":         ttl.Seconds(),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error insert idempotency key: %w", err)
+	}
+	if res.RowsAffected() == 1 {
+		return nil, nil
+	}
+
+	var storedOperation, storedFingerprint string
+	var response []byte
+	err = tx.QueryRow(ctx,
+		`SELECT operation, fingerprint, response
+				FROM idempotency_keys
+				WHERE user_id = @user_id AND key = @key`,
+		pgx.NamedArgs{
+			"user_id": userId,
+			"key":     key,
+		},
+	).Scan(&storedOperation, &storedFingerprint, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error get idempotency key: %w", err)
+	}
+	if storedOperation != operation || storedFingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	return response, nil
+}
+
+// saveIdempotentResponse stores the outcome of the request that claimed key,
+// it must be called in the same transaction as claimIdempotencyKey.
+func (r *Service) saveIdempotentResponse(ctx context.Context, tx pgx.Tx, userId uint64, key string, response any) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("error marshal idempotent response: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE idempotency_keys SET response = @response
+				WHERE user_id = @user_id AND key = @key`,
+		pgx.NamedArgs{
+			"user_id":  userId,
+			"key":      key,
+			"response": data,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error save idempotent response: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys drops the keys whose requests can no longer be
+// replayed.
+func (r *Service) DeleteExpiredIdempotencyKeys(ctx context.Context) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= CURRENT_TIMESTAMP`)
+	if err != nil {
+		return fmt.Errorf("error delete expired idempotency keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,373 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"log/slog"
+)
+
+const uniqueViolationCode = "23505"
+
+func (r *Service) GetItems(ctx context.Context) ([]*Item, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	rows, err := conn.Query(ctx,
+		`SELECT name, price, stock
+			FROM items
+			WHERE retired_at IS NULL
+			ORDER BY name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*Item
+	for rows.Next() {
+		var item Item
+		err := rows.Scan(&item.Name, &item.Price, &item.Stock)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error fetch items: %w", err)
+	}
+	return items, nil
+}
+
+func (r *Service) GetItem(ctx context.Context, name string) (*Item, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	var item Item
+	err = conn.QueryRow(ctx,
+		`SELECT name, price, stock
+				FROM items
+				WHERE name = @name AND retired_at IS NULL`,
+		pgx.NamedArgs{
+			"name": name,
+		},
+	).Scan(&item.Name, &item.Price, &item.Stock)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *Service) CreateItem(ctx context.Context, request *CreateItemRequest) (*CreateItemResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	_, err = tx.Exec(ctx,
+		`INSERT INTO items(name, price, stock)
+				VALUES (@name, @price, @stock)`,
+		pgx.NamedArgs{
+			"name":  request.Name,
+			"price": request.Price,
+			"stock": request.Stock,
+		},
+	)
+	if isUniqueViolation(err) {
+		return nil, ErrItemExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error create item: %w", err)
+	}
+	err = r.insertItemPrice(ctx, tx, request.Name, request.Price)
+	if err != nil {
+		return nil, err
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		Action:  AuditCreateItem,
+		Target:  request.Name,
+		Details: map[string]any{"price": request.Price, "stock": request.Stock},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nil, tx.Commit(ctx)
+}
+
+func (r *Service) UpdateItemPrice(ctx context.Context, request *UpdateItemPriceRequest) (*UpdateItemPriceResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	res, err := tx.Exec(ctx,
+		`UPDATE items
+				SET price = @price
+				WHERE name = @name AND retired_at IS NULL`,
+		pgx.NamedArgs{
+			"name":  request.Name,
+			"price": request.Price,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error update item price: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, ErrItemNotFound
+	}
+	err = r.insertItemPrice(ctx, tx, request.Name, request.Price)
+	if err != nil {
+		return nil, err
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		Action:  AuditUpdateItemPrice,
+		Target:  request.Name,
+		Details: map[string]any{"price": request.Price},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nil, tx.Commit(ctx)
+}
+
+func (r *Service) RenameItem(ctx context.Context, request *RenameItemRequest) (*RenameItemResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	res, err := tx.Exec(ctx,
+		`UPDATE items
+				SET name = @new_name
+				WHERE name = @name AND retired_at IS NULL`,
+		pgx.NamedArgs{
+			"name":     request.Name,
+			"new_name": request.NewName,
+		},
+	)
+	if isUniqueViolation(err) {
+		return nil, ErrItemExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error rename item: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, ErrItemNotFound
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE inventory
+				SET item = @new_name
+				WHERE item = @name`,
+		pgx.NamedArgs{
+			"name":     request.Name,
+			"new_name": request.NewName,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error rename item in inventory: %w", err)
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		Action:  AuditRenameItem,
+		Target:  request.Name,
+		Details: map[string]any{"newName": request.NewName},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nil, tx.Commit(ctx)
+}
+
+func (r *Service) RetireItem(ctx context.Context, name string) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	res, err := tx.Exec(ctx,
+		`UPDATE items
+				SET retired_at = CURRENT_TIMESTAMP
+				WHERE name = @name AND retired_at IS NULL`,
+		pgx.NamedArgs{
+			"name": name,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error retire item: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrItemNotFound
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		Action: AuditRetireItem,
+		Target: name,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *Service) GetItemPrices(ctx context.Context, name string) ([]*ItemPrice, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	rows, err := conn.Query(ctx,
+		`SELECT price, valid_from
+			FROM item_prices
+			WHERE item = @name
+			ORDER BY valid_from, id`,
+		pgx.NamedArgs{
+			"name": name,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []*ItemPrice
+	for rows.Next() {
+		var price ItemPrice
+		err := rows.Scan(&price.Price, &price.ValidFrom)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, &price)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error fetch item prices: %w", err)
+	}
+	if len(prices) == 0 {
+		return nil, ErrItemNotFound
+	}
+	return prices, nil
+}
+
+func (r *Service) RestockItem(ctx context.Context, request *RestockItemRequest) (*RestockItemResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	var stock int
+	err = tx.QueryRow(ctx,
+		`UPDATE items
+				SET stock = stock + @amount
+				WHERE name = @name AND retired_at IS NULL AND stock IS NOT NULL
+				RETURNING stock`,
+		pgx.NamedArgs{
+			"name":   request.Name,
+			"amount": request.Amount,
+		},
+	).Scan(&stock)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Restocking an item with unlimited supply would silently limit it.
+		var exists bool
+		err = conn.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM items WHERE name = @name AND retired_at IS NULL)`,
+			pgx.NamedArgs{"name": request.Name},
+		).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("error check item: %w", err)
+		}
+		if exists {
+			return nil, ErrItemUnlimited
+		}
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error restock item: %w", err)
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		Action:  AuditRestockItem,
+		Target:  request.Name,
+		Details: map[string]any{"amount": request.Amount, "stock": stock},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RestockItemResponse{
+		Stock: stock,
+	}, tx.Commit(ctx)
+}
+
+func (r *Service) insertItemPrice(ctx context.Context, tx pgx.Tx, name string, price int) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO item_prices(item, price)
+				VALUES (@name, @price)`,
+		pgx.NamedArgs{
+			"name":  name,
+			"price": price,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error save item price: %w", err)
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
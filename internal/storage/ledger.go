@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+// Kinds of coin movements recorded in ledger_entries.
+const (
+	entryGrant      = "grant"
+	entryTransfer   = "transfer"
+	entryPurchase   = "purchase"
+	entryRefund     = "refund"
+	entryAdjustment = "adjustment"
+)
+
+// initialGrant is the number of coins credited to every new user.
+const initialGrant = 1000
+
+// ledgerAccount is one side of a ledger entry. User accounts mirror their
+// balance into users.balance, system accounts only exist in the ledger.
+type ledgerAccount struct {
+	// isUser tells a user account from a system one, any userId is a valid
+	// user, the system user has 0.
+	isUser bool
+	userId uint64
+	code   string
+}
+
+func userAccount(userId uint64) ledgerAccount {
+	return ledgerAccount{
+		isUser: true,
+		userId: userId,
+		code:   fmt.Sprintf("user:%d", userId),
+	}
+}
+
+var (
+	// issuanceAccount is where granted coins come from.
+	issuanceAccount = ledgerAccount{code: "system:issuance"}
+	// shopAccount receives coins spent on merch and pays refunds back.
+	shopAccount = ledgerAccount{code: "system:shop"}
+)
+
+// ledgerEntry moves Amount coins from the Credit account to the Debit account.
+type ledgerEntry struct {
+	Kind      string
+	Debit     ledgerAccount
+	Credit    ledgerAccount
+	Amount    int64
+	Reference string
+	// Overdraft lets the Credit user account go below zero.
+	Overdraft bool
+}
+
+// postEntry records entry inside tx and updates the cached balance of the user
+// accounts involved. A user account cannot go below zero unless the entry
+// allows an overdraft, otherwise ErrInsufficientFunds is returned and the
+// caller must roll back. The overdraft is also what lets the balance past
+// users_balance_check.
+func (r *Service) postEntry(ctx context.Context, tx pgx.Tx, entry *ledgerEntry) error {
+	if entry.Credit.isUser {
+		res, err := tx.Exec(ctx,
+			`UPDATE users
+					SET balance = balance - @amount,
+						allow_negative = @overdraft AND balance < @amount
+					WHERE id = @user_id AND (@overdraft OR balance >= @amount)`,
+			pgx.NamedArgs{
+				"amount":    entry.Amount,
+				"user_id":   entry.Credit.userId,
+				"overdraft": entry.Overdraft,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("error update user balance: %w", err)
+		}
+		if res.RowsAffected() == 0 {
+			return ErrInsufficientFunds
+		}
+	}
+	if entry.Debit.isUser {
+		res, err := tx.Exec(ctx,
+			`UPDATE users
+					SET balance = balance + @amount,
+						allow_negative = allow_negative AND balance + @amount < 0
+					WHERE id = @user_id`,
+			pgx.NamedArgs{
+				"amount":  entry.Amount,
+				"user_id": entry.Debit.userId,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("error update user balance: %w", err)
+		}
+		if res.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+	}
+	_, err := tx.Exec(ctx,
+		`INSERT INTO ledger_entries(kind, debit_account, credit_account, amount, reference)
+				VALUES (@kind, @debit_account, @credit_account, @amount, NULLIF(@reference, ''))`,
+		pgx.NamedArgs{
+			"kind":           entry.Kind,
+			"debit_account":  entry.Debit.code,
+			"credit_account": entry.Credit.code,
+			"amount":         entry.Amount,
+			"reference":      entry.Reference,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error insert ledger entry: %w", err)
+	}
+	return nil
+}
+
+func (r *Service) CheckBalances(ctx context.Context) ([]*BalanceMismatch, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	rows, err := conn.Query(ctx,
+		`SELECT users.id, users.username, users.balance, COALESCE(ledger_balances.balance, 0)
+				FROM users
+				LEFT JOIN ledger_balances ON ledger_balances.account = 'user:' || users.id
+				WHERE users.balance <> COALESCE(ledger_balances.balance, 0)
+				ORDER BY users.id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mismatches := make([]*BalanceMismatch, 0)
+	for rows.Next() {
+		var mismatch BalanceMismatch
+		err := rows.Scan(&mismatch.UserId, &mismatch.UserName, &mismatch.Balance, &mismatch.LedgerBalance)
+		if err != nil {
+			return nil, err
+		}
+		mismatches = append(mismatches, &mismatch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return mismatches, nil
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	storage "github.com/azaliaz/avito-shop/internal/storage"
 	gomock "go.uber.org/mock/gomock"
@@ -40,6 +41,21 @@ func (m *MockShopStorage) EXPECT() *MockShopStorageMockRecorder {
 	return m.recorder
 }
 
+// AdjustBalance mocks base method.
+func (m *MockShopStorage) AdjustBalance(ctx context.Context, request *storage.AdjustBalanceRequest) (*storage.AdjustBalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBalance", ctx, request)
+	ret0, _ := ret[0].(*storage.AdjustBalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustBalance indicates an expected call of AdjustBalance.
+func (mr *MockShopStorageMockRecorder) AdjustBalance(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBalance", reflect.TypeOf((*MockShopStorage)(nil).AdjustBalance), ctx, request)
+}
+
 // Auth mocks base method.
 func (m *MockShopStorage) Auth(ctx context.Context, request *storage.AuthRequest) (*storage.AuthResponse, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +86,179 @@ func (mr *MockShopStorageMockRecorder) BuyItem(ctx, request any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockShopStorage)(nil).BuyItem), ctx, request)
 }
 
+// ChangePassword mocks base method.
+func (m *MockShopStorage) ChangePassword(ctx context.Context, request *storage.ChangePasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockShopStorageMockRecorder) ChangePassword(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockShopStorage)(nil).ChangePassword), ctx, request)
+}
+
+// CheckBalances mocks base method.
+func (m *MockShopStorage) CheckBalances(ctx context.Context) ([]*storage.BalanceMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckBalances", ctx)
+	ret0, _ := ret[0].([]*storage.BalanceMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckBalances indicates an expected call of CheckBalances.
+func (mr *MockShopStorageMockRecorder) CheckBalances(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckBalances", reflect.TypeOf((*MockShopStorage)(nil).CheckBalances), ctx)
+}
+
+// Checkout mocks base method.
+func (m *MockShopStorage) Checkout(ctx context.Context, request *storage.CheckoutRequest) (*storage.CheckoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", ctx, request)
+	ret0, _ := ret[0].(*storage.CheckoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockShopStorageMockRecorder) Checkout(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockShopStorage)(nil).Checkout), ctx, request)
+}
+
+// ClearAuthAttempts mocks base method.
+func (m *MockShopStorage) ClearAuthAttempts(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearAuthAttempts", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearAuthAttempts indicates an expected call of ClearAuthAttempts.
+func (mr *MockShopStorageMockRecorder) ClearAuthAttempts(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAuthAttempts", reflect.TypeOf((*MockShopStorage)(nil).ClearAuthAttempts), ctx, key)
+}
+
+// CountAuthAttempt mocks base method.
+func (m *MockShopStorage) CountAuthAttempt(ctx context.Context, request *storage.CountAuthAttemptRequest) (*storage.CountAuthAttemptResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAuthAttempt", ctx, request)
+	ret0, _ := ret[0].(*storage.CountAuthAttemptResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAuthAttempt indicates an expected call of CountAuthAttempt.
+func (mr *MockShopStorageMockRecorder) CountAuthAttempt(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAuthAttempt", reflect.TypeOf((*MockShopStorage)(nil).CountAuthAttempt), ctx, request)
+}
+
+// CreateItem mocks base method.
+func (m *MockShopStorage) CreateItem(ctx context.Context, request *storage.CreateItemRequest) (*storage.CreateItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", ctx, request)
+	ret0, _ := ret[0].(*storage.CreateItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockShopStorageMockRecorder) CreateItem(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockShopStorage)(nil).CreateItem), ctx, request)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockShopStorage) CreateRefreshToken(ctx context.Context, request *storage.CreateRefreshTokenRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockShopStorageMockRecorder) CreateRefreshToken(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockShopStorage)(nil).CreateRefreshToken), ctx, request)
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockShopStorage) DeleteExpiredIdempotencyKeys(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockShopStorageMockRecorder) DeleteExpiredIdempotencyKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockShopStorage)(nil).DeleteExpiredIdempotencyKeys), ctx)
+}
+
+// DeleteExpiredRefreshTokens mocks base method.
+func (m *MockShopStorage) DeleteExpiredRefreshTokens(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRefreshTokens", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredRefreshTokens indicates an expected call of DeleteExpiredRefreshTokens.
+func (mr *MockShopStorageMockRecorder) DeleteExpiredRefreshTokens(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRefreshTokens", reflect.TypeOf((*MockShopStorage)(nil).DeleteExpiredRefreshTokens), ctx)
+}
+
+// DeleteExpiredRevocations mocks base method.
+func (m *MockShopStorage) DeleteExpiredRevocations(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRevocations", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredRevocations indicates an expected call of DeleteExpiredRevocations.
+func (mr *MockShopStorageMockRecorder) DeleteExpiredRevocations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRevocations", reflect.TypeOf((*MockShopStorage)(nil).DeleteExpiredRevocations), ctx)
+}
+
+// DeleteStaleAuthAttempts mocks base method.
+func (m *MockShopStorage) DeleteStaleAuthAttempts(ctx context.Context, window time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleAuthAttempts", ctx, window)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStaleAuthAttempts indicates an expected call of DeleteStaleAuthAttempts.
+func (mr *MockShopStorageMockRecorder) DeleteStaleAuthAttempts(ctx, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleAuthAttempts", reflect.TypeOf((*MockShopStorage)(nil).DeleteStaleAuthAttempts), ctx, window)
+}
+
+// GetAuditEvents mocks base method.
+func (m *MockShopStorage) GetAuditEvents(ctx context.Context, request *storage.GetAuditEventsRequest) ([]*storage.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEvents", ctx, request)
+	ret0, _ := ret[0].([]*storage.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEvents indicates an expected call of GetAuditEvents.
+func (mr *MockShopStorageMockRecorder) GetAuditEvents(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEvents", reflect.TypeOf((*MockShopStorage)(nil).GetAuditEvents), ctx, request)
+}
+
 // GetBalance mocks base method.
 func (m *MockShopStorage) GetBalance(ctx context.Context, userId uint64) (int, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +289,36 @@ func (mr *MockShopStorageMockRecorder) GetCoinHistory(ctx, userId any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinHistory", reflect.TypeOf((*MockShopStorage)(nil).GetCoinHistory), ctx, userId)
 }
 
+// GetCredentials mocks base method.
+func (m *MockShopStorage) GetCredentials(ctx context.Context, username string) (*storage.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCredentials", ctx, username)
+	ret0, _ := ret[0].(*storage.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCredentials indicates an expected call of GetCredentials.
+func (mr *MockShopStorageMockRecorder) GetCredentials(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredentials", reflect.TypeOf((*MockShopStorage)(nil).GetCredentials), ctx, username)
+}
+
+// GetHistory mocks base method.
+func (m *MockShopStorage) GetHistory(ctx context.Context, request *storage.GetHistoryRequest) ([]*storage.HistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, request)
+	ret0, _ := ret[0].([]*storage.HistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockShopStorageMockRecorder) GetHistory(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockShopStorage)(nil).GetHistory), ctx, request)
+}
+
 // GetInventory mocks base method.
 func (m *MockShopStorage) GetInventory(ctx context.Context, userId uint64) ([]*storage.ProductStock, error) {
 	m.ctrl.T.Helper()
@@ -115,6 +334,257 @@ func (mr *MockShopStorageMockRecorder) GetInventory(ctx, userId any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventory", reflect.TypeOf((*MockShopStorage)(nil).GetInventory), ctx, userId)
 }
 
+// GetItem mocks base method.
+func (m *MockShopStorage) GetItem(ctx context.Context, name string) (*storage.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, name)
+	ret0, _ := ret[0].(*storage.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockShopStorageMockRecorder) GetItem(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockShopStorage)(nil).GetItem), ctx, name)
+}
+
+// GetItemPrices mocks base method.
+func (m *MockShopStorage) GetItemPrices(ctx context.Context, name string) ([]*storage.ItemPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemPrices", ctx, name)
+	ret0, _ := ret[0].([]*storage.ItemPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemPrices indicates an expected call of GetItemPrices.
+func (mr *MockShopStorageMockRecorder) GetItemPrices(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemPrices", reflect.TypeOf((*MockShopStorage)(nil).GetItemPrices), ctx, name)
+}
+
+// GetItems mocks base method.
+func (m *MockShopStorage) GetItems(ctx context.Context) ([]*storage.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx)
+	ret0, _ := ret[0].([]*storage.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockShopStorageMockRecorder) GetItems(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockShopStorage)(nil).GetItems), ctx)
+}
+
+// GetOrders mocks base method.
+func (m *MockShopStorage) GetOrders(ctx context.Context, request *storage.GetOrdersRequest) (*storage.GetOrdersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders", ctx, request)
+	ret0, _ := ret[0].(*storage.GetOrdersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockShopStorageMockRecorder) GetOrders(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockShopStorage)(nil).GetOrders), ctx, request)
+}
+
+// GetRevocations mocks base method.
+func (m *MockShopStorage) GetRevocations(ctx context.Context) (*storage.Revocations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevocations", ctx)
+	ret0, _ := ret[0].(*storage.Revocations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevocations indicates an expected call of GetRevocations.
+func (mr *MockShopStorageMockRecorder) GetRevocations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevocations", reflect.TypeOf((*MockShopStorage)(nil).GetRevocations), ctx)
+}
+
+// GetUser mocks base method.
+func (m *MockShopStorage) GetUser(ctx context.Context, userId uint64) (*storage.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, userId)
+	ret0, _ := ret[0].(*storage.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockShopStorageMockRecorder) GetUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockShopStorage)(nil).GetUser), ctx, userId)
+}
+
+// GetUserByName mocks base method.
+func (m *MockShopStorage) GetUserByName(ctx context.Context, username string) (*storage.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByName", ctx, username)
+	ret0, _ := ret[0].(*storage.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByName indicates an expected call of GetUserByName.
+func (mr *MockShopStorageMockRecorder) GetUserByName(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByName", reflect.TypeOf((*MockShopStorage)(nil).GetUserByName), ctx, username)
+}
+
+// RecordAuditEvent mocks base method.
+func (m *MockShopStorage) RecordAuditEvent(ctx context.Context, event *storage.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAuditEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAuditEvent indicates an expected call of RecordAuditEvent.
+func (mr *MockShopStorageMockRecorder) RecordAuditEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAuditEvent", reflect.TypeOf((*MockShopStorage)(nil).RecordAuditEvent), ctx, event)
+}
+
+// Register mocks base method.
+func (m *MockShopStorage) Register(ctx context.Context, request *storage.RegisterRequest) (*storage.RegisterResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, request)
+	ret0, _ := ret[0].(*storage.RegisterResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockShopStorageMockRecorder) Register(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockShopStorage)(nil).Register), ctx, request)
+}
+
+// ReleaseAuthAttempt mocks base method.
+func (m *MockShopStorage) ReleaseAuthAttempt(ctx context.Context, request *storage.ReleaseAuthAttemptRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseAuthAttempt", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseAuthAttempt indicates an expected call of ReleaseAuthAttempt.
+func (mr *MockShopStorageMockRecorder) ReleaseAuthAttempt(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseAuthAttempt", reflect.TypeOf((*MockShopStorage)(nil).ReleaseAuthAttempt), ctx, request)
+}
+
+// RenameItem mocks base method.
+func (m *MockShopStorage) RenameItem(ctx context.Context, request *storage.RenameItemRequest) (*storage.RenameItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameItem", ctx, request)
+	ret0, _ := ret[0].(*storage.RenameItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameItem indicates an expected call of RenameItem.
+func (mr *MockShopStorageMockRecorder) RenameItem(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameItem", reflect.TypeOf((*MockShopStorage)(nil).RenameItem), ctx, request)
+}
+
+// RestockItem mocks base method.
+func (m *MockShopStorage) RestockItem(ctx context.Context, request *storage.RestockItemRequest) (*storage.RestockItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestockItem", ctx, request)
+	ret0, _ := ret[0].(*storage.RestockItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestockItem indicates an expected call of RestockItem.
+func (mr *MockShopStorageMockRecorder) RestockItem(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestockItem", reflect.TypeOf((*MockShopStorage)(nil).RestockItem), ctx, request)
+}
+
+// RetireItem mocks base method.
+func (m *MockShopStorage) RetireItem(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireItem", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireItem indicates an expected call of RetireItem.
+func (mr *MockShopStorageMockRecorder) RetireItem(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireItem", reflect.TypeOf((*MockShopStorage)(nil).RetireItem), ctx, name)
+}
+
+// ReturnOrder mocks base method.
+func (m *MockShopStorage) ReturnOrder(ctx context.Context, request *storage.ReturnOrderRequest) (*storage.ReturnOrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnOrder", ctx, request)
+	ret0, _ := ret[0].(*storage.ReturnOrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReturnOrder indicates an expected call of ReturnOrder.
+func (mr *MockShopStorageMockRecorder) ReturnOrder(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnOrder", reflect.TypeOf((*MockShopStorage)(nil).ReturnOrder), ctx, request)
+}
+
+// RevokeToken mocks base method.
+func (m *MockShopStorage) RevokeToken(ctx context.Context, request *storage.RevokeTokenRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockShopStorageMockRecorder) RevokeToken(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockShopStorage)(nil).RevokeToken), ctx, request)
+}
+
+// RevokeUserTokens mocks base method.
+func (m *MockShopStorage) RevokeUserTokens(ctx context.Context, request *storage.RevokeUserTokensRequest) (*storage.RevokeUserTokensResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserTokens", ctx, request)
+	ret0, _ := ret[0].(*storage.RevokeUserTokensResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeUserTokens indicates an expected call of RevokeUserTokens.
+func (mr *MockShopStorageMockRecorder) RevokeUserTokens(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserTokens", reflect.TypeOf((*MockShopStorage)(nil).RevokeUserTokens), ctx, request)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockShopStorage) RotateRefreshToken(ctx context.Context, request *storage.RotateRefreshTokenRequest) (*storage.RotateRefreshTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, request)
+	ret0, _ := ret[0].(*storage.RotateRefreshTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockShopStorageMockRecorder) RotateRefreshToken(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockShopStorage)(nil).RotateRefreshToken), ctx, request)
+}
+
 // SendCoin mocks base method.
 func (m *MockShopStorage) SendCoin(ctx context.Context, request *storage.SendCoinRequest) (*storage.SendCoinResponse, error) {
 	m.ctrl.T.Helper()
@@ -129,3 +599,47 @@ func (mr *MockShopStorageMockRecorder) SendCoin(ctx, request any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCoin", reflect.TypeOf((*MockShopStorage)(nil).SendCoin), ctx, request)
 }
+
+// SetUserRole mocks base method.
+func (m *MockShopStorage) SetUserRole(ctx context.Context, request *storage.SetUserRoleRequest) (*storage.SetUserRoleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", ctx, request)
+	ret0, _ := ret[0].(*storage.SetUserRoleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockShopStorageMockRecorder) SetUserRole(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockShopStorage)(nil).SetUserRole), ctx, request)
+}
+
+// UpdateItemPrice mocks base method.
+func (m *MockShopStorage) UpdateItemPrice(ctx context.Context, request *storage.UpdateItemPriceRequest) (*storage.UpdateItemPriceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPrice", ctx, request)
+	ret0, _ := ret[0].(*storage.UpdateItemPriceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemPrice indicates an expected call of UpdateItemPrice.
+func (mr *MockShopStorageMockRecorder) UpdateItemPrice(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPrice", reflect.TypeOf((*MockShopStorage)(nil).UpdateItemPrice), ctx, request)
+}
+
+// UpdatePassHash mocks base method.
+func (m *MockShopStorage) UpdatePassHash(ctx context.Context, request *storage.UpdatePassHashRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassHash", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassHash indicates an expected call of UpdatePassHash.
+func (mr *MockShopStorageMockRecorder) UpdatePassHash(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassHash", reflect.TypeOf((*MockShopStorage)(nil).UpdatePassHash), ctx, request)
+}
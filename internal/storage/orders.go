@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+)
+
+func (r *Service) GetOrders(ctx context.Context, request *GetOrdersRequest) (*GetOrdersResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	var total int
+	err = conn.QueryRow(ctx,
+		`SELECT count(*)
+				FROM orders
+				WHERE user_id = @user_id`,
+		pgx.NamedArgs{
+			"user_id": request.UserId,
+		},
+	).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx,
+		`SELECT id, item, price, quantity, returned_quantity, created_at
+				FROM orders
+				WHERE user_id = @user_id
+				ORDER BY created_at DESC, id DESC
+				LIMIT @limit OFFSET @offset`,
+		pgx.NamedArgs{
+			"user_id": request.UserId,
+			"limit":   request.Limit,
+			"offset":  request.Offset,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]*Order, 0, request.Limit)
+	ordersById := make(map[uint64]*Order, request.Limit)
+	orderIds := make([]uint64, 0, request.Limit)
+	for rows.Next() {
+		var order Order
+		err := rows.Scan(&order.Id, &order.Item, &order.Price, &order.Quantity, &order.ReturnedQuantity, &order.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, &order)
+		ordersById[order.Id] = &order
+		orderIds = append(orderIds, order.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = conn.Query(ctx,
+		`SELECT order_id, quantity, amount, created_at
+				FROM refunds
+				WHERE order_id = ANY(@order_ids)
+				ORDER BY created_at, id`,
+		pgx.NamedArgs{
+			"order_ids": orderIds,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var orderId uint64
+		var refund Refund
+		err := rows.Scan(&orderId, &refund.Quantity, &refund.Amount, &refund.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		order := ordersById[orderId]
+		order.Refunds = append(order.Refunds, &refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &GetOrdersResponse{
+		Orders: orders,
+		Total:  total,
+	}, nil
+}
+
+func (r *Service) ReturnOrder(ctx context.Context, request *ReturnOrderRequest) (*ReturnOrderResponse, error) {
+	if request.Quantity < 0 {
+		return nil, errors.New("quantity must be positive")
+	}
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+
+	var item string
+	var price, quantity, returnedQuantity int
+	var expired bool
+	err = tx.QueryRow(ctx,
+		`SELECT item, price, quantity, returned_quantity,
+       				created_at < CURRENT_TIMESTAMP - @return_window * INTERVAL '1 second'
+				FROM orders
+				WHERE id = @order_id AND user_id = @user_id
+				FOR UPDATE`,
+		pgx.NamedArgs{
+			"order_id":      request.OrderId,
+			"user_id":       request.UserId,
+			"return_window": request.ReturnWindow.Seconds(),
+		},
+	).Scan(&item, &price, &quantity, &returnedQuantity, &expired)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error get order from db: %w", err)
+	}
+	if expired {
+		return nil, ErrReturnExpired
+	}
+	returnQuantity := request.Quantity
+	if returnQuantity == 0 {
+		returnQuantity = quantity - returnedQuantity
+	}
+	if returnQuantity == 0 || returnedQuantity+returnQuantity > quantity {
+		return nil, ErrNothingToReturn
+	}
+
+	res, err := tx.Exec(ctx,
+		`UPDATE inventory SET quantity = quantity - @quantity
+				WHERE user_id = @user_id AND item = @item AND quantity >= @quantity`,
+		pgx.NamedArgs{
+			"user_id":  request.UserId,
+			"item":     item,
+			"quantity": returnQuantity,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error update user inventory: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, ErrNothingToReturn
+	}
+	_, err = tx.Exec(ctx,
+		`DELETE FROM inventory
+				WHERE user_id = @user_id AND item = @item AND quantity = 0`,
+		pgx.NamedArgs{
+			"user_id": request.UserId,
+			"item":    item,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error update user inventory: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE items SET stock = stock + @quantity
+				WHERE name = @item AND stock IS NOT NULL`,
+		pgx.NamedArgs{
+			"item":     item,
+			"quantity": returnQuantity,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error update item stock: %w", err)
+	}
+
+	amount := price * returnQuantity
+	_, err = tx.Exec(ctx,
+		`UPDATE orders SET returned_quantity = returned_quantity + @quantity
+				WHERE id = @order_id`,
+		pgx.NamedArgs{
+			"order_id": request.OrderId,
+			"quantity": returnQuantity,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error update order: %w", err)
+	}
+	// The refund is a transfer from the system user, so that it shows up in
+	// the transfer history next to the purchase it pays back.
+	var transactionId uint64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO transactions (from_user_id, to_user_id, amount, memo)
+				SELECT id, @user_id, @amount, @memo
+				FROM users
+				WHERE is_system
+				RETURNING id`,
+		pgx.NamedArgs{
+			"user_id": request.UserId,
+			"amount":  amount,
+			"memo":    fmt.Sprintf("refund of order %d", request.OrderId),
+		},
+	).Scan(&transactionId)
+	if err != nil {
+		return nil, fmt.Errorf("error insert transaction: %w", err)
+	}
+	var refundId uint64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO refunds(order_id, user_id, quantity, amount, transaction_id)
+				VALUES (@order_id, @user_id, @quantity, @amount, @transaction_id)
+				RETURNING id`,
+		pgx.NamedArgs{
+			"order_id":       request.OrderId,
+			"user_id":        request.UserId,
+			"quantity":       returnQuantity,
+			"amount":         amount,
+			"transaction_id": transactionId,
+		},
+	).Scan(&refundId)
+	if err != nil {
+		return nil, fmt.Errorf("error insert refund: %w", err)
+	}
+	err = r.postEntry(ctx, tx, &ledgerEntry{
+		Kind:      entryRefund,
+		Debit:     userAccount(request.UserId),
+		Credit:    shopAccount,
+		Amount:    int64(amount),
+		Reference: fmt.Sprintf("refund:%d", refundId),
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		ActorId: request.UserId,
+		Action:  AuditReturnOrder,
+		Target:  fmt.Sprintf("order:%d", request.OrderId),
+		Details: map[string]any{
+			"quantity":      returnQuantity,
+			"amount":        amount,
+			"refundId":      refundId,
+			"transactionId": transactionId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ReturnOrderResponse{
+		Amount: amount,
+	}, nil
+}
@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+)
+
+func (r *Service) RevokeToken(ctx context.Context, request *RevokeTokenRequest) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	_, err = tx.Exec(ctx,
+		`INSERT INTO revoked_tokens(jti, user_id, expires_at)
+				VALUES (@jti, @user_id, @expires_at)
+				ON CONFLICT (jti) DO NOTHING`,
+		pgx.NamedArgs{
+			"jti":        request.Jti,
+			"user_id":    request.UserId,
+			"expires_at": request.ExpiresAt,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error insert revoked token: %w", err)
+	}
+	if request.RefreshTokenHash != "" {
+		_, err = tx.Exec(ctx,
+			`UPDATE refresh_tokens
+					SET revoked_at = CURRENT_TIMESTAMP
+					WHERE revoked_at IS NULL AND family_id = (
+						SELECT family_id
+						FROM refresh_tokens
+						WHERE token_hash = @token_hash AND user_id = @user_id
+					)`,
+			pgx.NamedArgs{
+				"token_hash": request.RefreshTokenHash,
+				"user_id":    request.UserId,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("error revoke refresh token family: %w", err)
+		}
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		ActorId: request.UserId,
+		Action:  AuditLogout,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// RevokeUserTokens invalidates every access token issued to the user so far
+// and all of the user's refresh tokens.
+func (r *Service) RevokeUserTokens(ctx context.Context, request *RevokeUserTokensRequest) (*RevokeUserTokensResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	var resp RevokeUserTokensResponse
+	err = tx.QueryRow(ctx,
+		`INSERT INTO revoked_sessions(user_id, revoked_before, expires_at)
+				SELECT id, @revoked_before::TIMESTAMPTZ, @revoked_before::TIMESTAMPTZ + @ttl * INTERVAL '1 second'
+				FROM users
+				WHERE username = @username
+				ON CONFLICT (user_id) DO UPDATE
+					SET revoked_before = GREATEST(revoked_sessions.revoked_before, EXCLUDED.revoked_before),
+						expires_at = GREATEST(revoked_sessions.expires_at, EXCLUDED.expires_at)
+				RETURNING user_id, revoked_before, expires_at`,
+		pgx.NamedArgs{
+			"username":       request.UserName,
+			"revoked_before": request.RevokedBefore,
+			"ttl":            request.TokenTTL.Seconds(),
+		},
+	).Scan(&resp.UserId, &resp.RevokedBefore, &resp.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error insert revoked session: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE refresh_tokens
+				SET revoked_at = CURRENT_TIMESTAMP
+				WHERE user_id = @user_id AND revoked_at IS NULL`,
+		pgx.NamedArgs{
+			"user_id": resp.UserId,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error revoke refresh tokens: %w", err)
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		Action: AuditRevokeSessions,
+		Target: request.UserName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp, tx.Commit(ctx)
+}
+
+// GetRevocations returns the revocations that still matter, i.e. those whose
+// tokens have not expired yet.
+func (r *Service) GetRevocations(ctx context.Context) (*Revocations, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	rows, err := conn.Query(ctx,
+		`SELECT jti, expires_at
+				FROM revoked_tokens
+				WHERE expires_at > CURRENT_TIMESTAMP`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revocations := &Revocations{}
+	for rows.Next() {
+		var token RevokedToken
+		if err := rows.Scan(&token.Jti, &token.ExpiresAt); err != nil {
+			return nil, err
+		}
+		revocations.Tokens = append(revocations.Tokens, &token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = conn.Query(ctx,
+		`SELECT user_id, revoked_before, expires_at
+				FROM revoked_sessions
+				WHERE expires_at > CURRENT_TIMESTAMP`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var session RevokedSession
+		if err := rows.Scan(&session.UserId, &session.RevokedBefore, &session.ExpiresAt); err != nil {
+			return nil, err
+		}
+		revocations.Sessions = append(revocations.Sessions, &session)
+	}
+	return revocations, rows.Err()
+}
+
+// DeleteExpiredRevocations drops revocations of tokens that have expired on
+// their own by now.
+func (r *Service) DeleteExpiredRevocations(ctx context.Context) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	_, err = conn.Exec(ctx,
+		`DELETE FROM revoked_tokens WHERE expires_at <= CURRENT_TIMESTAMP`,
+	)
+	if err != nil {
+		return fmt.Errorf("error delete expired revoked tokens: %w", err)
+	}
+	_, err = conn.Exec(ctx,
+		`DELETE FROM revoked_sessions WHERE expires_at <= CURRENT_TIMESTAMP`,
+	)
+	if err != nil {
+		return fmt.Errorf("error delete expired revoked sessions: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"log/slog"
@@ -12,13 +13,72 @@ import (
 
 type ShopStorage interface {
 	Auth(ctx context.Context, request *AuthRequest) (*AuthResponse, error)
+	Register(ctx context.Context, request *RegisterRequest) (*RegisterResponse, error)
+	GetCredentials(ctx context.Context, username string) (*AuthResponse, error)
+	ChangePassword(ctx context.Context, request *ChangePasswordRequest) error
+	UpdatePassHash(ctx context.Context, request *UpdatePassHashRequest) error
+	CreateRefreshToken(ctx context.Context, request *CreateRefreshTokenRequest) error
+	RotateRefreshToken(ctx context.Context, request *RotateRefreshTokenRequest) (*RotateRefreshTokenResponse, error)
+	DeleteExpiredRefreshTokens(ctx context.Context) error
+	RevokeToken(ctx context.Context, request *RevokeTokenRequest) error
+	RevokeUserTokens(ctx context.Context, request *RevokeUserTokensRequest) (*RevokeUserTokensResponse, error)
+	GetRevocations(ctx context.Context) (*Revocations, error)
+	DeleteExpiredRevocations(ctx context.Context) error
+	CountAuthAttempt(ctx context.Context, request *CountAuthAttemptRequest) (*CountAuthAttemptResponse, error)
+	ReleaseAuthAttempt(ctx context.Context, request *ReleaseAuthAttemptRequest) error
+	ClearAuthAttempts(ctx context.Context, key string) error
+	DeleteStaleAuthAttempts(ctx context.Context, window time.Duration) error
 	GetInventory(ctx context.Context, userId uint64) ([]*ProductStock, error)
 	GetBalance(ctx context.Context, userId uint64) (balance int, err error)
 	GetCoinHistory(ctx context.Context, userId uint64) (*CoinHistory, error)
 	SendCoin(ctx context.Context, request *SendCoinRequest) (*SendCoinResponse, error)
 	BuyItem(ctx context.Context, request *BuyItemRequest) (*BuyItemResponse, error)
+	GetItems(ctx context.Context) ([]*Item, error)
+	GetItem(ctx context.Context, name string) (*Item, error)
+	GetUser(ctx context.Context, userId uint64) (*User, error)
+	GetUserByName(ctx context.Context, username string) (*User, error)
+	SetUserRole(ctx context.Context, request *SetUserRoleRequest) (*SetUserRoleResponse, error)
+	AdjustBalance(ctx context.Context, request *AdjustBalanceRequest) (*AdjustBalanceResponse, error)
+	CreateItem(ctx context.Context, request *CreateItemRequest) (*CreateItemResponse, error)
+	UpdateItemPrice(ctx context.Context, request *UpdateItemPriceRequest) (*UpdateItemPriceResponse, error)
+	RenameItem(ctx context.Context, request *RenameItemRequest) (*RenameItemResponse, error)
+	RetireItem(ctx context.Context, name string) error
+	GetItemPrices(ctx context.Context, name string) ([]*ItemPrice, error)
+	RestockItem(ctx context.Context, request *RestockItemRequest) (*RestockItemResponse, error)
+	Checkout(ctx context.Context, request *CheckoutRequest) (*CheckoutResponse, error)
+	GetOrders(ctx context.Context, request *GetOrdersRequest) (*GetOrdersResponse, error)
+	ReturnOrder(ctx context.Context, request *ReturnOrderRequest) (*ReturnOrderResponse, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) error
+	CheckBalances(ctx context.Context) ([]*BalanceMismatch, error)
+	GetHistory(ctx context.Context, request *GetHistoryRequest) ([]*HistoryEntry, error)
+	RecordAuditEvent(ctx context.Context, event *AuditEvent) error
+	GetAuditEvents(ctx context.Context, request *GetAuditEventsRequest) ([]*AuditEvent, error)
 }
 
+var (
+	ErrItemNotFound      = errors.New("item not found")
+	ErrItemExists        = errors.New("item already exists")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrSelfTransfer      = errors.New("cannot send coins to yourself")
+	ErrUserExists        = errors.New("user already exists")
+	ErrItemSoldOut       = errors.New("item is sold out")
+	// ErrItemUnlimited is returned when restocking an item without a stock
+	// limit.
+	ErrItemUnlimited     = errors.New("item stock is unlimited")
+	ErrInsufficientFunds = errors.New("not enough coins")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrReturnExpired     = errors.New("return window has expired")
+	ErrNothingToReturn   = errors.New("not enough units to return")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again
+	// with a different operation or different parameters.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for another request")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token
+	// is presented again, the whole token family is revoked by then.
+	ErrRefreshTokenReused = errors.New("refresh token was already used")
+)
+
 type AuthRequest struct {
 	UserName string
 	PassHash string
@@ -28,6 +88,92 @@ type AuthResponse struct {
 	UserId   uint64
 	UserName string
 	PassHash string
+	Role     string
+}
+
+type RegisterRequest struct {
+	UserName string
+	PassHash string
+}
+
+type RegisterResponse struct {
+	UserId uint64
+}
+
+type CreateRefreshTokenRequest struct {
+	UserId uint64
+	// TokenHash is the SHA-256 of the token, the token itself is never stored.
+	TokenHash string
+	// FamilyId groups all tokens rotated from the same login.
+	FamilyId string
+	TTL      time.Duration
+	// Login records the new session as a login. Sessions started by a
+	// registration or a password change are recorded as those operations.
+	Login bool
+}
+
+type RotateRefreshTokenRequest struct {
+	TokenHash    string
+	NewTokenHash string
+	TTL          time.Duration
+}
+
+type RotateRefreshTokenResponse struct {
+	UserId uint64
+	// Role is the current role of the user, a new access token carries it.
+	Role string
+}
+
+type RevokeTokenRequest struct {
+	Jti    string
+	UserId uint64
+	// ExpiresAt is the exp of the token, the revocation is dropped after it.
+	ExpiresAt time.Time
+	// RefreshTokenHash optionally revokes the refresh token family as well.
+	RefreshTokenHash string
+}
+
+type ChangePasswordRequest struct {
+	UserId   uint64
+	PassHash string
+}
+
+type UpdatePassHashRequest struct {
+	UserId      uint64
+	OldPassHash string
+	PassHash    string
+}
+
+type RevokeUserTokensRequest struct {
+	UserName string
+	// RevokedBefore is the cutoff, tokens issued before it are rejected.
+	RevokedBefore time.Time
+	// TokenTTL is the access token lifetime, the revocation is kept for
+	// that long.
+	TokenTTL time.Duration
+}
+
+type RevokeUserTokensResponse struct {
+	UserId        uint64
+	RevokedBefore time.Time
+	ExpiresAt     time.Time
+}
+
+type Revocations struct {
+	Tokens   []*RevokedToken
+	Sessions []*RevokedSession
+}
+
+type RevokedToken struct {
+	Jti       string
+	ExpiresAt time.Time
+}
+
+// RevokedSession rejects all tokens of the user issued before RevokedBefore.
+type RevokedSession struct {
+	UserId        uint64
+	RevokedBefore time.Time
+	ExpiresAt     time.Time
 }
 
 type CoinHistory struct {
@@ -39,6 +185,93 @@ type Transaction struct {
 	Amount    int
 	FromUser  string
 	ToUser    string
+	Memo      string
+	CreatedAt time.Time
+}
+
+type GetHistoryRequest struct {
+	UserId uint64
+	// Direction is DirectionSent, DirectionReceived or empty for both.
+	Direction string
+	// Counterparty keeps only transfers with this user, empty disables it.
+	Counterparty string
+	// From and To bound created_at as [From, To), nil means unbounded.
+	From  *time.Time
+	To    *time.Time
+	Limit int
+	// After is the last entry of the previous page, nil for the first page.
+	After *HistoryCursor
+}
+
+type GetAuditEventsRequest struct {
+	// Actor, Action, Outcome and IP keep only matching events, empty disables
+	// the filter.
+	Actor   string
+	Action  string
+	Outcome string
+	IP      string
+	// From and To bound created_at as [From, To), nil means unbounded.
+	From  *time.Time
+	To    *time.Time
+	Limit int
+	// After is the last event of the previous page, nil for the first page.
+	After *HistoryCursor
+}
+
+type AuditEvent struct {
+	Id uint64
+	// ActorId is zero for anonymous requests. On write it defaults to the
+	// actor from the context, as does IP.
+	ActorId   uint64
+	ActorName string
+	Action    string
+	// Target is what the operation was applied to, such as a username or an item.
+	Target string
+	IP     string
+	// Outcome is AuditSuccess or AuditFailure, on write empty means success.
+	Outcome string
+	Details map[string]any
+	// CreatedAt is set by the database.
+	CreatedAt time.Time
+}
+
+type CountAuthAttemptRequest struct {
+	Key string
+	// Free is how many attempts within the window go without a block.
+	Free int
+	// Delays are how long the key is blocked after each attempt past the
+	// free ones, the last one repeats.
+	Delays []time.Duration
+	// Window is how long an attempt is counted, the count starts over when
+	// the previous attempt is older than that.
+	Window time.Duration
+}
+
+type CountAuthAttemptResponse struct {
+	// Attempts is the number of attempts within the window, including this
+	// one. It is zero when the key is blocked and the attempt not counted.
+	Attempts int
+	// RetryAfter is how long the key stays blocked.
+	RetryAfter time.Duration
+}
+
+type ReleaseAuthAttemptRequest struct {
+	Key  string
+	Free int
+}
+
+type HistoryCursor struct {
+	CreatedAt time.Time
+	Id        uint64
+}
+
+type HistoryEntry struct {
+	Id        uint64
+	Direction string
+	FromUser  string
+	ToUser    string
+	Amount    int
+	Memo      string
 	CreatedAt time.Time
 }
 
@@ -51,16 +284,182 @@ type SendCoinRequest struct {
 	UserId uint64
 	Amount int
 	ToUser string
+	// Memo is an optional message for the receiver.
+	Memo string
+	// IdempotencyKey makes retries of the same transfer safe, empty disables it.
+	// The key can be replayed for IdempotencyKeyTTL.
+	IdempotencyKey    string
+	IdempotencyKeyTTL time.Duration
 }
 
-type SendCoinResponse struct{}
+type SendCoinResponse struct {
+	// Replayed is set when the response is the stored result of an earlier
+	// request with the same idempotency key.
+	Replayed bool `json:"-"`
+}
+
+type AdjustBalanceRequest struct {
+	// AdminId is the user making the adjustment, idempotency keys are scoped
+	// to them.
+	AdminId  uint64
+	UserName string
+	// Amount is credited when positive and debited when negative.
+	Amount int
+	Reason string
+	// Force allows a debit to take the balance below zero.
+	Force bool
+	// IdempotencyKey makes retries of the same adjustment safe, empty disables it.
+	// The key can be replayed for IdempotencyKeyTTL.
+	IdempotencyKey    string
+	IdempotencyKeyTTL time.Duration
+}
+
+type AdjustBalanceResponse struct {
+	// Balance is the user's balance after the adjustment.
+	Balance int
+	// Replayed is set when the response is the stored result of an earlier
+	// request with the same idempotency key.
+	Replayed bool `json:"-"`
+}
 
 type BuyItemRequest struct {
-	UserId uint64
-	Item   string
+	UserId   uint64
+	Item     string
+	Quantity int
+	// IdempotencyKey makes retries of the same purchase safe, empty disables it.
+	// The key can be replayed for IdempotencyKeyTTL.
+	IdempotencyKey    string
+	IdempotencyKeyTTL time.Duration
 }
 
 type BuyItemResponse struct {
+	// Replayed is set when the response is the stored result of an earlier
+	// request with the same idempotency key.
+	Replayed bool `json:"-"`
+}
+
+type CartItem struct {
+	Item     string
+	Quantity int
+}
+
+type CheckoutRequest struct {
+	UserId uint64
+	Items  []*CartItem
+}
+
+type CheckoutResponse struct {
+	Total int
+}
+
+type Order struct {
+	Id               uint64
+	Item             string
+	Price            int
+	Quantity         int
+	ReturnedQuantity int
+	Refunds          []*Refund
+	CreatedAt        time.Time
+}
+
+type Refund struct {
+	Quantity  int
+	Amount    int
+	CreatedAt time.Time
+}
+
+type GetOrdersRequest struct {
+	UserId uint64
+	Limit  int
+	Offset int
+}
+
+type GetOrdersResponse struct {
+	Orders []*Order
+	Total  int
+}
+
+type ReturnOrderRequest struct {
+	UserId  uint64
+	OrderId uint64
+	// Quantity is the number of units to return, zero means all units that
+	// have not been returned yet.
+	Quantity int
+	// ReturnWindow is how long after the purchase the order can be returned.
+	ReturnWindow time.Duration
+}
+
+type ReturnOrderResponse struct {
+	Amount int
+}
+
+// BalanceMismatch is a user whose cached balance differs from the sum of the
+// ledger entries of their account.
+type BalanceMismatch struct {
+	UserId        uint64
+	UserName      string
+	Balance       int64
+	LedgerBalance int64
+}
+
+type Item struct {
+	Name  string
+	Price int
+	// Stock is the number of units left, nil means unlimited supply.
+	Stock *int
+}
+
+type ItemPrice struct {
+	Price     int
+	ValidFrom time.Time
+}
+
+type User struct {
+	UserId   uint64
+	UserName string
+	Role     string
+}
+
+type SetUserRoleRequest struct {
+	UserName string
+	Role     string
+}
+
+type SetUserRoleResponse struct {
+	UserId uint64
+	// Changed is false when the user already had the role.
+	Changed bool
+}
+
+type CreateItemRequest struct {
+	Name  string
+	Price int
+	Stock *int
+}
+
+type CreateItemResponse struct{}
+
+type UpdateItemPriceRequest struct {
+	Name  string
+	Price int
+}
+
+type UpdateItemPriceResponse struct{}
+
+type RenameItemRequest struct {
+	Name    string
+	NewName string
+}
+
+type RenameItemResponse struct{}
+
+type RestockItemRequest struct {
+	Name   string
+	Amount int
+}
+
+type RestockItemResponse struct {
+	Stock int
 }
 
 func NewService(db *DB, logger *slog.Logger) *Service {
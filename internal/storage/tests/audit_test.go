@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestAuditEvents() {
+	ctx := storage.WithActor(context.Background(), storage.Actor{IP: "10.0.0.1"})
+
+	user1, err := s.repo.Register(ctx, &storage.RegisterRequest{UserName: "user1", PassHash: "hash"})
+	require.NoError(s.T(), err)
+	_, err = s.repo.Register(ctx, &storage.RegisterRequest{UserName: "user2", PassHash: "hash"})
+	require.NoError(s.T(), err)
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+
+	s.T().Run("Mutations record who did what from where", func(t *testing.T) {
+		_, err := s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: user1.UserId, Amount: 100, ToUser: "user2"})
+		require.NoError(t, err)
+		adminCtx := storage.WithActor(ctx, storage.Actor{UserId: user1.UserId, IP: "10.0.0.2"})
+		_, err = s.repo.SetUserRole(adminCtx, &storage.SetUserRoleRequest{UserName: "user2", Role: "auditor"})
+		require.NoError(t, err)
+
+		events, err := s.repo.GetAuditEvents(ctx, &storage.GetAuditEventsRequest{Actor: "user1", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, storage.AuditSetUserRole, events[0].Action)
+		assert.Equal(t, "user2", events[0].Target)
+		assert.Equal(t, "10.0.0.2", events[0].IP)
+		assert.Equal(t, map[string]any{"role": "auditor", "previousRole": "user"}, events[0].Details)
+		assert.Equal(t, storage.AuditSendCoin, events[1].Action)
+		assert.Equal(t, "user1", events[1].ActorName)
+		assert.Equal(t, "10.0.0.1", events[1].IP)
+		assert.Equal(t, storage.AuditSuccess, events[1].Outcome)
+		assert.Equal(t, storage.AuditRegister, events[2].Action)
+	})
+
+	s.T().Run("Failed mutations leave no success event", func(t *testing.T) {
+		_, err := s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: user1.UserId, Amount: 5000, ToUser: "user2"})
+		require.Error(t, err)
+		err = s.repo.RecordAuditEvent(ctx, &storage.AuditEvent{
+			ActorId: user1.UserId,
+			Action:  storage.AuditSendCoin,
+			Target:  "user2",
+			Outcome: storage.AuditFailure,
+			Details: map[string]any{"error": "not enough coins"},
+		})
+		require.NoError(t, err)
+
+		events, err := s.repo.GetAuditEvents(ctx, &storage.GetAuditEventsRequest{Action: storage.AuditSendCoin, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, storage.AuditFailure, events[0].Outcome)
+		assert.Equal(t, storage.AuditSuccess, events[1].Outcome)
+
+		events, err = s.repo.GetAuditEvents(ctx, &storage.GetAuditEventsRequest{Outcome: storage.AuditFailure, Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+
+	s.T().Run("Pages follow the cursor", func(t *testing.T) {
+		page, err := s.repo.GetAuditEvents(ctx, &storage.GetAuditEventsRequest{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		next, err := s.repo.GetAuditEvents(ctx, &storage.GetAuditEventsRequest{
+			Limit: 10,
+			After: &storage.HistoryCursor{CreatedAt: page[1].CreatedAt, Id: page[1].Id},
+		})
+		require.NoError(t, err)
+		assert.Len(t, next, 3)
+	})
+
+	s.T().Run("Only a login session records a login", func(t *testing.T) {
+		// A registration starts a session too, it is recorded as the
+		// registration only.
+		err := s.repo.CreateRefreshToken(ctx, &storage.CreateRefreshTokenRequest{
+			UserId:    user1.UserId,
+			TokenHash: "register",
+			FamilyId:  "register",
+			TTL:       time.Hour,
+		})
+		require.NoError(t, err)
+		events, err := s.repo.GetAuditEvents(ctx, &storage.GetAuditEventsRequest{Action: storage.AuditLogin, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, events)
+
+		err = s.repo.CreateRefreshToken(ctx, &storage.CreateRefreshTokenRequest{
+			UserId:    user1.UserId,
+			TokenHash: "login",
+			FamilyId:  "login",
+			TTL:       time.Hour,
+			Login:     true,
+		})
+		require.NoError(t, err)
+		events, err = s.repo.GetAuditEvents(ctx, &storage.GetAuditEventsRequest{Action: storage.AuditLogin, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "user1", events[0].ActorName)
+	})
+
+	s.T().Run("Audit events are append-only", func(t *testing.T) {
+		_, err := conn.Exec(ctx, `UPDATE audit_events SET outcome = 'success'`)
+		require.Error(t, err)
+		_, err = conn.Exec(ctx, `DELETE FROM audit_events`)
+		require.Error(t, err)
+		_, err = conn.Exec(ctx, `TRUNCATE audit_events`)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func (s *RepositoryTestSuite) TestCheckout() {
+	ctx := context.Background()
+	userId := uint64(1)
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES ($1, 'user1', 'password_hash', 600)`, userId)
+	require.NoError(s.T(), err)
+
+	s.T().Run("Checkout whole cart", func(t *testing.T) {
+		res, err := s.repo.Checkout(ctx, &storage.CheckoutRequest{
+			UserId: userId,
+			Items: []*storage.CartItem{
+				{Item: "cup", Quantity: 1},
+				{Item: "pen", Quantity: 3},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 50, res.Total)
+
+		balance, err := s.repo.GetBalance(ctx, userId)
+		require.NoError(t, err)
+		assert.Equal(t, 550, balance)
+
+		inventory, err := s.repo.GetInventory(ctx, userId)
+		require.NoError(t, err)
+		assert.Len(t, inventory, 2)
+	})
+
+	s.T().Run("Checkout is rolled back when coins run out mid-way", func(t *testing.T) {
+		_, err := s.repo.Checkout(ctx, &storage.CheckoutRequest{
+			UserId: userId,
+			Items: []*storage.CartItem{
+				{Item: "book", Quantity: 1},
+				{Item: "pink-hoody", Quantity: 1},
+			},
+		})
+		require.ErrorIs(t, err, storage.ErrInsufficientFunds)
+
+		balance, err := s.repo.GetBalance(ctx, userId)
+		require.NoError(t, err)
+		assert.Equal(t, 550, balance)
+
+		inventory, err := s.repo.GetInventory(ctx, userId)
+		require.NoError(t, err)
+		assert.Len(t, inventory, 2)
+	})
+
+	s.T().Run("Checkout with unknown item", func(t *testing.T) {
+		_, err := s.repo.Checkout(ctx, &storage.CheckoutRequest{
+			UserId: userId,
+			Items: []*storage.CartItem{
+				{Item: "cup", Quantity: 1},
+				{Item: "car", Quantity: 1},
+			},
+		})
+		require.ErrorIs(t, err, storage.ErrItemNotFound)
+
+		balance, err := s.repo.GetBalance(ctx, userId)
+		require.NoError(t, err)
+		assert.Equal(t, 550, balance)
+	})
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestGetHistory() {
+	ctx := context.Background()
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES (1, 'user1', 'password_hash', 1000), (2, 'user2', 'password_hash', 1000), (3, 'user3', 'password_hash', 1000)`)
+	require.NoError(s.T(), err)
+
+	start := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+	transfers := []struct {
+		from, to uint64
+		amount   int
+	}{
+		{1, 2, 10},
+		{2, 1, 20},
+		{1, 3, 30},
+		{3, 1, 40},
+		{1, 2, 50},
+	}
+	for i, transfer := range transfers {
+		_, err = conn.Exec(ctx, `INSERT INTO transactions(from_user_id, to_user_id, amount, created_at)
+				VALUES ($1, $2, $3, $4)`, transfer.from, transfer.to, transfer.amount, start.Add(time.Duration(i)*time.Hour))
+		require.NoError(s.T(), err)
+	}
+
+	amounts := func(history []*storage.HistoryEntry) []int {
+		res := make([]int, 0, len(history))
+		for _, entry := range history {
+			res = append(res, entry.Amount)
+		}
+		return res
+	}
+
+	s.T().Run("Pages follow the cursor", func(t *testing.T) {
+		page, err := s.repo.GetHistory(ctx, &storage.GetHistoryRequest{UserId: 1, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []int{50, 40}, amounts(page))
+		assert.Equal(t, storage.DirectionSent, page[0].Direction)
+		assert.Equal(t, storage.DirectionReceived, page[1].Direction)
+
+		last := page[1]
+		page, err = s.repo.GetHistory(ctx, &storage.GetHistoryRequest{
+			UserId: 1,
+			Limit:  10,
+			After:  &storage.HistoryCursor{CreatedAt: last.CreatedAt, Id: last.Id},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{30, 20, 10}, amounts(page))
+	})
+
+	s.T().Run("Filter by direction and counterparty", func(t *testing.T) {
+		page, err := s.repo.GetHistory(ctx, &storage.GetHistoryRequest{UserId: 1, Direction: storage.DirectionReceived, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int{40, 20}, amounts(page))
+
+		page, err = s.repo.GetHistory(ctx, &storage.GetHistoryRequest{UserId: 1, Counterparty: "user2", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int{50, 20, 10}, amounts(page))
+	})
+
+	s.T().Run("Filter by date range", func(t *testing.T) {
+		from := start.Add(time.Hour)
+		to := start.Add(3 * time.Hour)
+		page, err := s.repo.GetHistory(ctx, &storage.GetHistoryRequest{UserId: 1, From: &from, To: &to, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int{30, 20}, amounts(page))
+	})
+}
+
+func (s *RepositoryTestSuite) TestSendCoinMemo() {
+	ctx := context.Background()
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES (1, 'user1', 'password_hash', 1000), (2, 'user2', 'password_hash', 1000)`)
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: 1, Amount: 10, ToUser: "user2", Memo: "thanks for the review"})
+	require.NoError(s.T(), err)
+	_, err = s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: 2, Amount: 5, ToUser: "user1"})
+	require.NoError(s.T(), err)
+
+	history, err := s.repo.GetCoinHistory(ctx, 2)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), history.Received, 1)
+	assert.Equal(s.T(), "thanks for the review", history.Received[0].Memo)
+	require.Len(s.T(), history.Sent, 1)
+	assert.Empty(s.T(), history.Sent[0].Memo)
+
+	page, err := s.repo.GetHistory(ctx, &storage.GetHistoryRequest{UserId: 1, Direction: storage.DirectionSent, Limit: 10})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page, 1)
+	assert.Equal(s.T(), "thanks for the review", page[0].Memo)
+}
+
+func (s *RepositoryTestSuite) TestSendCoinUnknownRecipient() {
+	ctx := context.Background()
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES (1, 'user1', 'password_hash', 1000)`)
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: 1, Amount: 10, ToUser: "nobody"})
+	assert.ErrorIs(s.T(), err, storage.ErrRecipientNotFound)
+}
+
+func (s *RepositoryTestSuite) TestSendCoinToSelf() {
+	ctx := context.Background()
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES (1, 'user1', 'password_hash', 1000)`)
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: 1, Amount: 10, ToUser: "user1"})
+	assert.ErrorIs(s.T(), err, storage.ErrSelfTransfer)
+}
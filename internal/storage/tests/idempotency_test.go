@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestIdempotencyKey() {
+	ctx := context.Background()
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES (1, 'user1', 'password_hash', 1000), (2, 'user2', 'password_hash', 1000)`)
+	require.NoError(s.T(), err)
+
+	s.T().Run("Repeated transfer runs once", func(t *testing.T) {
+		request := &storage.SendCoinRequest{UserId: 1, Amount: 100, ToUser: "user2", IdempotencyKey: "transfer-1", IdempotencyKeyTTL: time.Hour}
+		res, err := s.repo.SendCoin(ctx, request)
+		require.NoError(t, err)
+		assert.False(t, res.Replayed)
+
+		res, err = s.repo.SendCoin(ctx, request)
+		require.NoError(t, err)
+		assert.True(t, res.Replayed)
+
+		balance, err := s.repo.GetBalance(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 900, balance)
+	})
+
+	s.T().Run("Key reused with other parameters", func(t *testing.T) {
+		_, err := s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: 1, Amount: 200, ToUser: "user2", IdempotencyKey: "transfer-1", IdempotencyKeyTTL: time.Hour})
+		require.ErrorIs(t, err, storage.ErrIdempotencyKeyReused)
+
+		_, err = s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: 1, Item: "cup", Quantity: 1, IdempotencyKey: "transfer-1", IdempotencyKeyTTL: time.Hour})
+		require.ErrorIs(t, err, storage.ErrIdempotencyKeyReused)
+	})
+
+	s.T().Run("Keys are scoped to the user", func(t *testing.T) {
+		res, err := s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: 2, Amount: 50, ToUser: "user1", IdempotencyKey: "transfer-1", IdempotencyKeyTTL: time.Hour})
+		require.NoError(t, err)
+		assert.False(t, res.Replayed)
+	})
+
+	s.T().Run("Repeated purchase runs once", func(t *testing.T) {
+		request := &storage.BuyItemRequest{UserId: 1, Item: "cup", Quantity: 2, IdempotencyKey: "buy-1", IdempotencyKeyTTL: time.Hour}
+		_, err := s.repo.BuyItem(ctx, request)
+		require.NoError(t, err)
+		res, err := s.repo.BuyItem(ctx, request)
+		require.NoError(t, err)
+		assert.True(t, res.Replayed)
+
+		inventory, err := s.repo.GetInventory(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, inventory, 1)
+		assert.Equal(t, 2, inventory[0].Quantity)
+	})
+
+	s.T().Run("Failed purchase does not keep the key", func(t *testing.T) {
+		request := &storage.BuyItemRequest{UserId: 1, Item: "pink-hoody", Quantity: 3, IdempotencyKey: "buy-2", IdempotencyKeyTTL: time.Hour}
+		_, err := s.repo.BuyItem(ctx, request)
+		require.ErrorIs(t, err, storage.ErrInsufficientFunds)
+
+		request.Quantity = 1
+		res, err := s.repo.BuyItem(ctx, request)
+		require.NoError(t, err)
+		assert.False(t, res.Replayed)
+	})
+
+	s.T().Run("Expired key is claimed again", func(t *testing.T) {
+		request := &storage.SendCoinRequest{UserId: 1, Amount: 10, ToUser: "user2", IdempotencyKey: "transfer-2", IdempotencyKeyTTL: time.Hour}
+		_, err := s.repo.SendCoin(ctx, request)
+		require.NoError(t, err)
+		_, err = conn.Exec(ctx, `UPDATE idempotency_keys SET expires_at = CURRENT_TIMESTAMP - INTERVAL '1 second'
+				WHERE key = 'transfer-2'`)
+		require.NoError(t, err)
+
+		// Once expired, the key neither replays nor rejects other parameters.
+		request.Amount = 20
+		res, err := s.repo.SendCoin(ctx, request)
+		require.NoError(t, err)
+		assert.False(t, res.Replayed)
+		res, err = s.repo.SendCoin(ctx, request)
+		require.NoError(t, err)
+		assert.True(t, res.Replayed)
+	})
+
+	s.T().Run("Expired keys are deleted", func(t *testing.T) {
+		_, err := conn.Exec(ctx, `UPDATE idempotency_keys SET expires_at = CURRENT_TIMESTAMP - INTERVAL '1 second'
+				WHERE key = 'buy-1'`)
+		require.NoError(t, err)
+		require.NoError(t, s.repo.DeleteExpiredIdempotencyKeys(ctx))
+
+		var keys []string
+		rows, err := conn.Query(ctx, `SELECT key FROM idempotency_keys WHERE user_id = 1 ORDER BY key`)
+		require.NoError(t, err)
+		for rows.Next() {
+			var key string
+			require.NoError(t, rows.Scan(&key))
+			keys = append(keys, key)
+		}
+		require.NoError(t, rows.Err())
+		assert.Equal(t, []string{"buy-2", "transfer-1", "transfer-2"}, keys)
+	})
+}
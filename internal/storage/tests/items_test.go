@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func (s *RepositoryTestSuite) TestCreateItem() {
+	ctx := context.Background()
+
+	s.T().Run("Create new item", func(t *testing.T) {
+		_, err := s.repo.CreateItem(ctx, &storage.CreateItemRequest{Name: "sticker", Price: 5})
+		require.NoError(t, err)
+
+		item, err := s.repo.GetItem(ctx, "sticker")
+		require.NoError(t, err)
+		assert.Equal(t, 5, item.Price)
+
+		prices, err := s.repo.GetItemPrices(ctx, "sticker")
+		require.NoError(t, err)
+		assert.Len(t, prices, 1)
+		assert.Equal(t, 5, prices[0].Price)
+	})
+
+	s.T().Run("Create existing item", func(t *testing.T) {
+		_, err := s.repo.CreateItem(ctx, &storage.CreateItemRequest{Name: "cup", Price: 5})
+		require.ErrorIs(t, err, storage.ErrItemExists)
+	})
+}
+
+func (s *RepositoryTestSuite) TestUpdateItemPrice() {
+	ctx := context.Background()
+
+	_, err := s.repo.UpdateItemPrice(ctx, &storage.UpdateItemPriceRequest{Name: "cup", Price: 25})
+	require.NoError(s.T(), err)
+
+	item, err := s.repo.GetItem(ctx, "cup")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 25, item.Price)
+
+	prices, err := s.repo.GetItemPrices(ctx, "cup")
+	require.NoError(s.T(), err)
+	require.Len(s.T(), prices, 2)
+	assert.Equal(s.T(), 20, prices[0].Price)
+	assert.Equal(s.T(), 25, prices[1].Price)
+
+	_, err = s.repo.UpdateItemPrice(ctx, &storage.UpdateItemPriceRequest{Name: "car", Price: 25})
+	require.ErrorIs(s.T(), err, storage.ErrItemNotFound)
+}
+
+func (s *RepositoryTestSuite) TestRenameItem() {
+	ctx := context.Background()
+	userId := uint64(1)
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES ($1, 'user1', 'password_hash', 100)`, userId)
+	require.NoError(s.T(), err)
+	_, err = conn.Exec(ctx, `INSERT INTO inventory(user_id, item, quantity) VALUES ($1, 'cup', 2)`, userId)
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.RenameItem(ctx, &storage.RenameItemRequest{Name: "cup", NewName: "mug"})
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.GetItem(ctx, "cup")
+	require.ErrorIs(s.T(), err, storage.ErrItemNotFound)
+	item, err := s.repo.GetItem(ctx, "mug")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 20, item.Price)
+
+	inventory, err := s.repo.GetInventory(ctx, userId)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), inventory, 1)
+	assert.Equal(s.T(), "mug", inventory[0].Type)
+	assert.Equal(s.T(), 2, inventory[0].Quantity)
+
+	prices, err := s.repo.GetItemPrices(ctx, "mug")
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), prices, 1)
+
+	_, err = s.repo.RenameItem(ctx, &storage.RenameItemRequest{Name: "mug", NewName: "pen"})
+	require.ErrorIs(s.T(), err, storage.ErrItemExists)
+}
+
+func (s *RepositoryTestSuite) TestRetireItem() {
+	ctx := context.Background()
+	userId := uint64(1)
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES ($1, 'user1', 'password_hash', 1000)`, userId)
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "cup", Quantity: 1})
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), s.repo.RetireItem(ctx, "cup"))
+	require.ErrorIs(s.T(), s.repo.RetireItem(ctx, "cup"), storage.ErrItemNotFound)
+
+	_, err = s.repo.GetItem(ctx, "cup")
+	require.ErrorIs(s.T(), err, storage.ErrItemNotFound)
+
+	_, err = s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "cup", Quantity: 1})
+	require.ErrorIs(s.T(), err, storage.ErrItemNotFound)
+
+	inventory, err := s.repo.GetInventory(ctx, userId)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), inventory, 1)
+	assert.Equal(s.T(), "cup", inventory[0].Type)
+	assert.Equal(s.T(), 1, inventory[0].Quantity)
+}
+
+func (s *RepositoryTestSuite) TestRestockItem() {
+	ctx := context.Background()
+	userId := uint64(1)
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES ($1, 'user1', 'password_hash', 1000)`, userId)
+	require.NoError(s.T(), err)
+
+	// pink-hoody has unlimited supply, restocking must not limit it.
+	_, err = s.repo.RestockItem(ctx, &storage.RestockItemRequest{Name: "pink-hoody", Amount: 1})
+	require.ErrorIs(s.T(), err, storage.ErrItemUnlimited)
+	item, err := s.repo.GetItem(ctx, "pink-hoody")
+	require.NoError(s.T(), err)
+	assert.Nil(s.T(), item.Stock)
+
+	_, err = conn.Exec(ctx, `UPDATE items SET stock = 0 WHERE name = 'pink-hoody'`)
+	require.NoError(s.T(), err)
+	res, err := s.repo.RestockItem(ctx, &storage.RestockItemRequest{Name: "pink-hoody", Amount: 1})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, res.Stock)
+
+	item, err = s.repo.GetItem(ctx, "pink-hoody")
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), item.Stock)
+	assert.Equal(s.T(), 1, *item.Stock)
+
+	_, err = s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "pink-hoody", Quantity: 1})
+	require.NoError(s.T(), err)
+
+	item, err = s.repo.GetItem(ctx, "pink-hoody")
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), item.Stock)
+	assert.Equal(s.T(), 0, *item.Stock)
+
+	_, err = s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "pink-hoody", Quantity: 1})
+	require.ErrorIs(s.T(), err, storage.ErrItemSoldOut)
+
+	var balance int
+	err = conn.QueryRow(ctx, `SELECT balance FROM users WHERE id = $1`, userId).Scan(&balance)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 500, balance)
+
+	_, err = s.repo.RestockItem(ctx, &storage.RestockItemRequest{Name: "car", Amount: 1})
+	require.ErrorIs(s.T(), err, storage.ErrItemNotFound)
+}
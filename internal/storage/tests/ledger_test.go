@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestLedger() {
+	ctx := context.Background()
+
+	user1, err := s.repo.Auth(ctx, &storage.AuthRequest{UserName: "user1", PassHash: "password_hash"})
+	require.NoError(s.T(), err)
+	user2, err := s.repo.Auth(ctx, &storage.AuthRequest{UserName: "user2", PassHash: "password_hash"})
+	require.NoError(s.T(), err)
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+
+	s.T().Run("New users get an initial grant", func(t *testing.T) {
+		balance, err := s.repo.GetBalance(ctx, user1.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, 1000, balance)
+
+		var count int
+		err = conn.QueryRow(ctx, `SELECT count(*) FROM ledger_entries WHERE kind = 'grant'`).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	s.T().Run("Every coin movement is recorded", func(t *testing.T) {
+		_, err := s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: user1.UserId, Amount: 100, ToUser: "user2"})
+		require.NoError(t, err)
+		_, err = s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: user2.UserId, Item: "cup", Quantity: 3})
+		require.NoError(t, err)
+		orders, err := s.repo.GetOrders(ctx, &storage.GetOrdersRequest{UserId: user2.UserId, Limit: 1})
+		require.NoError(t, err)
+		_, err = s.repo.ReturnOrder(ctx, &storage.ReturnOrderRequest{
+			UserId:       user2.UserId,
+			OrderId:      orders.Orders[0].Id,
+			Quantity:     1,
+			ReturnWindow: time.Hour,
+		})
+		require.NoError(t, err)
+
+		rows, err := conn.Query(ctx, `SELECT kind, debit_account, credit_account, amount
+				FROM ledger_entries WHERE kind <> 'grant' ORDER BY id`)
+		require.NoError(t, err)
+		type entry struct {
+			kind, debit, credit string
+			amount              int64
+		}
+		var entries []entry
+		for rows.Next() {
+			var e entry
+			require.NoError(t, rows.Scan(&e.kind, &e.debit, &e.credit, &e.amount))
+			entries = append(entries, e)
+		}
+		require.NoError(t, rows.Err())
+		account1, account2 := fmt.Sprintf("user:%d", user1.UserId), fmt.Sprintf("user:%d", user2.UserId)
+		assert.Equal(t, []entry{
+			{"transfer", account2, account1, 100},
+			{"purchase", "system:shop", account2, 60},
+			{"refund", account2, "system:shop", 20},
+		}, entries)
+
+		balance, err := s.repo.GetBalance(ctx, user2.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, 1060, balance)
+	})
+
+	s.T().Run("Failed purchase leaves no entry", func(t *testing.T) {
+		_, err := s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: user1.UserId, Item: "pink-hoody", Quantity: 2})
+		require.ErrorIs(t, err, storage.ErrInsufficientFunds)
+
+		var count int
+		err = conn.QueryRow(ctx, `SELECT count(*) FROM ledger_entries WHERE kind = 'purchase'`).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	s.T().Run("Balances match the ledger", func(t *testing.T) {
+		mismatches, err := s.repo.CheckBalances(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, mismatches)
+
+		var total int64
+		err = conn.QueryRow(ctx, `SELECT sum(balance) FROM ledger_balances`).Scan(&total)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+
+	s.T().Run("Cached balance drift is detected", func(t *testing.T) {
+		_, err := conn.Exec(ctx, `UPDATE users SET balance = balance + 5 WHERE id = $1`, user1.UserId)
+		require.NoError(t, err)
+
+		mismatches, err := s.repo.CheckBalances(ctx)
+		require.NoError(t, err)
+		require.Len(t, mismatches, 1)
+		assert.Equal(t, "user1", mismatches[0].UserName)
+		assert.Equal(t, mismatches[0].LedgerBalance+5, mismatches[0].Balance)
+	})
+
+	s.T().Run("Ledger entries are append-only", func(t *testing.T) {
+		_, err := conn.Exec(ctx, `UPDATE ledger_entries SET amount = 1`)
+		require.Error(t, err)
+		_, err = conn.Exec(ctx, `DELETE FROM ledger_entries`)
+		require.Error(t, err)
+	})
+}
+
+func (s *RepositoryTestSuite) TestAdjustBalance() {
+	ctx := context.Background()
+
+	user, err := s.repo.Auth(ctx, &storage.AuthRequest{UserName: "user1", PassHash: "password_hash"})
+	require.NoError(s.T(), err)
+
+	s.T().Run("Credit comes from the system user", func(t *testing.T) {
+		res, err := s.repo.AdjustBalance(ctx, &storage.AdjustBalanceRequest{
+			AdminId:  user.UserId,
+			UserName: "user1",
+			Amount:   500,
+			Reason:   "bonus",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1500, res.Balance)
+
+		history, err := s.repo.GetHistory(ctx, &storage.GetHistoryRequest{UserId: user.UserId, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, storage.DirectionReceived, history[0].Direction)
+		assert.Equal(t, "system account", history[0].FromUser)
+		assert.Equal(t, 500, history[0].Amount)
+		assert.Equal(t, "bonus", history[0].Memo)
+	})
+
+	s.T().Run("Debit keeps the balance non-negative", func(t *testing.T) {
+		_, err := s.repo.AdjustBalance(ctx, &storage.AdjustBalanceRequest{
+			AdminId:  user.UserId,
+			UserName: "user1",
+			Amount:   -2000,
+			Reason:   "mistake",
+		})
+		require.ErrorIs(t, err, storage.ErrInsufficientFunds)
+
+		balance, err := s.repo.GetBalance(ctx, user.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, 1500, balance)
+	})
+
+	s.T().Run("Forced debit goes below zero", func(t *testing.T) {
+		res, err := s.repo.AdjustBalance(ctx, &storage.AdjustBalanceRequest{
+			AdminId:           user.UserId,
+			UserName:          "user1",
+			Amount:            -2000,
+			Reason:            "duplicate grant",
+			Force:             true,
+			IdempotencyKey:    "adjust-1",
+			IdempotencyKeyTTL: time.Hour,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, -500, res.Balance)
+
+		res, err = s.repo.AdjustBalance(ctx, &storage.AdjustBalanceRequest{
+			AdminId:           user.UserId,
+			UserName:          "user1",
+			Amount:            -2000,
+			Reason:            "duplicate grant",
+			Force:             true,
+			IdempotencyKey:    "adjust-1",
+			IdempotencyKeyTTL: time.Hour,
+		})
+		require.NoError(t, err)
+		assert.True(t, res.Replayed)
+		assert.Equal(t, -500, res.Balance)
+
+		_, err = s.repo.SendCoin(ctx, &storage.SendCoinRequest{UserId: user.UserId, Amount: 1, ToUser: "system account"})
+		assert.Error(t, err)
+
+		mismatches, err := s.repo.CheckBalances(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, mismatches)
+	})
+
+	s.T().Run("Only a forced debit gets past the balance check", func(t *testing.T) {
+		conn, err := s.db.Pool().Acquire(ctx)
+		require.NoError(t, err)
+		defer conn.Release()
+
+		// The balance is -500 and allow_negative is set by the forced debit,
+		// a debit that doesn't allow an overdraft clears it.
+		_, err = conn.Exec(ctx, `UPDATE users SET balance = balance - 1, allow_negative = false WHERE username = 'user1'`)
+		assert.Error(t, err)
+
+		res, err := s.repo.AdjustBalance(ctx, &storage.AdjustBalanceRequest{
+			AdminId:  user.UserId,
+			UserName: "user1",
+			Amount:   600,
+			Reason:   "settled",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 100, res.Balance)
+		_, err = conn.Exec(ctx, `UPDATE users SET balance = -1 WHERE username = 'user1'`)
+		assert.Error(t, err, "a credit back above zero clears allow_negative")
+	})
+
+	s.T().Run("Unknown and system users cannot be adjusted", func(t *testing.T) {
+		_, err := s.repo.AdjustBalance(ctx, &storage.AdjustBalanceRequest{UserName: "nobody", Amount: 1, Reason: "bonus"})
+		assert.ErrorIs(t, err, storage.ErrUserNotFound)
+		_, err = s.repo.AdjustBalance(ctx, &storage.AdjustBalanceRequest{UserName: "system account", Amount: 1, Reason: "bonus"})
+		assert.ErrorIs(t, err, storage.ErrUserNotFound)
+	})
+}
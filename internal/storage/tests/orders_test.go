@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestGetOrders() {
+	ctx := context.Background()
+	userId := uint64(1)
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES ($1, 'user1', 'password_hash', 1000)`, userId)
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "cup", Quantity: 2})
+	require.NoError(s.T(), err)
+	_, err = s.repo.Checkout(ctx, &storage.CheckoutRequest{
+		UserId: userId,
+		Items: []*storage.CartItem{
+			{Item: "book", Quantity: 1},
+			{Item: "pen", Quantity: 3},
+		},
+	})
+	require.NoError(s.T(), err)
+
+	s.T().Run("Orders keep the price paid", func(t *testing.T) {
+		_, err := s.repo.UpdateItemPrice(ctx, &storage.UpdateItemPriceRequest{Name: "cup", Price: 25})
+		require.NoError(t, err)
+
+		res, err := s.repo.GetOrders(ctx, &storage.GetOrdersRequest{UserId: userId, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 3, res.Total)
+		require.Len(t, res.Orders, 3)
+
+		cup := res.Orders[2]
+		assert.Equal(t, "cup", cup.Item)
+		assert.Equal(t, 20, cup.Price)
+		assert.Equal(t, 2, cup.Quantity)
+	})
+
+	s.T().Run("Orders are paginated", func(t *testing.T) {
+		res, err := s.repo.GetOrders(ctx, &storage.GetOrdersRequest{UserId: userId, Limit: 2, Offset: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 3, res.Total)
+		require.Len(t, res.Orders, 1)
+		assert.Equal(t, "cup", res.Orders[0].Item)
+	})
+
+	s.T().Run("Failed purchase leaves no order", func(t *testing.T) {
+		_, err := s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "pink-hoody", Quantity: 2})
+		require.ErrorIs(t, err, storage.ErrInsufficientFunds)
+
+		res, err := s.repo.GetOrders(ctx, &storage.GetOrdersRequest{UserId: userId, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 3, res.Total)
+	})
+}
+
+func (s *RepositoryTestSuite) TestReturnOrder() {
+	ctx := context.Background()
+	userId := uint64(1)
+	returnWindow := 14 * 24 * time.Hour
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES ($1, 'user1', 'password_hash', 100)`, userId)
+	require.NoError(s.T(), err)
+	_, err = s.repo.RestockItem(ctx, &storage.RestockItemRequest{Name: "cup", Amount: 5})
+	require.NoError(s.T(), err)
+	_, err = s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "cup", Quantity: 3})
+	require.NoError(s.T(), err)
+
+	var orderId uint64
+	err = conn.QueryRow(ctx, `SELECT id FROM orders WHERE user_id = $1`, userId).Scan(&orderId)
+	require.NoError(s.T(), err)
+
+	s.T().Run("Return part of the order", func(t *testing.T) {
+		_, err := s.repo.UpdateItemPrice(ctx, &storage.UpdateItemPriceRequest{Name: "cup", Price: 30})
+		require.NoError(t, err)
+
+		res, err := s.repo.ReturnOrder(ctx, &storage.ReturnOrderRequest{
+			UserId:       userId,
+			OrderId:      orderId,
+			Quantity:     2,
+			ReturnWindow: returnWindow,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 40, res.Amount)
+
+		balance, err := s.repo.GetBalance(ctx, userId)
+		require.NoError(t, err)
+		assert.Equal(t, 80, balance)
+
+		item, err := s.repo.GetItem(ctx, "cup")
+		require.NoError(t, err)
+		assert.Equal(t, 4, *item.Stock)
+
+		orders, err := s.repo.GetOrders(ctx, &storage.GetOrdersRequest{UserId: userId, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, orders.Orders, 1)
+		assert.Equal(t, 2, orders.Orders[0].ReturnedQuantity)
+		require.Len(t, orders.Orders[0].Refunds, 1)
+		assert.Equal(t, 40, orders.Orders[0].Refunds[0].Amount)
+
+		history, err := s.repo.GetHistory(ctx, &storage.GetHistoryRequest{UserId: userId, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, storage.DirectionReceived, history[0].Direction)
+		assert.Equal(t, "system account", history[0].FromUser)
+		assert.Equal(t, 40, history[0].Amount)
+		assert.Equal(t, fmt.Sprintf("refund of order %d", orderId), history[0].Memo)
+
+		coinHistory, err := s.repo.GetCoinHistory(ctx, userId)
+		require.NoError(t, err)
+		require.Len(t, coinHistory.Received, 1)
+		assert.Equal(t, 40, coinHistory.Received[0].Amount)
+	})
+
+	s.T().Run("Return the rest of the order", func(t *testing.T) {
+		res, err := s.repo.ReturnOrder(ctx, &storage.ReturnOrderRequest{
+			UserId:       userId,
+			OrderId:      orderId,
+			ReturnWindow: returnWindow,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 20, res.Amount)
+
+		inventory, err := s.repo.GetInventory(ctx, userId)
+		require.NoError(t, err)
+		assert.Empty(t, inventory)
+
+		_, err = s.repo.ReturnOrder(ctx, &storage.ReturnOrderRequest{
+			UserId:       userId,
+			OrderId:      orderId,
+			ReturnWindow: returnWindow,
+		})
+		require.ErrorIs(t, err, storage.ErrNothingToReturn)
+	})
+
+	s.T().Run("Return after the window", func(t *testing.T) {
+		_, err := s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "pen", Quantity: 1})
+		require.NoError(t, err)
+		var penOrderId uint64
+		err = conn.QueryRow(ctx, `UPDATE orders SET created_at = created_at - INTERVAL '15 days'
+				WHERE user_id = $1 AND item = 'pen' RETURNING id`, userId).Scan(&penOrderId)
+		require.NoError(t, err)
+
+		_, err = s.repo.ReturnOrder(ctx, &storage.ReturnOrderRequest{
+			UserId:       userId,
+			OrderId:      penOrderId,
+			ReturnWindow: returnWindow,
+		})
+		require.ErrorIs(t, err, storage.ErrReturnExpired)
+	})
+
+	s.T().Run("Return order of another user", func(t *testing.T) {
+		_, err := s.repo.ReturnOrder(ctx, &storage.ReturnOrderRequest{
+			UserId:       userId + 1,
+			OrderId:      orderId,
+			ReturnWindow: returnWindow,
+		})
+		require.ErrorIs(t, err, storage.ErrOrderNotFound)
+	})
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestRevokeToken() {
+	ctx := context.Background()
+
+	user, err := s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "logout_user",
+		PassHash: "hash",
+	})
+	require.NoError(s.T(), err)
+	err = s.repo.CreateRefreshToken(ctx, &storage.CreateRefreshTokenRequest{
+		UserId:    user.UserId,
+		TokenHash: "refresh",
+		FamilyId:  "family",
+		TTL:       time.Hour,
+	})
+	require.NoError(s.T(), err)
+
+	err = s.repo.RevokeToken(ctx, &storage.RevokeTokenRequest{
+		Jti:              "live",
+		UserId:           user.UserId,
+		ExpiresAt:        time.Now().Add(time.Hour),
+		RefreshTokenHash: "refresh",
+	})
+	require.NoError(s.T(), err)
+	err = s.repo.RevokeToken(ctx, &storage.RevokeTokenRequest{
+		Jti:       "expired",
+		UserId:    user.UserId,
+		ExpiresAt: time.Now().Add(-time.Hour),
+	})
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.RotateRefreshToken(ctx, &storage.RotateRefreshTokenRequest{
+		TokenHash:    "refresh",
+		NewTokenHash: "next",
+		TTL:          time.Hour,
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrRefreshTokenReused)
+
+	revocations, err := s.repo.GetRevocations(ctx)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), revocations.Tokens, 1)
+	assert.Equal(s.T(), "live", revocations.Tokens[0].Jti)
+
+	require.NoError(s.T(), s.repo.DeleteExpiredRevocations(ctx))
+	var count int
+	err = s.db.Pool().QueryRow(ctx, `SELECT count(*) FROM revoked_tokens`).Scan(&count)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, count)
+}
+
+func (s *RepositoryTestSuite) TestRevokeUserTokens() {
+	ctx := context.Background()
+
+	user, err := s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "revoked_user",
+		PassHash: "hash",
+	})
+	require.NoError(s.T(), err)
+	err = s.repo.CreateRefreshToken(ctx, &storage.CreateRefreshTokenRequest{
+		UserId:    user.UserId,
+		TokenHash: "refresh",
+		FamilyId:  "family",
+		TTL:       time.Hour,
+	})
+	require.NoError(s.T(), err)
+
+	revokedBefore := time.Now()
+	resp, err := s.repo.RevokeUserTokens(ctx, &storage.RevokeUserTokensRequest{
+		UserName:      "revoked_user",
+		RevokedBefore: revokedBefore,
+		TokenTTL:      15 * time.Minute,
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), user.UserId, resp.UserId)
+	assert.WithinDuration(s.T(), revokedBefore, resp.RevokedBefore, time.Microsecond)
+	assert.WithinDuration(s.T(), resp.RevokedBefore.Add(15*time.Minute), resp.ExpiresAt, time.Second)
+
+	_, err = s.repo.RotateRefreshToken(ctx, &storage.RotateRefreshTokenRequest{
+		TokenHash:    "refresh",
+		NewTokenHash: "next",
+		TTL:          time.Hour,
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrRefreshTokenReused)
+
+	revocations, err := s.repo.GetRevocations(ctx)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), revocations.Sessions, 1)
+	assert.Equal(s.T(), user.UserId, revocations.Sessions[0].UserId)
+
+	_, err = s.repo.RevokeUserTokens(ctx, &storage.RevokeUserTokensRequest{
+		UserName:      "unknown_user",
+		RevokedBefore: time.Now(),
+		TokenTTL:      15 * time.Minute,
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrUserNotFound)
+}
@@ -385,8 +385,9 @@ func (s *RepositoryTestSuite) TestBuyItem() {
 	prepare()
 
 	request := &storage.BuyItemRequest{
-		UserId: userId,
-		Item:   item,
+		UserId:   userId,
+		Item:     item,
+		Quantity: 1,
 	}
 
 	_, err := s.repo.BuyItem(ctx, request)
@@ -408,6 +409,78 @@ func (s *RepositoryTestSuite) TestBuyItem() {
 	clear()
 }
 
+func (s *RepositoryTestSuite) TestBuyItemQuantity() {
+	ctx := context.Background()
+	userId := uint64(1)
+
+	conn, err := s.db.Pool().Acquire(ctx)
+	require.NoError(s.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, `INSERT INTO users(id, username, password_hash, balance)
+			VALUES ($1, 'user1', 'password_hash', 250)`, userId)
+	require.NoError(s.T(), err)
+
+	s.T().Run("Buy several units", func(t *testing.T) {
+		_, err := s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "pen", Quantity: 20})
+		require.NoError(t, err)
+		_, err = s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "pen", Quantity: 3})
+		require.NoError(t, err)
+
+		var balance int
+		err = conn.QueryRow(ctx, `SELECT balance FROM users WHERE id = $1`, userId).Scan(&balance)
+		require.NoError(t, err)
+		assert.Equal(t, 20, balance)
+
+		var quantity int
+		err = conn.QueryRow(ctx, `SELECT quantity FROM inventory WHERE user_id = $1 AND item = 'pen'`, userId).Scan(&quantity)
+		require.NoError(t, err)
+		assert.Equal(t, 23, quantity)
+	})
+
+	s.T().Run("Buy more than the user can afford", func(t *testing.T) {
+		_, err := s.repo.BuyItem(ctx, &storage.BuyItemRequest{UserId: userId, Item: "cup", Quantity: 2})
+		require.ErrorIs(t, err, storage.ErrInsufficientFunds)
+
+		var balance int
+		err = conn.QueryRow(ctx, `SELECT balance FROM users WHERE id = $1`, userId).Scan(&balance)
+		require.NoError(t, err)
+		assert.Equal(t, 20, balance)
+
+		var count int
+		err = conn.QueryRow(ctx, `SELECT count(*) FROM inventory WHERE user_id = $1 AND item = 'cup'`, userId).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetItems() {
+	ctx := context.Background()
+
+	items, err := s.repo.GetItems(ctx)
+	require.NoError(s.T(), err)
+
+	assert.Len(s.T(), items, 10)
+	assert.Equal(s.T(), "book", items[0].Name)
+	assert.Equal(s.T(), 50, items[0].Price)
+}
+
+func (s *RepositoryTestSuite) TestGetItem() {
+	ctx := context.Background()
+
+	s.T().Run("Get existing item", func(t *testing.T) {
+		item, err := s.repo.GetItem(ctx, "pink-hoody")
+		require.NoError(t, err)
+		assert.Equal(t, "pink-hoody", item.Name)
+		assert.Equal(t, 500, item.Price)
+	})
+
+	s.T().Run("Get non-existing item", func(t *testing.T) {
+		item, err := s.repo.GetItem(ctx, "car")
+		require.ErrorIs(t, err, storage.ErrItemNotFound)
+		assert.Nil(t, item)
+	})
+}
+
 func TestRepositorySuite(t *testing.T) {
 	suite.Run(t, new(RepositoryTestSuite))
 }
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"time"
+)
+
+// authBlock returns how long logins for key stay blocked.
+func (s *RepositoryTestSuite) authBlock(key string) time.Duration {
+	var seconds float64
+	err := s.db.Pool().QueryRow(context.Background(),
+		`SELECT COALESCE(EXTRACT(EPOCH FROM MAX(blocked_until) - CURRENT_TIMESTAMP), 0)::FLOAT8
+				FROM auth_attempts
+				WHERE key = @key AND blocked_until > CURRENT_TIMESTAMP`,
+		pgx.NamedArgs{
+			"key": key,
+		},
+	).Scan(&seconds)
+	require.NoError(s.T(), err)
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (s *RepositoryTestSuite) TestAuthAttempts() {
+	ctx := context.Background()
+	request := &storage.CountAuthAttemptRequest{
+		Key:    "user:user1",
+		Free:   3,
+		Delays: []time.Duration{time.Minute, time.Hour},
+		Window: time.Hour,
+	}
+
+	for want := 1; want <= 3; want++ {
+		res, err := s.repo.CountAuthAttempt(ctx, request)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), want, res.Attempts)
+		assert.Zero(s.T(), res.RetryAfter)
+	}
+	block := s.authBlock("user:user1")
+	assert.Zero(s.T(), block)
+
+	// The attempt past the free ones goes through and blocks the key for the
+	// first delay, the next one is rejected and not counted.
+	res, err := s.repo.CountAuthAttempt(ctx, request)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 4, res.Attempts)
+	res, err = s.repo.CountAuthAttempt(ctx, request)
+	require.NoError(s.T(), err)
+	assert.Zero(s.T(), res.Attempts)
+	assert.InDelta(s.T(), time.Minute.Seconds(), res.RetryAfter.Seconds(), 5)
+
+	// Once the block is over, the next attempt gets the last delay.
+	_, err = s.db.Pool().Exec(ctx, `UPDATE auth_attempts SET blocked_until = CURRENT_TIMESTAMP`)
+	require.NoError(s.T(), err)
+	res, err = s.repo.CountAuthAttempt(ctx, request)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 5, res.Attempts)
+	block = s.authBlock("user:user1")
+	assert.InDelta(s.T(), time.Hour.Seconds(), block.Seconds(), 5)
+
+	require.NoError(s.T(), s.repo.ClearAuthAttempts(ctx, "user:user1"))
+	block = s.authBlock("user:user1")
+	assert.Zero(s.T(), block)
+	res, err = s.repo.CountAuthAttempt(ctx, request)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, res.Attempts)
+
+	_, err = s.db.Pool().Exec(ctx,
+		`UPDATE auth_attempts SET last_attempt_at = last_attempt_at - INTERVAL '2 hours'`)
+	require.NoError(s.T(), err)
+	res, err = s.repo.CountAuthAttempt(ctx, request)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, res.Attempts, "the count starts over after the window")
+
+	_, err = s.db.Pool().Exec(ctx,
+		`UPDATE auth_attempts SET last_attempt_at = last_attempt_at - INTERVAL '2 hours'`)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.repo.DeleteStaleAuthAttempts(ctx, time.Hour))
+	var count int
+	err = s.db.Pool().QueryRow(ctx, `SELECT count(*) FROM auth_attempts`).Scan(&count)
+	require.NoError(s.T(), err)
+	assert.Zero(s.T(), count)
+}
+
+func (s *RepositoryTestSuite) TestReleaseAuthAttempt() {
+	ctx := context.Background()
+	defer func() {
+		_, err := s.db.Pool().Exec(ctx, `DELETE FROM auth_attempts`)
+		require.NoError(s.T(), err)
+	}()
+	request := &storage.CountAuthAttemptRequest{
+		Key:    "ip:10.0.0.1",
+		Free:   1,
+		Delays: []time.Duration{time.Minute},
+		Window: time.Hour,
+	}
+
+	for want := 1; want <= 2; want++ {
+		res, err := s.repo.CountAuthAttempt(ctx, request)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), want, res.Attempts)
+	}
+	// The second attempt succeeded, it is taken back with its block.
+	err := s.repo.ReleaseAuthAttempt(ctx, &storage.ReleaseAuthAttemptRequest{Key: "ip:10.0.0.1", Free: 1})
+	require.NoError(s.T(), err)
+	block := s.authBlock("ip:10.0.0.1")
+	assert.Zero(s.T(), block)
+	res, err := s.repo.CountAuthAttempt(ctx, request)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, res.Attempts)
+}
+
+func (s *RepositoryTestSuite) TestCountAuthAttempt_Concurrent() {
+	ctx := context.Background()
+	defer func() {
+		_, err := s.db.Pool().Exec(ctx, `DELETE FROM auth_attempts`)
+		require.NoError(s.T(), err)
+	}()
+	request := &storage.CountAuthAttemptRequest{
+		Key:    "user:user1",
+		Free:   3,
+		Delays: []time.Duration{time.Minute},
+		Window: time.Hour,
+	}
+
+	const parallel = 20
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		admitted int
+		blocked  int
+	)
+	for range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := s.repo.CountAuthAttempt(ctx, request)
+			assert.NoError(s.T(), err)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if res.RetryAfter > 0 {
+				blocked++
+			} else {
+				admitted++
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The free attempts and the one that earns the block get through, the
+	// rest are rejected before the password would be checked.
+	assert.Equal(s.T(), request.Free+1, admitted)
+	assert.Equal(s.T(), parallel-request.Free-1, blocked)
+	var attempts int
+	err := s.db.Pool().QueryRow(ctx, `SELECT attempts FROM auth_attempts WHERE key = @key`, pgx.NamedArgs{
+		"key": request.Key,
+	}).Scan(&attempts)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), request.Free+1, attempts)
+}
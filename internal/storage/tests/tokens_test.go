@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestRefreshTokenRotation() {
+	ctx := context.Background()
+
+	user, err := s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "refresh_user",
+		PassHash: "hash",
+	})
+	require.NoError(s.T(), err)
+	err = s.repo.CreateRefreshToken(ctx, &storage.CreateRefreshTokenRequest{
+		UserId:    user.UserId,
+		TokenHash: "first",
+		FamilyId:  "family",
+		TTL:       time.Hour,
+	})
+	require.NoError(s.T(), err)
+
+	resp, err := s.repo.RotateRefreshToken(ctx, &storage.RotateRefreshTokenRequest{
+		TokenHash:    "first",
+		NewTokenHash: "second",
+		TTL:          time.Hour,
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), user.UserId, resp.UserId)
+
+	_, err = s.repo.RotateRefreshToken(ctx, &storage.RotateRefreshTokenRequest{
+		TokenHash:    "unknown",
+		NewTokenHash: "third",
+		TTL:          time.Hour,
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrRefreshTokenNotFound)
+
+	// Presenting the rotated token again revokes the whole family, including
+	// the token that replaced it.
+	_, err = s.repo.RotateRefreshToken(ctx, &storage.RotateRefreshTokenRequest{
+		TokenHash:    "first",
+		NewTokenHash: "third",
+		TTL:          time.Hour,
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrRefreshTokenReused)
+	_, err = s.repo.RotateRefreshToken(ctx, &storage.RotateRefreshTokenRequest{
+		TokenHash:    "second",
+		NewTokenHash: "third",
+		TTL:          time.Hour,
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrRefreshTokenReused)
+}
+
+func (s *RepositoryTestSuite) TestRefreshTokenExpired() {
+	ctx := context.Background()
+
+	user, err := s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "expired_user",
+		PassHash: "hash",
+	})
+	require.NoError(s.T(), err)
+	err = s.repo.CreateRefreshToken(ctx, &storage.CreateRefreshTokenRequest{
+		UserId:    user.UserId,
+		TokenHash: "expired",
+		FamilyId:  "family",
+		TTL:       -time.Second,
+	})
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.RotateRefreshToken(ctx, &storage.RotateRefreshTokenRequest{
+		TokenHash:    "expired",
+		NewTokenHash: "next",
+		TTL:          time.Hour,
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrRefreshTokenNotFound)
+}
+
+func (s *RepositoryTestSuite) TestDeleteExpiredRefreshTokens() {
+	ctx := context.Background()
+
+	user, err := s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "cleanup_user",
+		PassHash: "hash",
+	})
+	require.NoError(s.T(), err)
+	for _, token := range []struct {
+		hash, family string
+		ttl          time.Duration
+	}{
+		{"expired", "expired-family", -time.Second},
+		{"rotated", "live-family", -time.Second},
+		{"current", "live-family", time.Hour},
+	} {
+		err = s.repo.CreateRefreshToken(ctx, &storage.CreateRefreshTokenRequest{
+			UserId:    user.UserId,
+			TokenHash: token.hash,
+			FamilyId:  token.family,
+			TTL:       token.ttl,
+		})
+		require.NoError(s.T(), err)
+	}
+
+	require.NoError(s.T(), s.repo.DeleteExpiredRefreshTokens(ctx))
+
+	count := func(family string) int {
+		var count int
+		err := s.db.Pool().QueryRow(ctx,
+			`SELECT count(*) FROM refresh_tokens WHERE family_id = $1`, family).Scan(&count)
+		require.NoError(s.T(), err)
+		return count
+	}
+	assert.Equal(s.T(), 0, count("expired-family"))
+	// The expired token of a live family is kept to detect its reuse.
+	assert.Equal(s.T(), 2, count("live-family"))
+}
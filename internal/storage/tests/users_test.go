@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"context"
+	"github.com/azaliaz/avito-shop/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"time"
+)
+
+func (s *RepositoryTestSuite) TestRegister() {
+	ctx := context.Background()
+
+	resp, err := s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "new_user",
+		PassHash: "hash",
+	})
+	require.NoError(s.T(), err)
+	assert.Greater(s.T(), resp.UserId, uint64(0))
+
+	balance, err := s.repo.GetBalance(ctx, resp.UserId)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1000, balance)
+
+	_, err = s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "new_user",
+		PassHash: "other_hash",
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrUserExists)
+
+	creds, err := s.repo.GetCredentials(ctx, "new_user")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), resp.UserId, creds.UserId)
+	assert.Equal(s.T(), "hash", creds.PassHash)
+	assert.Equal(s.T(), "user", creds.Role)
+
+	_, err = s.repo.GetCredentials(ctx, "unknown_user")
+	assert.ErrorIs(s.T(), err, storage.ErrUserNotFound)
+}
+
+func (s *RepositoryTestSuite) TestSetUserRole() {
+	ctx := context.Background()
+
+	resp, err := s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "future_auditor",
+		PassHash: "hash",
+	})
+	require.NoError(s.T(), err)
+
+	res, err := s.repo.SetUserRole(ctx, &storage.SetUserRoleRequest{
+		UserName: "future_auditor",
+		Role:     "auditor",
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), resp.UserId, res.UserId)
+	assert.True(s.T(), res.Changed)
+
+	res, err = s.repo.SetUserRole(ctx, &storage.SetUserRoleRequest{
+		UserName: "future_auditor",
+		Role:     "auditor",
+	})
+	require.NoError(s.T(), err)
+	assert.False(s.T(), res.Changed)
+
+	user, err := s.repo.GetUserByName(ctx, "future_auditor")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "auditor", user.Role)
+
+	_, err = s.repo.SetUserRole(ctx, &storage.SetUserRoleRequest{
+		UserName: "future_auditor",
+		Role:     "root",
+	})
+	assert.Error(s.T(), err)
+
+	_, err = s.repo.SetUserRole(ctx, &storage.SetUserRoleRequest{
+		UserName: "unknown_user",
+		Role:     "admin",
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrUserNotFound)
+	_, err = s.repo.GetUserByName(ctx, "unknown_user")
+	assert.ErrorIs(s.T(), err, storage.ErrUserNotFound)
+}
+
+func (s *RepositoryTestSuite) TestChangePassword() {
+	ctx := context.Background()
+
+	user, err := s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "password_user",
+		PassHash: "hash",
+	})
+	require.NoError(s.T(), err)
+	err = s.repo.CreateRefreshToken(ctx, &storage.CreateRefreshTokenRequest{
+		UserId:    user.UserId,
+		TokenHash: "refresh",
+		FamilyId:  "family",
+		TTL:       time.Hour,
+	})
+	require.NoError(s.T(), err)
+
+	err = s.repo.ChangePassword(ctx, &storage.ChangePasswordRequest{
+		UserId:   user.UserId,
+		PassHash: "new_hash",
+	})
+	require.NoError(s.T(), err)
+
+	creds, err := s.repo.GetCredentials(ctx, "password_user")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "new_hash", creds.PassHash)
+	_, err = s.repo.RotateRefreshToken(ctx, &storage.RotateRefreshTokenRequest{
+		TokenHash:    "refresh",
+		NewTokenHash: "next",
+		TTL:          time.Hour,
+	})
+	assert.Error(s.T(), err, "refresh tokens issued before the change are revoked")
+
+	err = s.repo.ChangePassword(ctx, &storage.ChangePasswordRequest{
+		UserId:   user.UserId + 100,
+		PassHash: "new_hash",
+	})
+	assert.ErrorIs(s.T(), err, storage.ErrUserNotFound)
+}
+
+func (s *RepositoryTestSuite) TestUpdatePassHash() {
+	ctx := context.Background()
+
+	user, err := s.repo.Register(ctx, &storage.RegisterRequest{
+		UserName: "rehash_user",
+		PassHash: "old_hash",
+	})
+	require.NoError(s.T(), err)
+
+	err = s.repo.UpdatePassHash(ctx, &storage.UpdatePassHashRequest{
+		UserId:      user.UserId,
+		OldPassHash: "stale_hash",
+		PassHash:    "lost_hash",
+	})
+	require.NoError(s.T(), err)
+	creds, err := s.repo.GetCredentials(ctx, "rehash_user")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "old_hash", creds.PassHash, "a hash changed in the meantime is kept")
+
+	err = s.repo.UpdatePassHash(ctx, &storage.UpdatePassHashRequest{
+		UserId:      user.UserId,
+		OldPassHash: "old_hash",
+		PassHash:    "new_hash",
+	})
+	require.NoError(s.T(), err)
+	creds, err = s.repo.GetCredentials(ctx, "rehash_user")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "new_hash", creds.PassHash)
+}
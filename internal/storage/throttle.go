@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+// getAuthBlock returns how long logins stay blocked for the longest blocked
+// of keys, zero when none of them is blocked.
+func getAuthBlock(ctx context.Context, conn *pgxpool.Conn, keys []string) (time.Duration, error) {
+	var seconds float64
+	err := conn.QueryRow(ctx,
+		`SELECT COALESCE(EXTRACT(EPOCH FROM MAX(blocked_until) - CURRENT_TIMESTAMP), 0)::FLOAT8
+				FROM auth_attempts
+				WHERE key = ANY(@keys) AND blocked_until > CURRENT_TIMESTAMP`,
+		pgx.NamedArgs{
+			"keys": keys,
+		},
+	).Scan(&seconds)
+	if err != nil {
+		return 0, fmt.Errorf("error select auth block: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// CountAuthAttempt counts a login for key unless the key is blocked. The
+// count and the block it earns are written by one statement, so that of
+// parallel attempts only the free ones and one more get through.
+func (r *Service) CountAuthAttempt(ctx context.Context, request *CountAuthAttemptRequest) (*CountAuthAttemptResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	delays := make([]float64, 0, len(request.Delays))
+	for _, delay := range request.Delays {
+		delays = append(delays, delay.Seconds())
+	}
+	for {
+		var attempts int
+		err = conn.QueryRow(ctx,
+			`INSERT INTO auth_attempts AS a (key, attempts, last_attempt_at)
+					VALUES (@key, 1, CURRENT_TIMESTAMP)
+					ON CONFLICT (key) DO UPDATE
+						SET (attempts, last_attempt_at, blocked_until) = (
+							SELECT counted.attempts, CURRENT_TIMESTAMP,
+								CASE WHEN counted.attempts > @free THEN CURRENT_TIMESTAMP
+									+ (@delays::FLOAT8[])[LEAST(counted.attempts - @free, cardinality(@delays::FLOAT8[]))]
+									* INTERVAL '1 second'
+								END
+							FROM (SELECT CASE
+									WHEN a.last_attempt_at < CURRENT_TIMESTAMP - @window * INTERVAL '1 second' THEN 1
+									ELSE a.attempts + 1
+								END AS attempts) counted
+						)
+						WHERE a.blocked_until IS NULL OR a.blocked_until <= CURRENT_TIMESTAMP
+					RETURNING attempts`,
+			pgx.NamedArgs{
+				"key":    request.Key,
+				"free":   request.Free,
+				"delays": delays,
+				"window": request.Window.Seconds(),
+			},
+		).Scan(&attempts)
+		if err == nil {
+			return &CountAuthAttemptResponse{Attempts: attempts}, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("error upsert auth attempts: %w", err)
+		}
+		retryAfter, err := getAuthBlock(ctx, conn, []string{request.Key})
+		if err != nil {
+			return nil, err
+		}
+		if retryAfter > 0 {
+			return &CountAuthAttemptResponse{RetryAfter: retryAfter}, nil
+		}
+		// The block has ended in between, the attempt can be counted now.
+	}
+}
+
+// ReleaseAuthAttempt takes back an attempt of key that turned out to be a
+// successful login, along with the block it earned when it was the one past
+// the free attempts.
+func (r *Service) ReleaseAuthAttempt(ctx context.Context, request *ReleaseAuthAttemptRequest) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	_, err = conn.Exec(ctx,
+		`UPDATE auth_attempts
+				SET attempts = GREATEST(attempts - 1, 0),
+					blocked_until = CASE WHEN attempts - 1 <= @free THEN NULL ELSE blocked_until END
+				WHERE key = @key`,
+		pgx.NamedArgs{
+			"key":  request.Key,
+			"free": request.Free,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error update auth attempts: %w", err)
+	}
+	return nil
+}
+
+// ClearAuthAttempts forgets the attempts of key, it is called after a
+// successful login.
+func (r *Service) ClearAuthAttempts(ctx context.Context, key string) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	_, err = conn.Exec(ctx,
+		`DELETE FROM auth_attempts WHERE key = @key`,
+		pgx.NamedArgs{
+			"key": key,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error delete auth attempts: %w", err)
+	}
+	return nil
+}
+
+// DeleteStaleAuthAttempts drops the keys that are not blocked and have not
+// been tried within the window, their count would start over anyway.
+func (r *Service) DeleteStaleAuthAttempts(ctx context.Context, window time.Duration) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	_, err = conn.Exec(ctx,
+		`DELETE FROM auth_attempts
+				WHERE last_attempt_at < CURRENT_TIMESTAMP - @window * INTERVAL '1 second'
+					AND (blocked_until IS NULL OR blocked_until <= CURRENT_TIMESTAMP)`,
+		pgx.NamedArgs{
+			"window": window.Seconds(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error delete stale auth attempts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+)
+
+func (r *Service) CreateRefreshToken(ctx context.Context, request *CreateRefreshTokenRequest) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	_, err = tx.Exec(ctx,
+		`INSERT INTO refresh_tokens(user_id, token_hash, family_id, expires_at)
+				VALUES (@user_id, @token_hash, @family_id, CURRENT_TIMESTAMP + @ttl * INTERVAL '1 second')`,
+		pgx.NamedArgs{
+			"user_id":    request.UserId,
+			"token_hash": request.TokenHash,
+			"family_id":  request.FamilyId,
+			"ttl":        request.TTL.Seconds(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error insert refresh token: %w", err)
+	}
+	if request.Login {
+		err = r.audit(ctx, tx, &AuditEvent{
+			ActorId: request.UserId,
+			Action:  AuditLogin,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one of the same
+// family. Presenting a token that was already rotated means it has leaked, so
+// the whole family is revoked and ErrRefreshTokenReused is returned.
+func (r *Service) RotateRefreshToken(ctx context.Context, request *RotateRefreshTokenRequest) (*RotateRefreshTokenResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	var id uint64
+	var userId uint64
+	var familyId string
+	var role string
+	var expired, spent bool
+	err = tx.QueryRow(ctx,
+		`SELECT t.id, t.user_id, t.family_id, u.role,
+					t.expires_at <= CURRENT_TIMESTAMP,
+					t.used_at IS NOT NULL OR t.revoked_at IS NOT NULL
+				FROM refresh_tokens t
+				JOIN users u ON u.id = t.user_id
+				WHERE t.token_hash = @token_hash
+				FOR UPDATE OF t`,
+		pgx.NamedArgs{
+			"token_hash": request.TokenHash,
+		},
+	).Scan(&id, &userId, &familyId, &role, &expired, &spent)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error get refresh token: %w", err)
+	}
+	if spent {
+		_, err = tx.Exec(ctx,
+			`UPDATE refresh_tokens
+					SET revoked_at = CURRENT_TIMESTAMP
+					WHERE family_id = @family_id AND revoked_at IS NULL`,
+			pgx.NamedArgs{
+				"family_id": familyId,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error revoke refresh token family: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if expired {
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE refresh_tokens
+				SET used_at = CURRENT_TIMESTAMP
+				WHERE id = @id`,
+		pgx.NamedArgs{
+			"id": id,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error mark refresh token used: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO refresh_tokens(user_id, token_hash, family_id, expires_at)
+				VALUES (@user_id, @token_hash, @family_id, CURRENT_TIMESTAMP + @ttl * INTERVAL '1 second')`,
+		pgx.NamedArgs{
+			"user_id":    userId,
+			"token_hash": request.NewTokenHash,
+			"family_id":  familyId,
+			"ttl":        request.TTL.Seconds(),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error insert refresh token: %w", err)
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		ActorId: userId,
+		Action:  AuditRefresh,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RotateRefreshTokenResponse{
+		UserId: userId,
+		Role:   role,
+	}, tx.Commit(ctx)
+}
+
+// DeleteExpiredRefreshTokens drops the families whose newest token has
+// expired. Such a family can't be rotated any more, so its spent tokens are
+// no longer needed to detect a reuse.
+func (r *Service) DeleteExpiredRefreshTokens(ctx context.Context) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	_, err = conn.Exec(ctx,
+		`DELETE FROM refresh_tokens
+				WHERE family_id IN (
+					SELECT family_id
+						FROM refresh_tokens
+						GROUP BY family_id
+						HAVING max(expires_at) <= CURRENT_TIMESTAMP
+				)`,
+	)
+	if err != nil {
+		return fmt.Errorf("error delete expired refresh tokens: %w", err)
+	}
+	return nil
+}
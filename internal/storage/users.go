@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log/slog"
+)
+
+func (r *Service) Register(ctx context.Context, request *RegisterRequest) (*RegisterResponse, error) {
+	if request.PassHash == "" {
+		return nil, errors.New("password cannot be empty")
+	}
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	var userId uint64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO users(username, password_hash, balance)
+				VALUES (@username, @password_hash, 0)
+				RETURNING id`,
+		pgx.NamedArgs{
+			"username":      request.UserName,
+			"password_hash": request.PassHash,
+		},
+	).Scan(&userId)
+	if isUniqueViolation(err) {
+		return nil, ErrUserExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating user: %w", err)
+	}
+	err = r.postEntry(ctx, tx, &ledgerEntry{
+		Kind:   entryGrant,
+		Debit:  userAccount(userId),
+		Credit: issuanceAccount,
+		Amount: initialGrant,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error grant initial coins: %w", err)
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		ActorId: userId,
+		Action:  AuditRegister,
+		Target:  request.UserName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegisterResponse{
+		UserId: userId,
+	}, tx.Commit(ctx)
+}
+
+func (r *Service) GetCredentials(ctx context.Context, username string) (*AuthResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	var resp AuthResponse
+	err = conn.QueryRow(ctx,
+		`SELECT id, username, password_hash, role
+				FROM users
+				WHERE username = @username`,
+		pgx.NamedArgs{
+			"username": username,
+		},
+	).Scan(&resp.UserId, &resp.UserName, &resp.PassHash, &resp.Role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (r *Service) GetUserByName(ctx context.Context, username string) (*User, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	var user User
+	err = conn.QueryRow(ctx,
+		`SELECT id, username, role
+				FROM users
+				WHERE username = @username`,
+		pgx.NamedArgs{
+			"username": username,
+		},
+	).Scan(&user.UserId, &user.UserName, &user.Role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *Service) SetUserRole(ctx context.Context, request *SetUserRoleRequest) (*SetUserRoleResponse, error) {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	var resp SetUserRoleResponse
+	var role string
+	err = tx.QueryRow(ctx,
+		`SELECT id, role
+				FROM users
+				WHERE username = @username
+				FOR UPDATE`,
+		pgx.NamedArgs{
+			"username": request.UserName,
+		},
+	).Scan(&resp.UserId, &role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error get user: %w", err)
+	}
+	if role == request.Role {
+		return &resp, nil
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE users
+				SET role = @role
+				WHERE id = @user_id`,
+		pgx.NamedArgs{
+			"role":    request.Role,
+			"user_id": resp.UserId,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error update role: %w", err)
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		Action:  AuditSetUserRole,
+		Target:  request.UserName,
+		Details: map[string]any{"role": request.Role, "previousRole": role},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Changed = true
+	return &resp, tx.Commit(ctx)
+}
+
+// ChangePassword stores a new password hash and revokes all of the user's
+// refresh tokens, so that other sessions end once their access tokens expire.
+func (r *Service) ChangePassword(ctx context.Context, request *ChangePasswordRequest) error {
+	if request.PassHash == "" {
+		return errors.New("password cannot be empty")
+	}
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			r.logger.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+	tag, err := tx.Exec(ctx,
+		`UPDATE users
+				SET password_hash = @password_hash
+				WHERE id = @user_id AND NOT is_system`,
+		pgx.NamedArgs{
+			"password_hash": request.PassHash,
+			"user_id":       request.UserId,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error update password: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE refresh_tokens
+				SET revoked_at = CURRENT_TIMESTAMP
+				WHERE user_id = @user_id AND revoked_at IS NULL`,
+		pgx.NamedArgs{
+			"user_id": request.UserId,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error revoke refresh tokens: %w", err)
+	}
+	err = r.audit(ctx, tx, &AuditEvent{
+		ActorId: request.UserId,
+		Action:  AuditChangePassword,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdatePassHash replaces a password hash with an equivalent one, such as a
+// hash with stronger parameters. Nothing is changed if the stored hash is no
+// longer OldPassHash, the password was changed in the meantime then.
+func (r *Service) UpdatePassHash(ctx context.Context, request *UpdatePassHashRequest) error {
+	conn, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	_, err = conn.Exec(ctx,
+		`UPDATE users
+				SET password_hash = @password_hash
+				WHERE id = @user_id AND password_hash = @old_password_hash`,
+		pgx.NamedArgs{
+			"password_hash":     request.PassHash,
+			"old_password_hash": request.OldPassHash,
+			"user_id":           request.UserId,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error update password hash: %w", err)
+	}
+	return nil
+}
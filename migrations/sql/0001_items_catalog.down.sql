@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_pkey;
+ALTER TABLE items ALTER COLUMN name DROP NOT NULL;
+
+COMMIT;
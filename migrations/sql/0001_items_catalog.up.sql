@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE items ADD PRIMARY KEY (name);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS item_prices;
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_price_check;
+ALTER TABLE items DROP COLUMN IF EXISTS retired_at;
+
+COMMIT;
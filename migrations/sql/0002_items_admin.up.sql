@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE items ADD COLUMN retired_at TIMESTAMP;
+ALTER TABLE items ADD CONSTRAINT items_price_check CHECK (price > 0);
+
+CREATE TABLE item_prices (
+                             id SERIAL PRIMARY KEY,
+                             item TEXT NOT NULL REFERENCES items(name) ON UPDATE CASCADE ON DELETE CASCADE,
+                             price INT NOT NULL CHECK (price > 0),
+                             valid_from TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX item_prices_item_valid_from_idx ON item_prices (item, valid_from);
+
+INSERT INTO item_prices (item, price)
+SELECT name, price FROM items;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE items ADD COLUMN stock INT CHECK (stock >= 0);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE orders (
+    id SERIAL PRIMARY KEY,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    item TEXT NOT NULL REFERENCES items(name) ON UPDATE CASCADE,
+    price INT NOT NULL CHECK (price > 0),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX orders_user_id_created_at_idx ON orders (user_id, created_at);
+
+COMMIT;
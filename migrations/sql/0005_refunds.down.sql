@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS refunds;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_returned_quantity_check;
+ALTER TABLE orders DROP COLUMN IF EXISTS returned_quantity;
+
+COMMIT;
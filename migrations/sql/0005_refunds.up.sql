@@ -0,0 +1,18 @@
+BEGIN;
+
+ALTER TABLE orders ADD COLUMN returned_quantity INT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD CONSTRAINT orders_returned_quantity_check
+    CHECK (returned_quantity >= 0 AND returned_quantity <= quantity);
+
+CREATE TABLE refunds (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    amount INT NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX refunds_user_id_created_at_idx ON refunds (user_id, created_at);
+
+COMMIT;
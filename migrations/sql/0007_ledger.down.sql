@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE users ALTER COLUMN balance SET DEFAULT 1000;
+
+DROP VIEW IF EXISTS ledger_balances;
+DROP TABLE IF EXISTS ledger_entries;
+DROP FUNCTION IF EXISTS ledger_entries_immutable();
+
+COMMIT;
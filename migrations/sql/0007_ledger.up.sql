@@ -0,0 +1,43 @@
+BEGIN;
+
+CREATE TABLE ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('grant', 'transfer', 'purchase', 'refund', 'adjustment')),
+    debit_account TEXT NOT NULL,
+    credit_account TEXT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    reference TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (debit_account <> credit_account)
+);
+CREATE INDEX ledger_entries_debit_account_idx ON ledger_entries (debit_account, created_at);
+CREATE INDEX ledger_entries_credit_account_idx ON ledger_entries (credit_account, created_at);
+
+CREATE FUNCTION ledger_entries_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger entries are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entries_immutable
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_entries_immutable();
+
+CREATE VIEW ledger_balances AS
+SELECT account, sum(amount)::BIGINT AS balance
+FROM (
+    SELECT debit_account AS account, amount FROM ledger_entries
+    UNION ALL
+    SELECT credit_account AS account, -amount FROM ledger_entries
+) AS movements
+GROUP BY account;
+
+-- Balances that existed before the ledger are recorded as opening grants.
+INSERT INTO ledger_entries (kind, debit_account, credit_account, amount, reference)
+SELECT 'grant', 'user:' || id, 'system:issuance', balance, 'opening-balance'
+FROM users
+WHERE balance > 0;
+
+ALTER TABLE users ALTER COLUMN balance SET DEFAULT 0;
+
+COMMIT;
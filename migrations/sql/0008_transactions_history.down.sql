@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS transactions_to_user_id_created_at_idx;
+DROP INDEX IF EXISTS transactions_from_user_id_created_at_idx;
+
+COMMIT;
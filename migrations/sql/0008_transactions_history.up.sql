@@ -0,0 +1,6 @@
+BEGIN;
+
+CREATE INDEX transactions_from_user_id_created_at_idx ON transactions (from_user_id, created_at);
+CREATE INDEX transactions_to_user_id_created_at_idx ON transactions (to_user_id, created_at);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE transactions ADD COLUMN memo TEXT CHECK (char_length(memo) <= 200);
+
+COMMIT;
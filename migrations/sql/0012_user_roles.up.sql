@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'auditor', 'admin'));
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Balances taken below zero by forced debits are left as they are.
+ALTER TABLE users DROP CONSTRAINT users_balance_check;
+ALTER TABLE users ADD CONSTRAINT users_balance_check CHECK (balance >= 0) NOT VALID;
+ALTER TABLE users DROP COLUMN IF EXISTS allow_negative;
+
+DELETE FROM users WHERE is_system;
+ALTER TABLE users DROP COLUMN IF EXISTS is_system;
+
+COMMIT;
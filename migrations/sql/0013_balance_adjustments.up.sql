@@ -0,0 +1,19 @@
+BEGIN;
+
+-- System users own transfers that don't come from a person, they cannot log in
+-- and don't take part in ordinary transfers. The id is taken outside of the
+-- sequence so that it doesn't shift the ids of real users. The name contains a
+-- space, which no username can, so that it can't clash with an existing user.
+ALTER TABLE users ADD COLUMN is_system BOOLEAN NOT NULL DEFAULT false;
+
+INSERT INTO users (id, username, password_hash, balance, is_system)
+VALUES (0, 'system account', '!', 0, true);
+
+-- A balance stays non-negative unless a forced debit took it below zero, such
+-- a debit sets allow_negative, which is cleared again by any other debit and
+-- by the credit that brings the balance back to zero.
+ALTER TABLE users ADD COLUMN allow_negative BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users DROP CONSTRAINT users_balance_check;
+ALTER TABLE users ADD CONSTRAINT users_balance_check CHECK (balance >= 0 OR allow_negative);
+
+COMMIT;
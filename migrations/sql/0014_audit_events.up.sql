@@ -0,0 +1,32 @@
+BEGIN;
+
+-- actor_id has no foreign key so that nothing, not even removing a user, ever
+-- has to touch an event after it is written.
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INT,
+    action TEXT NOT NULL,
+    target TEXT,
+    ip TEXT,
+    outcome TEXT NOT NULL CHECK (outcome IN ('success', 'failure')),
+    details JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at, id);
+CREATE INDEX audit_events_actor_id_created_at_idx ON audit_events (actor_id, created_at);
+
+CREATE FUNCTION audit_events_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit events are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_immutable
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_immutable();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_immutable();
+
+COMMIT;
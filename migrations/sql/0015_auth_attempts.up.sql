@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Logins are counted per key, "user:<name>" or "ip:<address>", so that the
+-- backoff survives restarts and is shared by all instances. Every login is
+-- counted before the password is checked, so that parallel attempts can't all
+-- get past the limit, a successful one is taken back.
+CREATE TABLE auth_attempts (
+    key TEXT PRIMARY KEY,
+    attempts INT NOT NULL,
+    last_attempt_at TIMESTAMPTZ NOT NULL,
+    blocked_until TIMESTAMPTZ
+);
+CREATE INDEX auth_attempts_last_attempt_at_idx ON auth_attempts (last_attempt_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE refunds DROP CONSTRAINT IF EXISTS refunds_transaction_id_fkey;
+DELETE FROM transactions WHERE id IN (SELECT transaction_id FROM refunds);
+ALTER TABLE refunds DROP COLUMN IF EXISTS transaction_id;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- A refund is paid by the system user, like an adjustment, so that it shows up
+-- in the transfer history. The transfers of earlier refunds are added here with
+-- ids taken from the sequence before the rows exist.
+ALTER TABLE refunds ADD COLUMN transaction_id INT;
+
+UPDATE refunds SET transaction_id = nextval(pg_get_serial_sequence('transactions', 'id'));
+
+INSERT INTO transactions (id, from_user_id, to_user_id, amount, memo, created_at)
+SELECT refunds.transaction_id, users.id, refunds.user_id, refunds.amount,
+       'refund of order ' || refunds.order_id, refunds.created_at
+FROM refunds
+JOIN users ON users.is_system;
+
+ALTER TABLE refunds ADD CONSTRAINT refunds_transaction_id_fkey
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id);
+
+COMMIT;
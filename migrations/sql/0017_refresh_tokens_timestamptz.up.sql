@@ -0,0 +1,11 @@
+BEGIN;
+
+-- The timestamps were written in the session time zone, which is also the one
+-- the conversion reads them in, like the other token tables they keep the zone.
+ALTER TABLE refresh_tokens
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN used_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN revoked_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+COMMIT;
@@ -0,0 +1,5 @@
+// Package shoppb holds the gRPC contract of the shop, generated from
+// shop.proto, for the server and for the services that call it.
+package shoppb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative shop.proto